@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/aarondever/notiflow/internal"
 	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/tracing"
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,6 +36,13 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Setup tracing before the app so the database client and servers report to it
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		slog.Error("Failed to setup tracing", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize app with all dependencies via Wire
 	app, err := internal.InitializeApp(cfg)
 	if err != nil {
@@ -53,23 +63,50 @@ func main() {
 			os.Exit(1)
 		}
 
-		slog.Info("Starting gRPC server", "address", grpcAddr)
+		slog.Info("Starting gRPC server", "address", grpcAddr, "tls", app.TLSConfig != nil)
 		if err := app.GRPCServer.Serve(lis); err != nil {
 			slog.Error("gRPC server failed", "error", err)
 			os.Exit(1)
 		}
 	}()
 
-	// Start HTTP server in a goroutine
-	httpAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
+	// Start HTTP server in a goroutine, the certificate comes from the TLS config
+	httpSrv := &http.Server{
+		Addr:      fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+		Handler:   app.Router,
+		TLSConfig: app.TLSConfig,
+	}
 	go func() {
-		slog.Info("Starting HTTP server", "address", httpAddr)
-		if err := app.Router.Run(httpAddr); err != nil {
+		slog.Info("Starting HTTP server", "address", httpSrv.Addr, "tls", app.TLSConfig != nil)
+
+		var err error
+		if app.TLSConfig != nil {
+			err = httpSrv.ListenAndServeTLS("", "")
+		} else {
+			err = httpSrv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slog.Error("HTTP server failed", "error", err)
 			os.Exit(1)
 		}
 	}()
 
+	// Start inbound SMTP server in a goroutine, when enabled
+	if app.SMTPServer != nil {
+		go func() {
+			slog.Info("Starting inbound SMTP server", "address", app.SMTPServer.Addr)
+			if err := app.SMTPServer.ListenAndServe(); err != nil {
+				slog.Error("Inbound SMTP server failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// Start background workers, they stop when ctx is cancelled
+	for _, worker := range app.Workers {
+		go worker.Run(ctx)
+	}
+
 	// Wait for interrupt signal
 	<-ctx.Done()
 	slog.Info("Shutting down servers...")
@@ -78,13 +115,42 @@ func main() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Stop gRPC server
-	app.GRPCServer.GracefulStop()
+	// Stop gRPC server alongside HTTP, letting calls in flight finish
+	grpcStopped := make(chan struct{})
+	go func() {
+		app.GRPCServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	// Stop HTTP server, letting requests in flight finish. Event streams don't end on their own and
+	// are cut off at the timeout.
+	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error shutting down HTTP server", "error", err)
+		httpSrv.Close()
+	}
+
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		slog.Warn("gRPC calls still running at the shutdown timeout, cancelling them")
+		app.GRPCServer.Stop()
+		<-grpcStopped
+	}
+
+	// Stop inbound SMTP server
+	if app.SMTPServer != nil {
+		app.SMTPServer.Close()
+	}
 
 	// Disconnect from database
 	if err := app.DB.Mongo.Disconnect(shutdownCtx); err != nil {
 		slog.Error("Error disconnecting from database", "error", err)
 	}
 
+	// Flush spans that haven't been exported yet
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Error shutting down tracing", "error", err)
+	}
+
 	slog.Info("Servers stopped gracefully")
 }
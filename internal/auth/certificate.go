@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"slices"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+// CertificateMapper maps the identities of verified client certificates to principals, so clients of
+// the zero-trust network authenticate with their certificate instead of an API key or JWT
+type CertificateMapper struct {
+	identities []config.TLSClientIdentity
+}
+
+func NewCertificateMapper(cfg config.TLSConfig) (*CertificateMapper, error) {
+	for _, identity := range cfg.ClientIdentities {
+		if identity.Subject == "" {
+			return nil, fmt.Errorf("TLS client identity without a subject")
+		}
+		for _, scope := range identity.Scopes {
+			switch models.APIKeyScope(scope) {
+			case models.ScopeSend, models.ScopeRead, models.ScopeAdmin:
+			default:
+				return nil, fmt.Errorf("TLS client identity %s: unknown scope %q", identity.Subject, scope)
+			}
+		}
+	}
+	if len(cfg.ClientIdentities) > 0 && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("TLS client identities need a client CA file")
+	}
+
+	return &CertificateMapper{identities: cfg.ClientIdentities}, nil
+}
+
+// Principal returns the principal of the first identity matching the client certificate, or nil
+// when the connection has no verified certificate or no identity matches
+func (m *CertificateMapper) Principal(state *tls.ConnectionState) *models.Principal {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	certificate := state.VerifiedChains[0][0]
+
+	for _, identity := range m.identities {
+		if !certificateHasSubject(certificate, identity.Subject) {
+			continue
+		}
+
+		principal := &models.Principal{
+			Kind:   models.PrincipalCertificate,
+			ID:     identity.Subject,
+			Name:   identity.Name,
+			Tenant: identity.Tenant,
+		}
+		if principal.Name == "" {
+			principal.Name = identity.Subject
+		}
+		for _, scope := range identity.Scopes {
+			principal.Scopes = append(principal.Scopes, models.APIKeyScope(scope))
+		}
+
+		return principal
+	}
+
+	return nil
+}
+
+// certificateHasSubject reports whether subject is one of the certificate's URI, DNS or email SANs
+// or its common name
+func certificateHasSubject(certificate *x509.Certificate, subject string) bool {
+	for _, uri := range certificate.URIs {
+		if uri.String() == subject {
+			return true
+		}
+	}
+
+	return slices.Contains(certificate.DNSNames, subject) ||
+		slices.Contains(certificate.EmailAddresses, subject) ||
+		certificate.Subject.CommonName == subject
+}
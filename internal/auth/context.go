@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns ctx carrying the identity the request was authenticated as
+func WithPrincipal(ctx context.Context, principal *models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the identity of the request, nil when authentication is disabled
+func PrincipalFromContext(ctx context.Context) *models.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*models.Principal)
+	return principal
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Fetches are attempted at most this often, so forged key IDs or an unreachable issuer don't turn
+// every request into a JWKS fetch
+const jwksRetryInterval = 10 * time.Second
+
+// jwksCache holds the signing keys published at a JWKS URL. Keys are refreshed every refresh interval
+// and early when a token names a key ID that isn't cached yet, which is how issuers rotate keys.
+// The previous keys are kept when a refresh fails.
+type jwksCache struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]any // Key ID to *rsa.PublicKey or *ecdsa.PublicKey
+	fetchedAt time.Time      // Last successful fetch
+	triedAt   time.Time      // Last fetch attempt
+}
+
+func newJWKSCache(url string, refresh time.Duration) *jwksCache {
+	return &jwksCache{
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// lookup returns the public key with ID kid, or every cached key when kid is empty
+func (c *jwksCache) lookup(ctx context.Context, kid string) ([]any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	_, known := c.keys[kid]
+	due := c.fetchedAt.IsZero() || now.Sub(c.fetchedAt) >= c.refresh || (kid != "" && !known)
+	if due && now.Sub(c.triedAt) >= jwksRetryInterval {
+		c.triedAt = now
+		keys, err := c.fetch(ctx)
+		if err != nil {
+			slog.Error("Failed to refresh JWKS", "error", err, "url", c.url)
+		} else {
+			c.keys, c.fetchedAt = keys, now
+		}
+	}
+	if c.keys == nil {
+		return nil, errors.New("JWKS unavailable")
+	}
+
+	if kid != "" {
+		if key, ok := c.keys[kid]; ok {
+			return []any{key}, nil
+		}
+		return nil, nil
+	}
+
+	keys := make([]any, 0, len(c.keys))
+	for _, key := range c.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// jsonWebKey holds the members of RSA and EC keys (RFC 7517, 7518)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (c *jwksCache) fetch(ctx context.Context) (map[string]any, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS responded with %s", response.Status)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Issuers may publish key types we don't verify with, the others still work
+			slog.Warn("Skipping JWKS key", "error", err, "kid", jwk.Kid, "kty", jwk.Kty)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 coordinates")
+		}
+
+		// Parsing the uncompressed point rejects coordinates that aren't on the curve
+		if _, err = ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(decoded), nil
+}
@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// staticKey is a configured verification key, *rsa.PublicKey, *ecdsa.PublicKey or an HS256 secret
+type staticKey struct {
+	id  string
+	key any
+}
+
+// JWTVerifier validates bearer JWTs issued by the platform and maps their claims to a principal
+type JWTVerifier struct {
+	cfg    config.JWTConfig
+	keys   []staticKey
+	jwks   *jwksCache // nil without a JWKS URL
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(cfg config.JWTConfig) (*JWTVerifier, error) {
+	verifier := &JWTVerifier{cfg: cfg}
+
+	for _, keyConfig := range cfg.Keys {
+		key, err := loadStaticKey(keyConfig)
+		if err != nil {
+			slog.Error("Failed to load JWT verification key", "error", err, "id", keyConfig.ID)
+			return nil, err
+		}
+		verifier.keys = append(verifier.keys, staticKey{id: keyConfig.ID, key: key})
+	}
+
+	if cfg.JWKSURL != "" {
+		verifier.jwks = newJWKSCache(cfg.JWKSURL, time.Duration(cfg.JWKSRefresh)*time.Second)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "ES256", "HS256"}),
+		jwt.WithLeeway(time.Duration(cfg.Leeway) * time.Second),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	verifier.parser = jwt.NewParser(options...)
+
+	if verifier.Enabled() {
+		slog.Info("JWT authentication enabled", "jwks_url", cfg.JWKSURL, "static_keys", len(verifier.keys), "issuer", cfg.Issuer)
+	}
+
+	return verifier, nil
+}
+
+// Enabled reports whether any verification key is configured
+func (v *JWTVerifier) Enabled() bool {
+	return len(v.keys) > 0 || v.jwks != nil
+}
+
+// IsJWT reports whether a bearer token is shaped like a JWT rather than an API key
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Verify checks the token's signature and registered claims and returns its principal, with the
+// tenant and scopes read from the configured claims. Fails with ErrUnauthenticated.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		return v.verificationKeys(ctx, token)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid bearer token: %w", types.ErrUnauthenticated, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: bearer token has no subject", types.ErrUnauthenticated)
+	}
+	tenant, _ := claims[v.cfg.TenantClaim].(string)
+
+	return &models.Principal{
+		Kind:   models.PrincipalJWT,
+		ID:     subject,
+		Name:   subject,
+		Tenant: tenant,
+		Scopes: v.scopes(claims[v.cfg.ScopeClaim]),
+	}, nil
+}
+
+// verificationKeys returns the keys that may have signed token: those matching its kid header, or
+// all of them when it has none, limited to the key type of its algorithm so a public key can never
+// be used as an HMAC secret
+func (v *JWTVerifier) verificationKeys(ctx context.Context, token *jwt.Token) (jwt.VerificationKeySet, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	var candidates []any
+	for _, key := range v.keys {
+		if kid == "" || key.id == "" || key.id == kid {
+			candidates = append(candidates, key.key)
+		}
+	}
+	if v.jwks != nil {
+		keys, err := v.jwks.lookup(ctx, kid)
+		if err != nil && len(candidates) == 0 {
+			return jwt.VerificationKeySet{}, err
+		}
+		candidates = append(candidates, keys...)
+	}
+
+	var set jwt.VerificationKeySet
+	for _, key := range candidates {
+		switch key.(type) {
+		case []byte:
+			if token.Method == jwt.SigningMethodHS256 {
+				set.Keys = append(set.Keys, key)
+			}
+		case *rsa.PublicKey:
+			if token.Method == jwt.SigningMethodRS256 {
+				set.Keys = append(set.Keys, key)
+			}
+		case *ecdsa.PublicKey:
+			if token.Method == jwt.SigningMethodES256 {
+				set.Keys = append(set.Keys, key)
+			}
+		}
+	}
+	if len(set.Keys) == 0 {
+		return set, fmt.Errorf("no %s key with ID %q", token.Method.Alg(), kid)
+	}
+
+	return set, nil
+}
+
+// scopes maps the scope claim, a space-separated string or an array, to the scopes it grants
+func (v *JWTVerifier) scopes(claim any) []models.APIKeyScope {
+	var values []string
+	switch claim := claim.(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []any:
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	var scopes []models.APIKeyScope
+	for _, value := range values {
+		value, ok := strings.CutPrefix(value, v.cfg.ScopePrefix)
+		if !ok {
+			continue
+		}
+
+		switch scope := models.APIKeyScope(value); scope {
+		case models.ScopeSend, models.ScopeRead, models.ScopeAdmin:
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
+
+func loadStaticKey(keyConfig config.JWTKeyConfig) (any, error) {
+	if keyConfig.Secret != "" {
+		return []byte(keyConfig.Secret), nil
+	}
+
+	data, err := os.ReadFile(keyConfig.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", keyConfig.PublicKeyFile)
+	}
+
+	var key any
+	switch block.Type {
+	case "CERTIFICATE":
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = certificate.PublicKey
+	default:
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return key, nil
+	case *ecdsa.PublicKey:
+		if key.Curve.Params().Name != "P-256" {
+			return nil, fmt.Errorf("%s: ES256 needs a P-256 key", keyConfig.PublicKeyFile)
+		}
+		return key, nil
+	default:
+		return nil, errors.New(keyConfig.PublicKeyFile + ": unsupported public key type")
+	}
+}
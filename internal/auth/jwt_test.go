@@ -0,0 +1,310 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer   = "https://issuer.example"
+	testAudience = "notiflow"
+	testSecret   = "test-shared-secret-of-at-least-32-bytes"
+)
+
+// testJWKS serves a JWKS whose keys tests may replace, counting the fetches
+type testJWKS struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	keys    []jsonWebKey
+	fetches atomic.Int32
+}
+
+func newTestJWKS(t *testing.T, keys ...jsonWebKey) *testJWKS {
+	t.Helper()
+
+	jwks := &testJWKS{keys: keys}
+	jwks.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwks.fetches.Add(1)
+
+		jwks.mu.Lock()
+		defer jwks.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": jwks.keys})
+	}))
+	t.Cleanup(jwks.Close)
+
+	return jwks
+}
+
+func (jwks *testJWKS) publish(keys ...jsonWebKey) {
+	jwks.mu.Lock()
+	defer jwks.mu.Unlock()
+	jwks.keys = keys
+}
+
+func rsaJWK(t *testing.T, kid string) (*rsa.PrivateKey, jsonWebKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key, jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(t *testing.T, kid string) (*ecdsa.PrivateKey, jsonWebKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key, jsonWebKey{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func newTestVerifier(t *testing.T, jwksURL string) *JWTVerifier {
+	t.Helper()
+
+	verifier, err := NewJWTVerifier(config.JWTConfig{
+		JWKSURL:     jwksURL,
+		JWKSRefresh: 3600,
+		Keys:        []config.JWTKeyConfig{{ID: "shared", Secret: testSecret}},
+		Issuer:      testIssuer,
+		Audience:    testAudience,
+		TenantClaim: "tenant",
+		ScopeClaim:  "scope",
+		ScopePrefix: "notiflow:",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return verifier
+}
+
+// validClaims returns claims the test verifier accepts, with overrides applied
+func validClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"sub":    "service-a",
+		"iss":    testIssuer,
+		"aud":    testAudience,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"tenant": "acme",
+		"scope":  "notiflow:send notiflow:read openid",
+	}
+	for name, value := range overrides {
+		claims[name] = value
+	}
+	return claims
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTVerifierVerify(t *testing.T) {
+	rsaKey, rsaPublic := rsaJWK(t, "rsa-1")
+	ecKey, ecPublic := ecJWK(t, "ec-1")
+	jwks := newTestJWKS(t, rsaPublic, ecPublic)
+	verifier := newTestVerifier(t, jwks.URL)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"RS256 from JWKS", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims(nil))},
+		{"ES256 from JWKS", sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims(nil))},
+		{"HS256 static secret", sign(t, jwt.SigningMethodHS256, "shared", []byte(testSecret), validClaims(nil))},
+		{"RS256 without kid", sign(t, jwt.SigningMethodRS256, "", rsaKey, validClaims(nil))},
+		{"audience array", sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims(jwt.MapClaims{"aud": []string{"other", testAudience}}))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			if principal.Kind != models.PrincipalJWT || principal.ID != "service-a" || principal.Tenant != "acme" {
+				t.Errorf("Verify() principal = %+v", principal)
+			}
+			if want := []models.APIKeyScope{models.ScopeSend, models.ScopeRead}; !slices.Equal(principal.Scopes, want) {
+				t.Errorf("Verify() scopes = %v, want %v", principal.Scopes, want)
+			}
+		})
+	}
+
+	if got := jwks.fetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", got)
+	}
+}
+
+func TestJWTVerifierRejects(t *testing.T) {
+	rsaKey, rsaPublic := rsaJWK(t, "rsa-1")
+	ecKey, ecPublic := ecJWK(t, "ec-1")
+	otherKey, _ := rsaJWK(t, "rsa-1")
+	jwks := newTestJWKS(t, rsaPublic, ecPublic)
+	verifier := newTestVerifier(t, jwks.URL)
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims(nil)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}))},
+		{"no expiry", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims(jwt.MapClaims{"exp": nil}))},
+		{"not yet valid", sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims(jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()}))},
+		{"wrong audience", sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims(jwt.MapClaims{"aud": "someone-else"}))},
+		{"wrong issuer", sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims(jwt.MapClaims{"iss": "https://evil.example"}))},
+		{"no subject", sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims(jwt.MapClaims{"sub": ""}))},
+		{"alg none", none},
+		{"signed by another key", sign(t, jwt.SigningMethodRS256, "rsa-1", otherKey, validClaims(nil))},
+		{"wrong secret", sign(t, jwt.SigningMethodHS256, "shared", []byte("another-secret-of-at-least-32-bytes"), validClaims(nil))},
+		{"algorithm of another key type", sign(t, jwt.SigningMethodES256, "rsa-1", ecKey, validClaims(nil))},
+		{"not a JWT", "a.b.c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token)
+			if err == nil {
+				t.Fatalf("Verify() = %+v, want an error", principal)
+			}
+			if !errors.Is(err, types.ErrUnauthenticated) {
+				t.Errorf("Verify() error = %v, want ErrUnauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierPublicKeyIsNotAnHMACSecret(t *testing.T) {
+	_, rsaPublic := rsaJWK(t, "rsa-1")
+	jwks := newTestJWKS(t, rsaPublic)
+	verifier := newTestVerifier(t, jwks.URL)
+
+	// A forger who knows the public key signs HS256 with its modulus as the secret
+	modulus, err := base64.RawURLEncoding.DecodeString(rsaPublic.N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := sign(t, jwt.SigningMethodHS256, "rsa-1", modulus, validClaims(nil))
+
+	if _, err = verifier.Verify(context.Background(), token); !errors.Is(err, types.ErrUnauthenticated) {
+		t.Errorf("Verify() error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestJWTVerifierKeyRotation(t *testing.T) {
+	oldKey, oldPublic := rsaJWK(t, "2024")
+	newKey, newPublic := rsaJWK(t, "2025")
+	jwks := newTestJWKS(t, oldPublic)
+	verifier := newTestVerifier(t, jwks.URL)
+	ctx := context.Background()
+
+	if _, err := verifier.Verify(ctx, sign(t, jwt.SigningMethodRS256, "2024", oldKey, validClaims(nil))); err != nil {
+		t.Fatalf("Verify() with the current key: %v", err)
+	}
+
+	jwks.publish(oldPublic, newPublic)
+	rotated := sign(t, jwt.SigningMethodRS256, "2025", newKey, validClaims(nil))
+
+	// Unknown key IDs refetch at most once per retry interval
+	if _, err := verifier.Verify(ctx, rotated); err == nil {
+		t.Fatal("Verify() refetched the JWKS within the retry interval")
+	}
+	if got := jwks.fetches.Load(); got != 1 {
+		t.Fatalf("JWKS fetched %d times within the retry interval, want 1", got)
+	}
+
+	verifier.jwks.mu.Lock()
+	verifier.jwks.triedAt = time.Now().Add(-jwksRetryInterval)
+	verifier.jwks.mu.Unlock()
+
+	principal, err := verifier.Verify(ctx, rotated)
+	if err != nil {
+		t.Fatalf("Verify() with the rotated key: %v", err)
+	}
+	if principal.ID != "service-a" {
+		t.Errorf("Verify() principal = %+v", principal)
+	}
+	if got := jwks.fetches.Load(); got != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", got)
+	}
+
+	// Tokens signed with the old key stay valid while it is still published
+	if _, err = verifier.Verify(ctx, sign(t, jwt.SigningMethodRS256, "2024", oldKey, validClaims(nil))); err != nil {
+		t.Errorf("Verify() with the old key: %v", err)
+	}
+	if got := jwks.fetches.Load(); got != 2 {
+		t.Errorf("JWKS fetched %d times for a known key, want 2", got)
+	}
+}
+
+func TestJWTVerifierKeepsKeysWhenJWKSFails(t *testing.T) {
+	key, public := rsaJWK(t, "rsa-1")
+	jwks := newTestJWKS(t, public)
+	verifier := newTestVerifier(t, jwks.URL)
+	ctx := context.Background()
+	token := sign(t, jwt.SigningMethodRS256, "rsa-1", key, validClaims(nil))
+
+	if _, err := verifier.Verify(ctx, token); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	jwks.Close()
+	verifier.jwks.mu.Lock()
+	verifier.jwks.fetchedAt = time.Now().Add(-2 * time.Hour)
+	verifier.jwks.triedAt = time.Time{}
+	verifier.jwks.mu.Unlock()
+
+	if _, err := verifier.Verify(ctx, token); err != nil {
+		t.Errorf("Verify() after a failed refresh: %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	keyPrefix    = "nf_"
+	prefixLength = len(keyPrefix) + 8 // Characters of a key shown in listings
+)
+
+// GenerateKey returns a new random API key and the prefix that identifies it
+func GenerateKey() (key, prefix string, err error) {
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:prefixLength], nil
+}
+
+// HashKey returns the hash stored for key. Keys are random 256-bit values, so a fast unsalted hash
+// is enough to make a leaked database useless for authentication.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
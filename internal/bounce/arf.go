@@ -0,0 +1,55 @@
+package bounce
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strings"
+)
+
+// FeedbackReport is a parsed RFC 5965 abuse report sent by a mailbox provider's feedback loop
+type FeedbackReport struct {
+	EnvelopeTo        []string
+	FeedbackType      string // abuse, fraud, virus, not-spam or other
+	UserAgent         string
+	OriginalMailFrom  string // Envelope sender of the reported message, a VERP address when enabled
+	OriginalRcptTo    string // Often redacted by the provider
+	OriginalMessageID string
+}
+
+// ParseARF parses a multipart/report; report-type=feedback-report message
+func ParseARF(r io.Reader) (*FeedbackReport, error) {
+	report := &FeedbackReport{}
+	var found bool
+
+	header, err := parseReport(r, "feedback-report", func(partType string, part io.Reader) error {
+		switch partType {
+		case "message/feedback-report":
+			fields, err := textproto.NewReader(bufio.NewReader(part)).ReadMIMEHeader()
+			if err != nil && len(fields) == 0 {
+				return fmt.Errorf("bounce: failed to read feedback report: %w", err)
+			}
+
+			found = true
+			report.FeedbackType = strings.ToLower(strings.TrimSpace(fields.Get("Feedback-Type")))
+			report.UserAgent = strings.TrimSpace(fields.Get("User-Agent"))
+			report.OriginalMailFrom = addressField(fields.Get("Original-Mail-From"))
+			report.OriginalRcptTo = addressField(fields.Get("Original-Rcpt-To"))
+		case "message/rfc822", "text/rfc822-headers":
+			report.OriginalMessageID = returnedMessageID(part)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("bounce: report has no feedback part: %w", ErrNotReport)
+	}
+
+	report.EnvelopeTo = envelopeRecipients(header)
+	return report, nil
+}
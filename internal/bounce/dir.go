@@ -0,0 +1,53 @@
+package bounce
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DirSource reads .eml files from a directory, moving each into a processed subdirectory once
+// handled. Useful for replaying saved bounces and for local testing.
+type DirSource struct {
+	dir string
+}
+
+func (s *DirSource) Poll(ctx context.Context, handle Handler) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	processed := filepath.Join(s.dir, "processed")
+	if err = os.MkdirAll(processed, 0o755); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".eml") {
+			continue
+		}
+
+		path := filepath.Join(s.dir, entry.Name())
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			slog.Error("Failed to read bounce message", "error", err, "path", path)
+			continue
+		}
+
+		if err = handle(raw); err != nil {
+			continue
+		}
+
+		if err = os.Rename(path, filepath.Join(processed, entry.Name())); err != nil {
+			slog.Error("Failed to move processed bounce message", "error", err, "path", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package bounce
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aarondever/notiflow/internal/config"
+)
+
+func TestDirSourcePoll(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.eml":      "first",
+		"b.EML":      "second",
+		"failed.eml": "fails",
+		"notes.txt":  "ignored",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source, err := NewSource(config.BounceConfig{Source: "dir", Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handled []string
+	err = source.Poll(context.Background(), func(raw []byte) error {
+		handled = append(handled, string(raw))
+		if string(raw) == "fails" {
+			return errors.New("not processed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	slices.Sort(handled)
+	if want := []string{"fails", "first", "second"}; !slices.Equal(handled, want) {
+		t.Errorf("handled %q, want %q", handled, want)
+	}
+
+	tests := []struct {
+		path   string
+		exists bool
+	}{
+		{"a.eml", false},
+		{"processed/a.eml", true},
+		{"processed/b.EML", true},
+		{"failed.eml", true},
+		{"processed/failed.eml", false},
+		{"notes.txt", true},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(dir, tt.path))
+		if exists := err == nil; exists != tt.exists {
+			t.Errorf("%s exists = %v, want %v", tt.path, exists, tt.exists)
+		}
+	}
+
+	// Processed messages aren't handled again, failed ones are retried
+	handled = nil
+	if err = source.Poll(context.Background(), func(raw []byte) error {
+		handled = append(handled, string(raw))
+		return nil
+	}); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if want := []string{"fails"}; !slices.Equal(handled, want) {
+		t.Errorf("second poll handled %q, want %q", handled, want)
+	}
+}
+
+func TestDirSourcePollCanceled(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.eml"), []byte("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	source := &DirSource{dir: dir}
+	err := source.Poll(ctx, func([]byte) error {
+		t.Error("handled a message after cancellation")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Poll() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestNewSourceDirRequiresDirectory(t *testing.T) {
+	if _, err := NewSource(config.BounceConfig{Source: "dir"}); err == nil {
+		t.Error("NewSource() accepted a dir source without a directory")
+	}
+}
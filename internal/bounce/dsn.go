@@ -0,0 +1,196 @@
+package bounce
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// ErrNotReport is returned for messages that aren't the expected kind of report: RFC 3464
+// delivery status notifications for ParseDSN, RFC 5965 feedback reports for ParseARF
+var ErrNotReport = errors.New("bounce: not a report message")
+
+// Report is a parsed delivery status notification
+type Report struct {
+	// Envelope recipients of the DSN itself, where a VERP address shows up
+	EnvelopeTo []string
+	// Message-ID of the message that bounced, taken from the returned headers
+	OriginalMessageID string
+	Recipients        []RecipientStatus
+}
+
+type RecipientStatus struct {
+	FinalRecipient    string
+	OriginalRecipient string
+	Action            string // failed, delayed, delivered, relayed or expanded
+	Status            string // RFC 3463 enhanced status code, e.g. 5.1.1
+	DiagnosticCode    string
+	RemoteMTA         string
+}
+
+// IsPermanent reports whether the recipient failed with a 5.x.x status
+func (status RecipientStatus) IsPermanent() bool {
+	return status.Action == "failed" && strings.HasPrefix(status.Status, "5")
+}
+
+// ParseDSN parses a multipart/report; report-type=delivery-status message
+func ParseDSN(r io.Reader) (*Report, error) {
+	report := &Report{}
+
+	header, err := parseReport(r, "delivery-status", func(partType string, part io.Reader) error {
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			recipients, err := parseDeliveryStatus(part)
+			if err != nil {
+				return err
+			}
+			report.Recipients = append(report.Recipients, recipients...)
+		case "message/rfc822", "text/rfc822-headers", "message/global", "message/global-headers":
+			report.OriginalMessageID = returnedMessageID(part)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(report.Recipients) == 0 {
+		return nil, fmt.Errorf("bounce: delivery status has no recipients: %w", ErrNotReport)
+	}
+
+	report.EnvelopeTo = envelopeRecipients(header)
+	return report, nil
+}
+
+// parseReport walks the parts of a multipart/report message of the given report type,
+// returning ErrNotReport for any other message
+func parseReport(r io.Reader, reportType string, handlePart func(partType string, part io.Reader) error) (mail.Header, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("bounce: failed to read message: %w", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || !strings.EqualFold(params["report-type"], reportType) {
+		return nil, ErrNotReport
+	}
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bounce: failed to read report part: %w", err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err = handlePart(partType, part); err != nil {
+			return nil, err
+		}
+	}
+
+	return message.Header, nil
+}
+
+// returnedMessageID reads the Message-ID from the copy of the original message or its headers
+func returnedMessageID(part io.Reader) string {
+	headers, err := textproto.NewReader(bufio.NewReader(part)).ReadMIMEHeader()
+	if err != nil && len(headers) == 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(headers.Get("Message-Id"))
+}
+
+// parseDeliveryStatus reads the per-message block followed by one block per recipient
+func parseDeliveryStatus(r io.Reader) ([]RecipientStatus, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("bounce: failed to read delivery status: %w", err)
+	}
+
+	// Blocks are separated by blank lines and use header syntax
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	blocks := bytes.Split(bytes.TrimSpace(content), []byte("\n\n"))
+
+	var recipients []RecipientStatus
+	for i, block := range blocks {
+		// The first block describes the reporting MTA, not a recipient
+		if i == 0 {
+			continue
+		}
+
+		fields, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(block, '\n', '\n')))).ReadMIMEHeader()
+		if err != nil && len(fields) == 0 {
+			continue
+		}
+
+		recipient := RecipientStatus{
+			FinalRecipient:    addressField(fields.Get("Final-Recipient")),
+			OriginalRecipient: addressField(fields.Get("Original-Recipient")),
+			Action:            strings.ToLower(strings.TrimSpace(fields.Get("Action"))),
+			Status:            statusCode(fields.Get("Status")),
+			DiagnosticCode:    typedField(fields.Get("Diagnostic-Code")),
+			RemoteMTA:         typedField(fields.Get("Remote-Mta")),
+		}
+		if recipient.FinalRecipient == "" {
+			continue
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
+
+// envelopeRecipients collects the headers MTAs use to record the envelope recipient on delivery
+func envelopeRecipients(header mail.Header) []string {
+	var recipients []string
+	for _, name := range []string{"Delivered-To", "X-Original-To", "Envelope-To", "To"} {
+		for _, value := range header[textproto.CanonicalMIMEHeaderKey(name)] {
+			addresses, err := mail.ParseAddressList(value)
+			if err != nil {
+				continue
+			}
+			for _, address := range addresses {
+				recipients = append(recipients, address.Address)
+			}
+		}
+	}
+
+	return recipients
+}
+
+// addressField strips the address type from fields like "rfc822; user@example.com"
+func addressField(value string) string {
+	return strings.ToLower(strings.Trim(typedField(value), "<> "))
+}
+
+// typedField strips the type prefix from fields like "smtp; 550 5.1.1 User unknown"
+func typedField(value string) string {
+	if _, rest, ok := strings.Cut(value, ";"); ok {
+		value = rest
+	}
+
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// statusCode keeps only the enhanced status code, some MTAs append a comment
+func statusCode(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
@@ -0,0 +1,120 @@
+package bounce
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	return file
+}
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		wantErr    error
+		envelopeTo []string
+		messageID  string
+		recipients []RecipientStatus
+		permanent  []bool
+	}{
+		{
+			fixture:    "dsn_permanent.eml",
+			envelopeTo: []string{"bounces+65f1a2b3c4d5e6f708192a3b.jane=example.com@bounces.notiflow.test", "bounces+65f1a2b3c4d5e6f708192a3b.jane=example.com@bounces.notiflow.test"},
+			messageID:  "<65f1a2b3c4d5e6f708192a3b.1710152100@notiflow.test>",
+			recipients: []RecipientStatus{{
+				FinalRecipient:    "jane@example.com",
+				OriginalRecipient: "jane@example.com",
+				Action:            "failed",
+				Status:            "5.1.1",
+				DiagnosticCode:    "550 5.1.1 <jane@example.com>: Recipient address rejected: User unknown",
+				RemoteMTA:         "mx.example.com",
+			}},
+			permanent: []bool{true},
+		},
+		{
+			fixture:    "dsn_delayed.eml",
+			envelopeTo: []string{"bounces+65f1a2b3c4d5e6f708192a3c@bounces.notiflow.test", "bounces+65f1a2b3c4d5e6f708192a3c@bounces.notiflow.test"},
+			messageID:  "<65f1a2b3c4d5e6f708192a3c.1710152200@notiflow.test>",
+			recipients: []RecipientStatus{
+				{FinalRecipient: "ops@example.org", Action: "delayed", Status: "4.4.7", DiagnosticCode: "451 4.4.7 Message delayed in queue"},
+				{FinalRecipient: "billing@example.org", Action: "failed", Status: "5.2.2", DiagnosticCode: "552 5.2.2 Mailbox full"},
+				{FinalRecipient: "cfo@example.org", Action: "delivered", Status: "2.0.0"},
+			},
+			permanent: []bool{false, true, false},
+		},
+		{fixture: "dsn_empty.eml", wantErr: ErrNotReport},
+		{fixture: "arf_complaint.eml", wantErr: ErrNotReport},
+		{fixture: "reply.eml", wantErr: ErrNotReport},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			report, err := ParseDSN(openFixture(t, tt.fixture))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseDSN() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDSN() error = %v", err)
+			}
+
+			if !slices.Equal(report.EnvelopeTo, tt.envelopeTo) {
+				t.Errorf("EnvelopeTo = %q, want %q", report.EnvelopeTo, tt.envelopeTo)
+			}
+			if report.OriginalMessageID != tt.messageID {
+				t.Errorf("OriginalMessageID = %q, want %q", report.OriginalMessageID, tt.messageID)
+			}
+			if !slices.Equal(report.Recipients, tt.recipients) {
+				t.Fatalf("Recipients = %+v, want %+v", report.Recipients, tt.recipients)
+			}
+			for i, recipient := range report.Recipients {
+				if recipient.IsPermanent() != tt.permanent[i] {
+					t.Errorf("%s IsPermanent() = %v, want %v", recipient.FinalRecipient, recipient.IsPermanent(), tt.permanent[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseARF(t *testing.T) {
+	report, err := ParseARF(openFixture(t, "arf_complaint.eml"))
+	if err != nil {
+		t.Fatalf("ParseARF() error = %v", err)
+	}
+
+	want := FeedbackReport{
+		EnvelopeTo:        []string{"fbl@notiflow.test"},
+		FeedbackType:      "abuse",
+		UserAgent:         "ExampleFBL/1.0",
+		OriginalMailFrom:  "bounces+65f1a2b3c4d5e6f708192a3d.sam=example.net@bounces.notiflow.test",
+		OriginalRcptTo:    "sam@example.net",
+		OriginalMessageID: "<65f1a2b3c4d5e6f708192a3d.1710230291@notiflow.test>",
+	}
+	if !slices.Equal(report.EnvelopeTo, want.EnvelopeTo) {
+		t.Errorf("EnvelopeTo = %q, want %q", report.EnvelopeTo, want.EnvelopeTo)
+	}
+	report.EnvelopeTo, want.EnvelopeTo = nil, nil
+	if !reflect.DeepEqual(*report, want) {
+		t.Errorf("ParseARF() = %+v, want %+v", *report, want)
+	}
+
+	for _, fixture := range []string{"dsn_permanent.eml", "reply.eml"} {
+		if _, err = ParseARF(openFixture(t, fixture)); !errors.Is(err, ErrNotReport) {
+			t.Errorf("ParseARF(%s) error = %v, want ErrNotReport", fixture, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package bounce
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+)
+
+// IMAPSource reads unseen messages from a mailbox folder and flags them seen once handled
+type IMAPSource struct {
+	cfg config.BounceConfig
+}
+
+func (s *IMAPSource) Poll(ctx context.Context, handle Handler) error {
+	conn, err := dial(ctx, s.cfg)
+	if err != nil {
+		return err
+	}
+
+	// Closing the connection interrupts the command in progress when ctx is canceled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// The client sets its own deadline for every command, this one covers the greeting
+	if err = conn.SetDeadline(commandDeadline(ctx)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	imapClient, err := client.New(conn)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	imapClient.Timeout = commandTimeout
+	defer imapClient.Logout()
+
+	if s.cfg.Security == "starttls" {
+		if err = imapClient.StartTLS(tlsConfig(s.cfg)); err != nil {
+			return err
+		}
+	}
+
+	if err = imapClient.Login(s.cfg.Username, s.cfg.Password); err != nil {
+		return err
+	}
+
+	if _, err = imapClient.Select(s.cfg.Mailbox, false); err != nil {
+		return err
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag}
+	uids, err := imapClient.UidSearch(criteria)
+	if err != nil || len(uids) == 0 {
+		return err
+	}
+
+	// Peek so messages that fail to process stay unseen and are retried on the next poll
+	section := &imap.BodySectionName{Peek: true}
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uids...)
+
+	messages := make(chan *imap.Message, 16)
+	fetchErr := make(chan error, 1)
+	go func() {
+		fetchErr <- imapClient.UidFetch(seqSet, []imap.FetchItem{section.FetchItem(), imap.FetchUid}, messages)
+	}()
+
+	raws := make(map[uint32][]byte, len(uids))
+	for message := range messages {
+		body := message.GetBody(section)
+		if body == nil {
+			continue
+		}
+
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			slog.Error("Failed to read bounce message", "error", err, "uid", message.Uid)
+			continue
+		}
+		raws[message.Uid] = raw
+	}
+	if err = <-fetchErr; err != nil {
+		return err
+	}
+
+	// The client keeps reading while messages are handled, which may outlast the fetch's deadline
+	if err = conn.SetDeadline(time.Time{}); err != nil {
+		return err
+	}
+
+	handled := new(imap.SeqSet)
+	for uid, raw := range raws {
+		if ctx.Err() != nil {
+			break
+		}
+		if handle(raw) == nil {
+			handled.AddNum(uid)
+		}
+	}
+
+	if handled.Empty() {
+		return ctx.Err()
+	}
+
+	return imapClient.UidStore(handled, imap.FormatFlagsOp(imap.AddFlags, true), []interface{}{imap.SeenFlag}, nil)
+}
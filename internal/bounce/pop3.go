@@ -0,0 +1,124 @@
+package bounce
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/aarondever/notiflow/internal/config"
+)
+
+// POP3Source retrieves every message in the mailbox and deletes the ones that were handled.
+// POP3 has no flags, so a dedicated bounce mailbox is expected.
+type POP3Source struct {
+	cfg config.BounceConfig
+}
+
+func (s *POP3Source) Poll(ctx context.Context, handle Handler) error {
+	conn, err := dial(ctx, s.cfg)
+	if err != nil {
+		return err
+	}
+
+	// Closing the connection interrupts the command in progress when ctx is canceled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	pop := &pop3Client{ctx: ctx, conn: conn, text: textproto.NewConn(conn)}
+	defer pop.text.Close()
+
+	// Greeting
+	if err = conn.SetDeadline(commandDeadline(ctx)); err != nil {
+		return err
+	}
+	if _, err = pop.readReply(); err != nil {
+		return err
+	}
+
+	if s.cfg.Security == "starttls" {
+		if _, err = pop.command("STLS"); err != nil {
+			return err
+		}
+		pop.conn = tls.Client(conn, tlsConfig(s.cfg))
+		pop.text = textproto.NewConn(pop.conn)
+	}
+
+	if _, err = pop.command("USER %s", s.cfg.Username); err != nil {
+		return err
+	}
+	if _, err = pop.command("PASS %s", s.cfg.Password); err != nil {
+		return err
+	}
+
+	// STAT replies with "<count> <size>"
+	stat, err := pop.command("STAT")
+	if err != nil {
+		return err
+	}
+	count, err := strconv.Atoi(strings.Fields(stat + " 0")[0])
+	if err != nil {
+		return fmt.Errorf("bounce: unexpected STAT reply %q", stat)
+	}
+
+	for number := 1; number <= count && ctx.Err() == nil; number++ {
+		if _, err = pop.command("RETR %d", number); err != nil {
+			return err
+		}
+
+		raw, err := pop.text.ReadDotBytes()
+		if err != nil {
+			return err
+		}
+
+		if handle(raw) != nil {
+			continue
+		}
+
+		if _, err = pop.command("DELE %d", number); err != nil {
+			slog.Error("Failed to delete processed bounce message", "error", err, "message", number)
+		}
+	}
+
+	// Deletions only take effect on QUIT
+	_, err = pop.command("QUIT")
+	return err
+}
+
+// pop3Client sends commands over a connection, the TLS one after STLS
+type pop3Client struct {
+	ctx  context.Context
+	conn net.Conn
+	text *textproto.Conn
+}
+
+// command sends a command and reads its reply, both within commandDeadline
+func (c *pop3Client) command(format string, args ...any) (string, error) {
+	if err := c.conn.SetDeadline(commandDeadline(c.ctx)); err != nil {
+		return "", err
+	}
+	if err := c.text.PrintfLine(format, args...); err != nil {
+		return "", err
+	}
+
+	return c.readReply()
+}
+
+// readReply returns the text after +OK, or an error for -ERR replies
+func (c *pop3Client) readReply() (string, error) {
+	line, err := c.text.ReadLine()
+	if err != nil {
+		return "", err
+	}
+
+	status, text, _ := strings.Cut(line, " ")
+	if status != "+OK" {
+		return "", fmt.Errorf("bounce: pop3 error: %s", text)
+	}
+
+	return text, nil
+}
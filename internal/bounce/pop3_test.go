@@ -0,0 +1,125 @@
+package bounce
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+)
+
+// servePOP3 accepts one connection and answers it with a minimal POP3 server holding messages,
+// recording the commands it receives
+func servePOP3(t *testing.T, messages []string, stall bool) (config.BounceConfig, <-chan []string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	commands := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var received []string
+		defer func() { commands <- received }()
+
+		if stall {
+			// Never greet, the client has to give up on its own
+			_, _ = bufio.NewReader(conn).ReadString('\n')
+			return
+		}
+
+		reader := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte("+OK ready\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimSpace(line)
+			received = append(received, command)
+
+			verb, arg, _ := strings.Cut(command, " ")
+			switch verb {
+			case "STAT":
+				_, _ = conn.Write([]byte("+OK " + strconv.Itoa(len(messages)) + " 0\r\n"))
+			case "RETR":
+				number, _ := strconv.Atoi(arg)
+				_, _ = conn.Write([]byte("+OK\r\n" + messages[number-1] + "\r\n.\r\n"))
+			case "QUIT":
+				_, _ = conn.Write([]byte("+OK bye\r\n"))
+				return
+			default:
+				_, _ = conn.Write([]byte("+OK\r\n"))
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return config.BounceConfig{Source: "pop3", Host: host, Port: portNumber, Security: "none", Username: "bounces", Password: "secret"}, commands
+}
+
+func TestPOP3SourcePoll(t *testing.T) {
+	cfg, commands := servePOP3(t, []string{"first", "second"}, false)
+	source, err := NewSource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handled []string
+	err = source.Poll(context.Background(), func(raw []byte) error {
+		handled = append(handled, strings.TrimSpace(string(raw)))
+		if len(handled) == 1 {
+			return errors.New("not processed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	if want := []string{"first", "second"}; !slices.Equal(handled, want) {
+		t.Errorf("handled %q, want %q", handled, want)
+	}
+
+	// Only the handled message is deleted
+	want := []string{"USER bounces", "PASS secret", "STAT", "RETR 1", "RETR 2", "DELE 2", "QUIT"}
+	if got := <-commands; !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
+
+func TestPOP3SourcePollStalledServer(t *testing.T) {
+	cfg, _ := servePOP3(t, nil, true)
+	source, err := NewSource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = source.Poll(ctx, func([]byte) error { return nil })
+	if !errors.Is(err, os.ErrDeadlineExceeded) && !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Poll() error = %v, want a deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Poll() returned after %s", elapsed)
+	}
+}
@@ -0,0 +1,71 @@
+package bounce
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+)
+
+// commandTimeout bounds each mailbox command, so a stalled server can't hang polling
+const commandTimeout = time.Minute
+
+// Handler processes one raw message. Messages are only marked processed when it returns nil.
+type Handler func(raw []byte) error
+
+// Source is a mailbox that bounce reports are delivered to
+type Source interface {
+	// Poll hands every unprocessed message to handle
+	Poll(ctx context.Context, handle Handler) error
+}
+
+// NewSource returns the source configured in cfg, or nil when bounce processing is disabled
+func NewSource(cfg config.BounceConfig) (Source, error) {
+	switch cfg.Source {
+	case "":
+		return nil, nil
+	case "imap":
+		return &IMAPSource{cfg: cfg}, nil
+	case "pop3":
+		return &POP3Source{cfg: cfg}, nil
+	case "dir":
+		if cfg.Directory == "" {
+			return nil, fmt.Errorf("bounce: directory is required for the dir source")
+		}
+		return &DirSource{dir: cfg.Directory}, nil
+	default:
+		return nil, fmt.Errorf("bounce: unknown source %q", cfg.Source)
+	}
+}
+
+// dial connects to the mailbox server, over TLS unless security is "starttls" or "none"
+func dial(ctx context.Context, cfg config.BounceConfig) (net.Conn, error) {
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	if cfg.Security == "starttls" || cfg.Security == "none" {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+
+	dialer := tls.Dialer{Config: tlsConfig(cfg)}
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+func tlsConfig(cfg config.BounceConfig) *tls.Config {
+	return &tls.Config{ServerName: cfg.Host}
+}
+
+// commandDeadline is when the next command must have completed: after commandTimeout, or when ctx
+// ends if that is sooner
+func commandDeadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(commandTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+
+	return deadline
+}
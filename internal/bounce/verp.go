@@ -0,0 +1,75 @@
+package bounce
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// VERP return paths look like bounces+<email id>.<local>=<domain>@<bounce domain>, so a bounce
+// sent to the return path identifies both the email and the recipient without parsing the DSN.
+// Emails sent to several recipients in one transaction only carry the email ID.
+const verpPrefix = "bounces+"
+
+// Longer local parts break RFC 5321 and are rejected by some MTAs
+const maxLocalPartLength = 64
+
+// EncodeVERP returns the envelope sender for an email, recipient may be empty. The recipient is
+// left out when it would make the local part too long, the email ID alone still identifies it
+// because only emails with a single recipient carry one.
+func EncodeVERP(emailID bson.ObjectID, recipient, bounceDomain string) string {
+	local := verpPrefix + emailID.Hex()
+	if recipient != "" {
+		withRecipient := local + "." + strings.Replace(strings.ToLower(recipient), "@", "=", 1)
+		if len(withRecipient) <= maxLocalPartLength {
+			local = withRecipient
+		}
+	}
+
+	return fmt.Sprintf("%s@%s", local, bounceDomain)
+}
+
+// DecodeVERP extracts the email ID and recipient from a VERP return path
+func DecodeVERP(address string) (bson.ObjectID, string, bool) {
+	local, _, ok := strings.Cut(strings.ToLower(address), "@")
+	if !ok || !strings.HasPrefix(local, verpPrefix) {
+		return bson.NilObjectID, "", false
+	}
+
+	id, recipient, _ := strings.Cut(strings.TrimPrefix(local, verpPrefix), ".")
+	emailID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return bson.NilObjectID, "", false
+	}
+
+	// The recipient's own @ was replaced with =, put back the last one
+	if i := strings.LastIndex(recipient, "="); i >= 0 {
+		recipient = recipient[:i] + "@" + recipient[i+1:]
+	} else {
+		recipient = ""
+	}
+
+	return emailID, recipient, true
+}
+
+// MessageID returns the Message-ID header value notiflow sets on an email
+func MessageID(emailID bson.ObjectID, unique, domain string) string {
+	return fmt.Sprintf("<%s.%s@%s>", emailID.Hex(), unique, domain)
+}
+
+// EmailIDFromMessageID extracts the email ID from a Message-ID set by MessageID
+func EmailIDFromMessageID(messageID string) (bson.ObjectID, bool) {
+	local, _, ok := strings.Cut(strings.Trim(strings.TrimSpace(messageID), "<>"), "@")
+	if !ok {
+		return bson.NilObjectID, false
+	}
+
+	id, _, _ := strings.Cut(local, ".")
+	emailID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return bson.NilObjectID, false
+	}
+
+	return emailID, true
+}
@@ -0,0 +1,76 @@
+package bounce
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestVERPRoundTrip(t *testing.T) {
+	emailID := bson.NewObjectID()
+
+	tests := []struct {
+		name          string
+		recipient     string
+		wantRecipient string
+	}{
+		{"no recipient", "", ""},
+		{"recipient", "jane@example.com", "jane@example.com"},
+		{"mixed case", "Jane.Doe@Example.COM", "jane.doe@example.com"},
+		{"equals sign in local part", "a=b@x.io", "a=b@x.io"},
+		{"plus address", "ops+alerts@x.io", "ops+alerts@x.io"},
+		{"local part too long", "a.very.long.recipient.address@example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := EncodeVERP(emailID, tt.recipient, "bounces.notiflow.test")
+
+			local, domain, _ := strings.Cut(address, "@")
+			if len(local) > maxLocalPartLength {
+				t.Errorf("EncodeVERP() local part is %d octets: %s", len(local), address)
+			}
+			if domain != "bounces.notiflow.test" {
+				t.Errorf("EncodeVERP() domain = %q", domain)
+			}
+
+			gotID, gotRecipient, ok := DecodeVERP(address)
+			if !ok {
+				t.Fatalf("DecodeVERP(%q) failed", address)
+			}
+			if gotID != emailID {
+				t.Errorf("DecodeVERP() email ID = %s, want %s", gotID.Hex(), emailID.Hex())
+			}
+			if gotRecipient != tt.wantRecipient {
+				t.Errorf("DecodeVERP() recipient = %q, want %q", gotRecipient, tt.wantRecipient)
+			}
+		})
+	}
+}
+
+func TestDecodeVERPRejects(t *testing.T) {
+	for _, address := range []string{
+		"",
+		"jane@example.com",
+		"bounces+not-an-id@bounces.notiflow.test",
+		"bounces+65f1a2b3c4d5e6f708192a3b",
+		"reply+65f1a2b3c4d5e6f708192a3b@replies.notiflow.test",
+	} {
+		if _, _, ok := DecodeVERP(address); ok {
+			t.Errorf("DecodeVERP(%q) succeeded", address)
+		}
+	}
+}
+
+func TestMessageIDRoundTrip(t *testing.T) {
+	emailID := bson.NewObjectID()
+
+	messageID := MessageID(emailID, "1710152100", "notiflow.test")
+	if got, ok := EmailIDFromMessageID(" " + messageID + " "); !ok || got != emailID {
+		t.Errorf("EmailIDFromMessageID(%q) = %s, %v", messageID, got.Hex(), ok)
+	}
+
+	if _, ok := EmailIDFromMessageID("<20240311101502.8F2C71A0@mx.example.com>"); ok {
+		t.Error("EmailIDFromMessageID() accepted a foreign Message-ID")
+	}
+}
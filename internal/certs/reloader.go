@@ -0,0 +1,185 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+)
+
+// fileState identifies a version of a file, rotation tools replace the file or the symlink to it
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader serves the configured certificate and client CAs to the HTTP and gRPC servers' TLS
+// handshakes, and reloads them when the files change so rotated certificates apply without a restart
+type Reloader struct {
+	cfg        config.TLSConfig
+	minVersion uint16
+	clientAuth tls.ClientAuthType
+
+	current atomic.Pointer[tls.Config]
+	files   map[string]fileState // Only used by the constructor and Run
+}
+
+func NewReloader(cfg *config.Config) (*Reloader, error) {
+	reloader := &Reloader{cfg: cfg.Server.TLS}
+	if !reloader.Enabled() {
+		if reloader.cfg.KeyFile != "" || reloader.cfg.ClientCAFile != "" {
+			return nil, fmt.Errorf("TLS key and client CA files need a certificate file")
+		}
+
+		return reloader, nil
+	}
+	if reloader.cfg.KeyFile == "" {
+		return nil, fmt.Errorf("TLS certificate file %s needs a key file", reloader.cfg.CertFile)
+	}
+
+	switch reloader.cfg.MinVersion {
+	case "", "1.2":
+		reloader.minVersion = tls.VersionTLS12
+	case "1.3":
+		reloader.minVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS min version %q, use 1.2 or 1.3", reloader.cfg.MinVersion)
+	}
+
+	switch reloader.cfg.ClientAuth {
+	case "", "require":
+		reloader.clientAuth = tls.RequireAndVerifyClientCert
+	case "optional":
+		reloader.clientAuth = tls.VerifyClientCertIfGiven
+	default:
+		return nil, fmt.Errorf("unsupported TLS client auth %q, use require or optional", reloader.cfg.ClientAuth)
+	}
+	if reloader.cfg.ClientCAFile == "" {
+		reloader.clientAuth = tls.NoClientCert
+	}
+
+	if err := reloader.load(); err != nil {
+		slog.Error("Failed to load TLS certificate", "error", err, "cert_file", reloader.cfg.CertFile)
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// Enabled reports whether a certificate is configured, the servers serve plaintext otherwise
+func (r *Reloader) Enabled() bool {
+	return r.cfg.CertFile != ""
+}
+
+// ServerConfig returns the TLS config of a server, each handshake uses the latest files loaded
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Run checks the files for changes every reload interval until ctx is cancelled. A failed reload
+// keeps the files loaded before, so a half-written rotation doesn't take the servers down.
+func (r *Reloader) Run(ctx context.Context) {
+	if !r.Enabled() || r.cfg.ReloadInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(r.cfg.ReloadInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.load(); err != nil {
+				slog.Error("Failed to reload TLS certificate, keeping the current one", "error", err, "cert_file", r.cfg.CertFile)
+			}
+		}
+	}
+}
+
+// load reads the certificate, key and client CAs and swaps them in for new handshakes
+func (r *Reloader) load() error {
+	// Stat before reading, so a change while reading is picked up by the next check. A failed load is
+	// retried once the files change again.
+	r.files = r.stat()
+
+	certificate, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   r.minVersion,
+		ClientAuth:   r.clientAuth,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM certificates in client CA file %s", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.current.Store(tlsConfig)
+
+	slog.Info("Loaded TLS certificate",
+		"subject", certificate.Leaf.Subject.String(),
+		"not_after", certificate.Leaf.NotAfter,
+		"mtls", r.cfg.ClientCAFile != "",
+	)
+
+	return nil
+}
+
+// changed reports whether any file differs from when it was loaded
+func (r *Reloader) changed() bool {
+	current := r.stat()
+	for name, state := range current {
+		if r.files[name] != state {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Reloader) stat() map[string]fileState {
+	files := make(map[string]fileState)
+	for _, name := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if name == "" {
+			continue
+		}
+
+		// A missing file counts as unchanged until it is back, the reload would only fail
+		info, err := os.Stat(name)
+		if err != nil {
+			files[name] = r.files[name]
+			continue
+		}
+		files[name] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return files
+}
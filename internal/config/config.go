@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -18,12 +19,46 @@ type Config struct {
 	Database    DatabaseConfig     `yaml:"database"`
 	Logging     LoggingConfig      `yaml:"logging"`
 	SMTPServers []SMTPServerConfig `yaml:"smtp_servers"`
+	Telegram    TelegramConfig     `yaml:"telegram"`
+	Escalation  EscalationConfig   `yaml:"escalation"`
+	Preferences PreferencesConfig  `yaml:"preferences"`
+	Unsubscribe UnsubscribeConfig  `yaml:"unsubscribe"`
+	Bounce      BounceConfig       `yaml:"bounce"`
+	Inbound     InboundConfig      `yaml:"inbound"`
+	Webhooks    WebhooksConfig     `yaml:"webhooks"`
+	Tracking    TrackingConfig     `yaml:"tracking"`
+	Stats       StatsConfig        `yaml:"stats"`
+	Health      HealthConfig       `yaml:"health"`
+	Tracing     TracingConfig      `yaml:"tracing"`
+	Auth        AuthConfig         `yaml:"auth"`
+	Limits      LimitsConfig       `yaml:"limits"`
 }
 
 type ServerConfig struct {
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	GRPCPort int    `yaml:"grpc_port"`
+	Host               string    `yaml:"host"`
+	Port               int       `yaml:"port"`
+	GRPCPort           int       `yaml:"grpc_port"`
+	GRPCMaxMessageSize int       `yaml:"grpc_max_message_size"` // Largest gRPC request in bytes, batch sends need more than the 4 MB default
+	TLS                TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig terminates TLS on the HTTP and gRPC servers, which serve plaintext without a certificate
+type TLSConfig struct {
+	CertFile         string              `yaml:"cert_file"`         // PEM certificate chain of the servers
+	KeyFile          string              `yaml:"key_file"`          // PEM private key of the certificate
+	ClientCAFile     string              `yaml:"client_ca_file"`    // PEM CAs that sign client certificates, enables mTLS when set
+	ClientAuth       string              `yaml:"client_auth"`       // "require" a client certificate, or "optional" to also accept clients without one
+	MinVersion       string              `yaml:"min_version"`       // Lowest TLS version accepted, "1.2" or "1.3"
+	ReloadInterval   int                 `yaml:"reload_interval"`   // Seconds between checks of the files for changes, 0 disables reloading
+	ClientIdentities []TLSClientIdentity `yaml:"client_identities"` // Client certificates that authenticate without an API key or JWT
+}
+
+// TLSClientIdentity grants a verified client certificate the scopes of a principal
+type TLSClientIdentity struct {
+	Subject string   `yaml:"subject"` // Matched against the certificate's URI SANs (e.g. a SPIFFE ID), DNS SANs, email SANs and common name
+	Name    string   `yaml:"name"`    // Shown as the principal's name, defaults to the subject
+	Tenant  string   `yaml:"tenant"`  // Tenant the client acts for, empty for a platform client
+	Scopes  []string `yaml:"scopes"`  // Any of send, read and admin
 }
 
 type DatabaseConfig struct {
@@ -48,6 +83,146 @@ type SMTPServerConfig struct {
 	FromEmail string `yaml:"from_email"`
 }
 
+type TelegramConfig struct {
+	APIBaseURL string              `yaml:"api_base_url"` // Override for local Bot API servers or test stubs
+	Bots       []TelegramBotConfig `yaml:"bots"`
+}
+
+type TelegramBotConfig struct {
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"`
+}
+
+type EscalationConfig struct {
+	CheckInterval int             `yaml:"check_interval"` // Seconds between scans for emails to escalate
+	Webhooks      []WebhookConfig `yaml:"webhooks"`
+}
+
+type WebhookConfig struct {
+	Name   string `yaml:"name"`
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"` // Signs the payload with HMAC-SHA256 when set
+}
+
+type PreferencesConfig struct {
+	MandatoryCategories []string `yaml:"mandatory_categories"` // Categories recipients cannot opt out of, e.g. "security"
+}
+
+type UnsubscribeConfig struct {
+	BaseURL        string   `yaml:"base_url"`        // Public URL of this service, unsubscribe links point here
+	Secret         string   `yaml:"secret"`          // Signs unsubscribe tokens, headers are omitted when empty
+	BulkCategories []string `yaml:"bulk_categories"` // Categories that get one-click List-Unsubscribe headers
+}
+
+type BounceConfig struct {
+	Source          string `yaml:"source"` // "imap", "pop3" or "dir", bounce processing is disabled when empty
+	Host            string `yaml:"host"`
+	Port            int    `yaml:"port"`
+	Username        string `yaml:"username"`
+	Password        string `yaml:"password"`
+	Security        string `yaml:"security"`          // "tls", "starttls" or "none"
+	Mailbox         string `yaml:"mailbox"`           // IMAP folder to poll
+	Directory       string `yaml:"directory"`         // Directory of .eml files for the "dir" source
+	PollInterval    int    `yaml:"poll_interval"`     // Seconds between mailbox polls
+	VERPDomain      string `yaml:"verp_domain"`       // Envelope senders become VERP addresses at this domain when set
+	MessageIDDomain string `yaml:"message_id_domain"` // Domain part of the Message-ID set on outgoing emails
+}
+
+type InboundConfig struct {
+	Port            int           `yaml:"port"`              // Inbound SMTP listener port, disabled when 0
+	ReplyDomain     string        `yaml:"reply_domain"`      // Outgoing emails get a reply+<id>@ Reply-To at this domain when set
+	MaxMessageBytes int           `yaml:"max_message_bytes"` // Larger inbound messages are rejected
+	ReplyWebhook    WebhookConfig `yaml:"reply_webhook"`     // Receives replies to sent emails
+}
+
+type WebhooksConfig struct {
+	CheckInterval        int `yaml:"check_interval"`         // Seconds between scans for due webhook deliveries
+	Timeout              int `yaml:"timeout"`                // Seconds to wait for a subscriber to respond
+	MaxAttempts          int `yaml:"max_attempts"`           // Attempts per delivery before it is given up
+	DisableAfterFailures int `yaml:"disable_after_failures"` // Consecutive failed attempts that disable an endpoint
+}
+
+type TrackingConfig struct {
+	BaseURL         string   `yaml:"base_url"`         // Public URL of this service, tracking links point here
+	Secret          string   `yaml:"secret"`           // Signs tracking tokens and salts IP hashes, tracking is unavailable when empty
+	PrefetchWindow  int      `yaml:"prefetch_window"`  // Opens and clicks within this many seconds of sending are treated as prefetches
+	ExcludedDomains []string `yaml:"excluded_domains"` // Links to these domains and their subdomains are never rewritten
+}
+
+type StatsConfig struct {
+	RollupInterval int `yaml:"rollup_interval"` // Seconds between refreshes of the hourly stats rollups
+}
+
+type HealthConfig struct {
+	CheckInterval     int `yaml:"check_interval"`      // Seconds between updates of the gRPC health status
+	SMTPProbeInterval int `yaml:"smtp_probe_interval"` // Seconds between connection probes of the SMTP servers
+	SMTPProbeTimeout  int `yaml:"smtp_probe_timeout"`  // Seconds before a probe counts as failed
+	BacklogAge        int `yaml:"backlog_age"`         // Seconds after which pending emails and due webhook deliveries count as backlog
+	MaxBacklog        int `yaml:"max_backlog"`         // Backlog that makes the service not ready, 0 only reports it
+}
+
+type TracingConfig struct {
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/gRPC collector, e.g. localhost:4317, spans aren't exported when empty
+	Insecure    bool    `yaml:"insecure"`     // Connect to the collector without TLS
+	ServiceName string  `yaml:"service_name"` // Reported as service.name on every span
+	SampleRatio float64 `yaml:"sample_ratio"` // Fraction of new traces that are recorded, callers' sampling decisions are kept
+}
+
+type AuthConfig struct {
+	Disabled     bool      `yaml:"disabled"`      // Serve the API without authentication, for local development only
+	BootstrapKey string    `yaml:"bootstrap_key"` // Accepted as an admin API key, e.g. to create the first keys
+	JWT          JWTConfig `yaml:"jwt"`
+}
+
+type JWTConfig struct {
+	JWKSURL     string         `yaml:"jwks_url"`     // Signing keys of the issuer, bearer JWTs are rejected when neither this nor keys is set
+	JWKSRefresh int            `yaml:"jwks_refresh"` // Seconds between JWKS refreshes, tokens signed with an unknown key trigger an early one
+	Keys        []JWTKeyConfig `yaml:"keys"`         // Static verification keys, used besides the JWKS
+	Issuer      string         `yaml:"issuer"`       // Required iss claim when set
+	Audience    string         `yaml:"audience"`     // Required aud claim when set
+	Leeway      int            `yaml:"leeway"`       // Seconds of clock skew tolerated on exp, nbf and iat
+	TenantClaim string         `yaml:"tenant_claim"` // Claim holding the caller's tenant
+	ScopeClaim  string         `yaml:"scope_claim"`  // Claim holding the scopes, a space-separated string or an array
+	ScopePrefix string         `yaml:"scope_prefix"` // Only scopes with this prefix count, e.g. "notiflow:" maps "notiflow:send" to send
+}
+
+// LimitsConfig throttles sends, tenants may override the tenant rate, burst and quota
+type LimitsConfig struct {
+	KeyRate      float64 `yaml:"key_rate"`      // Sends per second per API key or token subject, 0 disables the limit
+	KeyBurst     int     `yaml:"key_burst"`     // Sends a key may make at once before the rate applies
+	TenantRate   float64 `yaml:"tenant_rate"`   // Sends per second per tenant across all its keys, 0 disables the limit
+	TenantBurst  int     `yaml:"tenant_burst"`  // Sends a tenant may make at once before the rate applies
+	MonthlyQuota int     `yaml:"monthly_quota"` // Emails per tenant and calendar month in UTC, 0 is unlimited
+}
+
+type JWTKeyConfig struct {
+	ID            string `yaml:"id"`              // Matched against the token's kid header, tokens without one try every key
+	Secret        string `yaml:"secret"`          // HS256 shared secret
+	PublicKeyFile string `yaml:"public_key_file"` // PEM public key or certificate for RS256 or ES256
+}
+
+// IsBulk reports whether emails of category are bulk sends that need unsubscribe headers
+func (unsubscribe UnsubscribeConfig) IsBulk(category string) bool {
+	for _, bulk := range unsubscribe.BulkCategories {
+		if bulk == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsMandatory reports whether recipients are prevented from opting out of category
+func (preferences PreferencesConfig) IsMandatory(category string) bool {
+	for _, mandatory := range preferences.MandatoryCategories {
+		if mandatory == category {
+			return true
+		}
+	}
+
+	return false
+}
+
 func LoadConfig() (*Config, error) {
 	// Load config from environment variables
 	config := loadConfigFromEnv()
@@ -132,9 +307,18 @@ func loadConfigFromEnv() *Config {
 
 	// Server config
 	config.Server = ServerConfig{
-		Host:     getStringEnv("HOST", "0.0.0.0"),
-		Port:     getIntEnv("PORT", 8080),
-		GRPCPort: getIntEnv("GRPC_PORT", 9090),
+		Host:               getStringEnv("HOST", "0.0.0.0"),
+		Port:               getIntEnv("PORT", 8080),
+		GRPCPort:           getIntEnv("GRPC_PORT", 9090),
+		GRPCMaxMessageSize: getIntEnv("GRPC_MAX_MESSAGE_SIZE", 32<<20),
+		TLS: TLSConfig{
+			CertFile:       getStringEnv("TLS_CERT_FILE", ""),
+			KeyFile:        getStringEnv("TLS_KEY_FILE", ""),
+			ClientCAFile:   getStringEnv("TLS_CLIENT_CA_FILE", ""),
+			ClientAuth:     getStringEnv("TLS_CLIENT_AUTH", "require"),
+			MinVersion:     getStringEnv("TLS_MIN_VERSION", "1.2"),
+			ReloadInterval: getIntEnv("TLS_RELOAD_INTERVAL", 30),
+		},
 	}
 
 	// Database config
@@ -163,6 +347,141 @@ func loadConfigFromEnv() *Config {
 		},
 	}
 
+	// Telegram config
+	config.Telegram = TelegramConfig{
+		APIBaseURL: getStringEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+	}
+	if token := getStringEnv("TELEGRAM_BOT_TOKEN", ""); token != "" {
+		config.Telegram.Bots = []TelegramBotConfig{
+			{
+				Name:  getStringEnv("TELEGRAM_BOT_NAME", "default"),
+				Token: token,
+			},
+		}
+	}
+
+	// Escalation config
+	config.Escalation = EscalationConfig{
+		CheckInterval: getIntEnv("ESCALATION_CHECK_INTERVAL", 30),
+	}
+	if url := getStringEnv("ESCALATION_WEBHOOK_URL", ""); url != "" {
+		config.Escalation.Webhooks = []WebhookConfig{
+			{
+				Name:   "default",
+				URL:    url,
+				Secret: getStringEnv("ESCALATION_WEBHOOK_SECRET", ""),
+			},
+		}
+	}
+
+	// Preferences config
+	config.Preferences = PreferencesConfig{
+		MandatoryCategories: getStringSliceEnv("MANDATORY_CATEGORIES", []string{"security"}),
+	}
+
+	// Unsubscribe config
+	config.Unsubscribe = UnsubscribeConfig{
+		BaseURL:        getStringEnv("PUBLIC_BASE_URL", ""),
+		Secret:         getStringEnv("UNSUBSCRIBE_SECRET", ""),
+		BulkCategories: getStringSliceEnv("BULK_CATEGORIES", []string{"marketing"}),
+	}
+
+	// Bounce config
+	config.Bounce = BounceConfig{
+		Source:          getStringEnv("BOUNCE_SOURCE", ""),
+		Host:            getStringEnv("BOUNCE_HOST", ""),
+		Port:            getIntEnv("BOUNCE_PORT", 993),
+		Username:        getStringEnv("BOUNCE_USERNAME", ""),
+		Password:        getStringEnv("BOUNCE_PASSWORD", ""),
+		Security:        getStringEnv("BOUNCE_SECURITY", "tls"),
+		Mailbox:         getStringEnv("BOUNCE_MAILBOX", "INBOX"),
+		Directory:       getStringEnv("BOUNCE_DIRECTORY", ""),
+		PollInterval:    getIntEnv("BOUNCE_POLL_INTERVAL", 60),
+		VERPDomain:      getStringEnv("BOUNCE_VERP_DOMAIN", ""),
+		MessageIDDomain: getStringEnv("MESSAGE_ID_DOMAIN", "notiflow.local"),
+	}
+
+	// Inbound config
+	config.Inbound = InboundConfig{
+		Port:            getIntEnv("INBOUND_SMTP_PORT", 0),
+		ReplyDomain:     getStringEnv("INBOUND_REPLY_DOMAIN", ""),
+		MaxMessageBytes: getIntEnv("INBOUND_MAX_MESSAGE_BYTES", 10*1024*1024),
+		ReplyWebhook: WebhookConfig{
+			Name:   "reply",
+			URL:    getStringEnv("INBOUND_REPLY_WEBHOOK_URL", ""),
+			Secret: getStringEnv("INBOUND_REPLY_WEBHOOK_SECRET", ""),
+		},
+	}
+
+	// Webhooks config
+	config.Webhooks = WebhooksConfig{
+		CheckInterval:        getIntEnv("WEBHOOK_CHECK_INTERVAL", 5),
+		Timeout:              getIntEnv("WEBHOOK_TIMEOUT", 10),
+		MaxAttempts:          getIntEnv("WEBHOOK_MAX_ATTEMPTS", 10),
+		DisableAfterFailures: getIntEnv("WEBHOOK_DISABLE_AFTER_FAILURES", 50),
+	}
+
+	// Tracking config
+	config.Tracking = TrackingConfig{
+		BaseURL:         getStringEnv("PUBLIC_BASE_URL", ""),
+		Secret:          getStringEnv("TRACKING_SECRET", ""),
+		PrefetchWindow:  getIntEnv("TRACKING_PREFETCH_WINDOW", 10),
+		ExcludedDomains: getStringSliceEnv("TRACKING_EXCLUDED_DOMAINS", nil),
+	}
+
+	// Stats config
+	config.Stats = StatsConfig{
+		RollupInterval: getIntEnv("STATS_ROLLUP_INTERVAL", 60),
+	}
+
+	// Health config
+	config.Health = HealthConfig{
+		CheckInterval:     getIntEnv("HEALTH_CHECK_INTERVAL", 10),
+		SMTPProbeInterval: getIntEnv("HEALTH_SMTP_PROBE_INTERVAL", 60),
+		SMTPProbeTimeout:  getIntEnv("HEALTH_SMTP_PROBE_TIMEOUT", 10),
+		BacklogAge:        getIntEnv("HEALTH_BACKLOG_AGE", 300),
+		MaxBacklog:        getIntEnv("HEALTH_MAX_BACKLOG", 0),
+	}
+
+	// Tracing config
+	config.Tracing = TracingConfig{
+		Endpoint:    getStringEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		Insecure:    getBoolEnv("OTEL_EXPORTER_OTLP_INSECURE", false),
+		ServiceName: getStringEnv("OTEL_SERVICE_NAME", "notiflow"),
+		SampleRatio: getFloatEnv("OTEL_TRACES_SAMPLE_RATIO", 1),
+	}
+
+	// Auth config
+	config.Auth = AuthConfig{
+		Disabled:     getBoolEnv("AUTH_DISABLED", false),
+		BootstrapKey: getStringEnv("AUTH_BOOTSTRAP_KEY", ""),
+		JWT: JWTConfig{
+			JWKSURL:     getStringEnv("AUTH_JWT_JWKS_URL", ""),
+			JWKSRefresh: getIntEnv("AUTH_JWT_JWKS_REFRESH", 3600),
+			Issuer:      getStringEnv("AUTH_JWT_ISSUER", ""),
+			Audience:    getStringEnv("AUTH_JWT_AUDIENCE", ""),
+			Leeway:      getIntEnv("AUTH_JWT_LEEWAY", 30),
+			TenantClaim: getStringEnv("AUTH_JWT_TENANT_CLAIM", "tenant"),
+			ScopeClaim:  getStringEnv("AUTH_JWT_SCOPE_CLAIM", "scope"),
+			ScopePrefix: getStringEnv("AUTH_JWT_SCOPE_PREFIX", ""),
+		},
+	}
+	if secret := getStringEnv("AUTH_JWT_SECRET", ""); secret != "" {
+		config.Auth.JWT.Keys = append(config.Auth.JWT.Keys, JWTKeyConfig{Secret: secret})
+	}
+	if file := getStringEnv("AUTH_JWT_PUBLIC_KEY_FILE", ""); file != "" {
+		config.Auth.JWT.Keys = append(config.Auth.JWT.Keys, JWTKeyConfig{PublicKeyFile: file})
+	}
+
+	// Limits config
+	config.Limits = LimitsConfig{
+		KeyRate:      getFloatEnv("RATE_LIMIT_KEY_RATE", 0),
+		KeyBurst:     getIntEnv("RATE_LIMIT_KEY_BURST", 20),
+		TenantRate:   getFloatEnv("RATE_LIMIT_TENANT_RATE", 0),
+		TenantBurst:  getIntEnv("RATE_LIMIT_TENANT_BURST", 100),
+		MonthlyQuota: getIntEnv("SEND_QUOTA_MONTHLY", 0),
+	}
+
 	return config
 }
 
@@ -236,6 +555,11 @@ func mergeStructs(dst, src reflect.Value) {
 			}
 		case reflect.Bool:
 			dstField.SetBool(srcField.Bool())
+		case reflect.Slice:
+			// Override if source slice is not empty
+			if srcField.Len() > 0 {
+				dstField.Set(srcField)
+			}
 		default:
 			// For other types, try direct assignment if possible
 			if dstField.CanSet() && srcField.Type() == dstField.Type() {
@@ -254,6 +578,21 @@ func getStringEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getStringSliceEnv retrieves a comma-separated environment variable with a default value
+func getStringSliceEnv(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var result []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+
+	return defaultValue
+}
+
 // getIntEnv retrieves an integer environment variable with a default value
 func getIntEnv(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
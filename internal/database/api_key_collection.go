@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const apiKeyCollectionName = "api_keys"
+
+func (database *Database) CreateAPIKey(ctx context.Context, key *models.APIKey) (*models.APIKey, error) {
+	key.CreatedAt = time.Now()
+
+	result, err := database.apiKeyCollection.InsertOne(ctx, key)
+	if err != nil {
+		slog.Error("Failed to insert API key", "error", err)
+		return nil, err
+	}
+
+	key.ID = result.InsertedID.(bson.ObjectID)
+	return key, nil
+}
+
+// ListAPIKeys returns the keys of tenant, or every key when tenant is empty
+func (database *Database) ListAPIKeys(ctx context.Context, tenant string) ([]*models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	filter := bson.M{}
+	if tenant != "" {
+		filter["tenant"] = tenant
+	}
+
+	cursor, err := database.apiKeyCollection.Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("Failed to list API keys", "error", err)
+		return nil, err
+	}
+
+	keys := make([]*models.APIKey, 0)
+	if err = cursor.All(ctx, &keys); err != nil {
+		slog.Error("Failed to decode API keys", "error", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetAPIKeyByHash returns the key that hashes to hash, revoked or not, or nil when there is none
+func (database *Database) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := database.apiKeyCollection.FindOne(ctx, bson.M{"hash": hash}).Decode(&key); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find API key", "error", err)
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// RevokeAPIKey marks the key revoked and returns it, revoking it again keeps the original time.
+// A non-empty tenant limits it to that tenant's keys. Returns nil when the key doesn't exist.
+func (database *Database) RevokeAPIKey(ctx context.Context, id bson.ObjectID, tenant string) (*models.APIKey, error) {
+	filter := bson.M{"_id": id}
+	if tenant != "" {
+		filter["tenant"] = tenant
+	}
+
+	_, err := database.apiKeyCollection.UpdateOne(
+		ctx,
+		bson.M{"$and": []bson.M{filter, {"revoked_at": bson.M{"$exists": false}}}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		slog.Error("Failed to revoke API key", "error", err)
+		return nil, err
+	}
+
+	var key models.APIKey
+	if err = database.apiKeyCollection.FindOne(ctx, filter).Decode(&key); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find API key", "error", err)
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// TouchAPIKey records that the key was used at usedAt
+func (database *Database) TouchAPIKey(ctx context.Context, id bson.ObjectID, usedAt time.Time) error {
+	_, err := database.apiKeyCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "$or": []bson.M{
+			{"last_used_at": bson.M{"$exists": false}},
+			{"last_used_at": bson.M{"$lt": usedAt}},
+		}},
+		bson.M{"$set": bson.M{"last_used_at": usedAt}})
+	if err != nil {
+		slog.Error("Failed to update API key last use", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (database *Database) initAPIKeyCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, apiKeyCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"name", "prefix", "hash", "scopes", "created_at"},
+			"properties": bson.M{
+				"name": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"maxLength":   255,
+					"description": "must be a string up to 255 characters and is required",
+				},
+				"prefix": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"hash": bson.M{
+					"bsonType":    "string",
+					"pattern":     "^[0-9a-f]{64}$",
+					"description": "must be a hex SHA-256 hash and is required",
+				},
+				"scopes": bson.M{
+					"bsonType": "array",
+					"minItems": 1,
+					"items": bson.M{
+						"bsonType": "string",
+						"enum":     []string{"send", "read", "admin"},
+					},
+					"description": "must be a non-empty array of scopes and is required",
+				},
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be the ID of the tenant the key acts for",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+				"last_used_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date",
+				},
+				"revoked_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(apiKeyCollectionName)
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Unique index for authenticating requests by key hash
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetName("hash_unique").SetUnique(true),
+		},
+		// Index for listing a tenant's keys
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("tenant_created_at").SetSparse(true),
+		},
+	})
+
+	return collection
+}
@@ -2,21 +2,38 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"time"
 
 	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/metrics"
+	"github.com/aarondever/notiflow/internal/tracing"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
 type Database struct {
-	Mongo           *mongo.Client
-	db              *mongo.Database
-	emailCollection *mongo.Collection
+	Mongo                     *mongo.Client
+	db                        *mongo.Database
+	emailCollection           *mongo.Collection
+	telegramCollection        *mongo.Collection
+	inboxCollection           *mongo.Collection
+	recipientCollection       *mongo.Collection
+	preferenceCollection      *mongo.Collection
+	suppressionCollection     *mongo.Collection
+	emailEventCollection      *mongo.Collection
+	webhookEndpointCollection *mongo.Collection
+	webhookDeliveryCollection *mongo.Collection
+	emailStatsCollection      *mongo.Collection
+	apiKeyCollection          *mongo.Collection
+	tenantCollection          *mongo.Collection
+	usageCollection           *mongo.Collection
 }
 
 func NewDatabase(config *config.Config) (*Database, error) {
@@ -31,7 +48,7 @@ func NewDatabase(config *config.Config) (*Database, error) {
 	)
 
 	// Connect to MongoDB
-	client, err := mongo.Connect(options.Client().ApplyURI(databaseURL))
+	client, err := mongo.Connect(options.Client().ApplyURI(databaseURL).SetMonitor(combineMonitors(metrics.MongoMonitor(), tracing.MongoMonitor())))
 	if err != nil {
 		slog.Error("Failed to connect to MongoDB", "error", err)
 		return nil, err
@@ -53,14 +70,38 @@ func NewDatabase(config *config.Config) (*Database, error) {
 
 	// Initialize collections
 	database.emailCollection = database.initEmailCollection(ctx)
+	database.telegramCollection = database.initTelegramCollection(ctx)
+	database.inboxCollection = database.initInboxCollection(ctx)
+	database.recipientCollection = database.initRecipientCollection(ctx)
+	database.preferenceCollection = database.initPreferenceCollection(ctx)
+	database.suppressionCollection = database.initSuppressionCollection(ctx)
+	database.emailEventCollection = database.initEmailEventCollection(ctx)
+	database.webhookEndpointCollection = database.initWebhookEndpointCollection(ctx)
+	database.webhookDeliveryCollection = database.initWebhookDeliveryCollection(ctx)
+	database.emailStatsCollection = database.initEmailStatsCollection(ctx)
+	database.apiKeyCollection = database.initAPIKeyCollection(ctx)
+	database.tenantCollection = database.initTenantCollection(ctx)
+	database.usageCollection = database.initUsageCollection(ctx)
 
 	return database, nil
 }
 
+// Ping checks that the primary is reachable
+func (database *Database) Ping(ctx context.Context) error {
+	return database.Mongo.Ping(ctx, readpref.Primary())
+}
+
 func (database *Database) createCollection(ctx context.Context, collectionName string, validator bson.M) {
-	// If collection exists, skip creation
+	// If collection exists, only bring its validator up to date
 	collections, _ := database.db.ListCollectionNames(ctx, bson.M{"name": collectionName})
 	if len(collections) > 0 {
+		// Users without the collMod privilege keep the previous validator, which only misses
+		// checks newer versions added
+		command := bson.D{{Key: "collMod", Value: collectionName}, {Key: "validator", Value: validator}}
+		if err := database.db.RunCommand(ctx, command).Err(); err != nil {
+			slog.Error("Failed to update collection validator, keeping the existing one", "collection", collectionName, "error", err)
+		}
+
 		return
 	}
 
@@ -83,3 +124,44 @@ func (database *Database) createIndexes(ctx context.Context, collection *mongo.C
 
 	slog.Info("Indexes created successfully", "collection", collection.Name())
 }
+
+// dropIndexes removes indexes that were replaced by others, ones that are already gone are skipped
+func (database *Database) dropIndexes(ctx context.Context, collection *mongo.Collection, names ...string) {
+	for _, name := range names {
+		err := collection.Indexes().DropOne(ctx, name)
+		var commandErr mongo.CommandError
+		if err == nil || (errors.As(err, &commandErr) && commandErr.Name == "IndexNotFound") {
+			continue
+		}
+
+		slog.Error("Failed to drop index", "collection", collection.Name(), "index", name, "error", err)
+		os.Exit(1)
+	}
+}
+
+// combineMonitors returns a monitor that notifies each of monitors in order
+func combineMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Started != nil {
+					monitor.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, monitor := range monitors {
+				if monitor.Succeeded != nil {
+					monitor.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Failed != nil {
+					monitor.Failed(ctx, e)
+				}
+			}
+		},
+	}
+}
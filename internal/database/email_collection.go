@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/aarondever/notiflow/internal/models"
@@ -13,7 +15,18 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-const emailCollectionName = "emails"
+const (
+	emailCollectionName = "emails"
+	// How often email watches query for changes when change streams aren't available
+	emailWatchPollInterval = 2 * time.Second
+	emailWatchClockSkew    = 5 * time.Second
+)
+
+// Server error code of a document rejected by the collection's validator
+const documentValidationFailure = 121
+
+// ErrInvalidEmail is returned for an email of a batch that the collection's validator rejected
+var ErrInvalidEmail = errors.New("email failed validation")
 
 func (database *Database) GetEmailByID(ctx context.Context, id string) (*models.Email, error) {
 	emailID, err := bson.ObjectIDFromHex(id)
@@ -22,8 +35,23 @@ func (database *Database) GetEmailByID(ctx context.Context, id string) (*models.
 		return nil, err
 	}
 
+	return database.findEmail(ctx, bson.M{"_id": emailID})
+}
+
+// GetTenantEmail returns the email with the given ID when it belongs to tenant, or nil
+func (database *Database) GetTenantEmail(ctx context.Context, tenant, id string) (*models.Email, error) {
+	emailID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		slog.Error("Failed to parse email ID", "error", err)
+		return nil, err
+	}
+
+	return database.findEmail(ctx, bson.M{"_id": emailID, "tenant": tenant})
+}
+
+func (database *Database) findEmail(ctx context.Context, filter bson.M) (*models.Email, error) {
 	var email models.Email
-	if err = database.emailCollection.FindOne(ctx, bson.M{"_id": emailID}).Decode(&email); err != nil {
+	if err := database.emailCollection.FindOne(ctx, filter).Decode(&email); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, nil
 		}
@@ -37,6 +65,7 @@ func (database *Database) GetEmailByID(ctx context.Context, id string) (*models.
 
 func (database *Database) CreateEmail(ctx context.Context, email *models.Email) (*models.Email, error) {
 	email.CreatedAt = time.Now()
+	email.UpdatedAt = email.CreatedAt
 	email.Status = models.StatusPending
 
 	result, err := database.emailCollection.InsertOne(ctx, email)
@@ -48,6 +77,46 @@ func (database *Database) CreateEmail(ctx context.Context, email *models.Email)
 	return database.GetEmailByID(ctx, result.InsertedID.(bson.ObjectID).Hex())
 }
 
+// CreateEmails stores new pending emails in bulk. The IDs are assigned up front, so the emails are
+// ready to dispatch without reading them back. errs holds the error of each email that wasn't
+// stored; err is set when the insert failed as a whole.
+func (database *Database) CreateEmails(ctx context.Context, emails []*models.Email) (errs []error, err error) {
+	// Stored dates have millisecond precision, keep the emails as they will be read back
+	now := time.Now().Truncate(time.Millisecond)
+	documents := make([]any, len(emails))
+	for i, email := range emails {
+		email.ID = bson.NewObjectID()
+		email.CreatedAt = now
+		email.UpdatedAt = now
+		email.Status = models.StatusPending
+		documents[i] = email
+	}
+
+	// Unordered, so one rejected email doesn't stop the ones after it
+	errs = make([]error, len(emails))
+	_, err = database.emailCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			if writeErr.Code == documentValidationFailure {
+				errs[writeErr.Index] = ErrInvalidEmail
+			} else {
+				errs[writeErr.Index] = writeErr
+			}
+		}
+
+		slog.Error("Failed to insert some emails", "failed", len(bulkErr.WriteErrors), "error", err)
+		return errs, nil
+	}
+	if err != nil {
+		slog.Error("Failed to insert emails", "error", err)
+		return nil, err
+	}
+
+	return errs, nil
+}
+
 func (database *Database) UpdateEmailFail(ctx context.Context, email *models.Email) (*models.Email, error) {
 	if email.ID == bson.NilObjectID {
 		return nil, fmt.Errorf("ID is required for updating an email")
@@ -56,16 +125,96 @@ func (database *Database) UpdateEmailFail(ctx context.Context, email *models.Ema
 	_, err := database.emailCollection.UpdateOne(
 		ctx,
 		bson.M{"_id": email.ID},
-		bson.M{"$set": bson.M{"status": models.StatusFailed, "error_message": email.ErrorMsg}})
+		bson.M{"$set": bson.M{"status": models.StatusFailed, "error_message": email.ErrorMsg, "updated_at": time.Now()}})
+	if err != nil {
+		slog.Error("Failed to update email", "error", err)
+		return nil, err
+	}
+
+	return database.GetEmailByID(ctx, email.ID.Hex())
+}
+
+// UpdateEmailDelivery records the outcome of sending: the per-recipient results and the status
+// aggregated from them
+func (database *Database) UpdateEmailDelivery(ctx context.Context, email *models.Email) (*models.Email, error) {
+	if email.ID == bson.NilObjectID {
+		return nil, fmt.Errorf("ID is required for updating an email")
+	}
+
+	set := bson.M{"status": email.Status, "recipients": email.Recipients, "updated_at": time.Now()}
+	if !email.SentAt.IsZero() {
+		set["sent_at"] = email.SentAt
+	}
+	if email.ErrorMsg != "" {
+		set["error_message"] = email.ErrorMsg
+	}
+
+	_, err := database.emailCollection.UpdateOne(ctx, bson.M{"_id": email.ID}, bson.M{"$set": set})
 	if err != nil {
 		slog.Error("Failed to update email", "error", err)
 		return nil, err
 	}
 
+	// An email delivered to at least one recipient no longer needs its fallback plan
+	if email.Status == models.StatusSent || email.Status == models.StatusPartial {
+		_, err = database.emailCollection.UpdateOne(
+			ctx,
+			bson.M{"_id": email.ID, "fallback.state": models.FallbackStateWaiting},
+			bson.M{"$set": bson.M{"fallback.state": models.FallbackStateResolved, "updated_at": time.Now()}})
+		if err != nil {
+			slog.Error("Failed to resolve email fallback plan", "error", err)
+			return nil, err
+		}
+	}
+
 	return database.GetEmailByID(ctx, email.ID.Hex())
 }
 
-func (database *Database) UpdateEmailSent(ctx context.Context, email *models.Email) (*models.Email, error) {
+// AddEmailRecipients appends recipients to an email, such as the addresses of a fallback resend
+func (database *Database) AddEmailRecipients(ctx context.Context, id bson.ObjectID, recipients []models.EmailRecipient) error {
+	_, err := database.emailCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$push": bson.M{"recipients": bson.M{"$each": recipients}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		})
+	if err != nil {
+		slog.Error("Failed to add email recipients", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateEmailRecipients records the address a recipient profile resolved to at dispatch time,
+// the addresses removed before sending and the initial per-recipient state
+func (database *Database) UpdateEmailRecipients(ctx context.Context, email *models.Email) error {
+	set := bson.M{}
+	if len(email.To) > 0 {
+		set["to"] = email.To
+	}
+	if len(email.Suppressed) > 0 {
+		set["suppressed_recipients"] = email.Suppressed
+	}
+	if len(email.Recipients) > 0 {
+		set["recipients"] = email.Recipients
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	set["updated_at"] = time.Now()
+
+	_, err := database.emailCollection.UpdateOne(ctx, bson.M{"_id": email.ID}, bson.M{"$set": set})
+	if err != nil {
+		slog.Error("Failed to update email recipients", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (database *Database) UpdateEmailSuppressed(ctx context.Context, email *models.Email) (*models.Email, error) {
 	if email.ID == bson.NilObjectID {
 		return nil, fmt.Errorf("ID is required for updating an email")
 	}
@@ -73,21 +222,427 @@ func (database *Database) UpdateEmailSent(ctx context.Context, email *models.Ema
 	_, err := database.emailCollection.UpdateOne(
 		ctx,
 		bson.M{"_id": email.ID},
-		bson.M{"$set": bson.M{"status": models.StatusSent, "sent_at": email.SentAt}})
+		bson.M{"$set": bson.M{
+			"status":                models.StatusSuppressed,
+			"error_message":         email.ErrorMsg,
+			"suppressed_recipients": email.Suppressed,
+			"recipients":            email.Recipients,
+			"updated_at":            time.Now(),
+		}})
 	if err != nil {
 		slog.Error("Failed to update email", "error", err)
 		return nil, err
 	}
 
+	// Nothing to escalate for an email that was deliberately not sent
+	_, err = database.emailCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": email.ID, "fallback.state": models.FallbackStateWaiting},
+		bson.M{"$set": bson.M{"fallback.state": models.FallbackStateResolved, "updated_at": time.Now()}})
+	if err != nil {
+		slog.Error("Failed to resolve email fallback plan", "error", err)
+		return nil, err
+	}
+
 	return database.GetEmailByID(ctx, email.ID.Hex())
 }
 
+// AddEmailBounces records bounce reports on an email and attaches the latest report to each of the
+// recipients it's about, keyed by their stored address. Recipients are updated in place through array
+// filters so concurrent delivery and engagement updates aren't lost. Permanent bounces mark recipients
+// that were sent to as bounced and the email status is aggregated again. Emails sent before per-recipient
+// tracking have no recipients, only the reports are added.
+func (database *Database) AddEmailBounces(ctx context.Context, id bson.ObjectID, bounces []models.Bounce, recipients map[string]models.Bounce) (*models.Email, error) {
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	var filters []any
+	for i, address := range slices.Sorted(maps.Keys(recipients)) {
+		reported := recipients[address]
+
+		identifier := fmt.Sprintf("r%d", i)
+		set["recipients.$["+identifier+"].bounce"] = reported
+		set["recipients.$["+identifier+"].updated_at"] = now
+		filters = append(filters, bson.M{identifier + ".address": address})
+
+		if reported.Permanent {
+			sent := fmt.Sprintf("s%d", i)
+			set["recipients.$["+sent+"].status"] = models.RecipientBounced
+			filters = append(filters, bson.M{sent + ".address": address, sent + ".status": models.RecipientSent})
+		}
+	}
+
+	opts := options.UpdateOne()
+	if len(filters) > 0 {
+		opts.SetArrayFilters(filters)
+	}
+
+	_, err := database.emailCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$push": bson.M{"bounces": bson.M{"$each": bounces}}, "$set": set},
+		opts)
+	if err != nil {
+		slog.Error("Failed to add email bounces", "error", err)
+		return nil, err
+	}
+
+	updated, err := database.GetEmailByID(ctx, id.Hex())
+	if err != nil || updated == nil || len(updated.Recipients) == 0 {
+		return updated, err
+	}
+
+	// Only move the status on if nothing else changed it since it was read
+	status := models.AggregateStatus(updated.Recipients)
+	if status != updated.Status {
+		result, err := database.emailCollection.UpdateOne(
+			ctx,
+			bson.M{"_id": id, "status": updated.Status},
+			bson.M{"$set": bson.M{"status": status, "updated_at": time.Now()}})
+		if err != nil {
+			slog.Error("Failed to update email status", "error", err)
+			return nil, err
+		}
+		if result.ModifiedCount > 0 {
+			updated.Status = status
+		}
+	}
+
+	// Delivery resolved the fallback plan, every recipient bouncing puts it back in play. Plans
+	// resolved by a resend step have escalated already.
+	if updated.Status == models.StatusBounced {
+		_, err = database.emailCollection.UpdateOne(
+			ctx,
+			bson.M{"_id": id, "fallback.state": models.FallbackStateResolved, "fallback.escalated_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"fallback.state": models.FallbackStateWaiting, "updated_at": time.Now()}})
+		if err != nil {
+			slog.Error("Failed to reopen email fallback plan", "error", err)
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+func (database *Database) AddEmailComplaint(ctx context.Context, id bson.ObjectID, complaint models.Complaint) error {
+	_, err := database.emailCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$push": bson.M{"complaints": complaint},
+			"$set":  bson.M{"updated_at": time.Now()},
+		})
+	if err != nil {
+		slog.Error("Failed to add email complaint", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RecordEmailOpen counts an open by one of the email's recipients
+func (database *Database) RecordEmailOpen(ctx context.Context, id bson.ObjectID, address string, openedAt time.Time) error {
+	if err := database.countRecipientEngagement(ctx, id, address, "open_count", "first_opened_at", "last_opened_at", openedAt); err != nil {
+		slog.Error("Failed to record email open", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RecordEmailClick counts a tracked link click by one of the email's recipients
+func (database *Database) RecordEmailClick(ctx context.Context, id bson.ObjectID, address string, clickedAt time.Time) error {
+	if err := database.countRecipientEngagement(ctx, id, address, "click_count", "first_clicked_at", "last_clicked_at", clickedAt); err != nil {
+		slog.Error("Failed to record email click", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// countRecipientEngagement increments a recipient's counter and widens its first/last timestamps
+func (database *Database) countRecipientEngagement(ctx context.Context, id bson.ObjectID, address, counter, first, last string, at time.Time) error {
+	_, err := database.emailCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$inc": bson.M{"recipients.$[recipient]." + counter: 1},
+			"$min": bson.M{"recipients.$[recipient]." + first: at},
+			"$max": bson.M{"recipients.$[recipient]." + last: at},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+		options.UpdateOne().SetArrayFilters([]any{bson.M{"recipient.address": address}}))
+
+	return err
+}
+
+// CountEmailsByStatus returns the number of stored emails per status
+func (database *Database) CountEmailsByStatus(ctx context.Context) (map[string]int64, error) {
+	statuses := []models.EmailStatus{
+		models.StatusPending, models.StatusSent, models.StatusFailed,
+		models.StatusSuppressed, models.StatusBounced, models.StatusPartial,
+	}
+
+	counts := make(map[string]int64, len(statuses))
+	for _, status := range statuses {
+		count, err := database.emailCollection.CountDocuments(ctx, bson.M{"status": status})
+		if err != nil {
+			slog.Error("Failed to count emails", "error", err, "status", status)
+			return nil, err
+		}
+		counts[string(status)] = count
+	}
+
+	return counts, nil
+}
+
+// CountStalePendingEmails counts the emails created before the given time that are still pending
+func (database *Database) CountStalePendingEmails(ctx context.Context, before time.Time) (int64, error) {
+	count, err := database.emailCollection.CountDocuments(ctx, bson.M{"status": models.StatusPending, "created_at": bson.M{"$lt": before}})
+	if err != nil {
+		slog.Error("Failed to count pending emails", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ClaimEmailForEscalation locks the next email whose fallback plan is due: the primary send failed
+// permanently or bounced, its escalation deadline passed, or a previous run was interrupted. Returns
+// nil when there is none.
+func (database *Database) ClaimEmailForEscalation(ctx context.Context, now time.Time, lease time.Duration) (*models.Email, error) {
+	// Failed recipients without a 5xx reply were deferred or never reached a server, only the
+	// escalation deadline escalates those
+	transient := bson.M{"$elemMatch": bson.M{"status": models.RecipientFailed, "smtp_code": bson.M{"$not": bson.M{"$gte": 500}}}}
+
+	filter := bson.M{
+		"fallback.locked_until": bson.M{"$not": bson.M{"$gt": now}},
+		"$or": []bson.M{
+			{"fallback.state": models.FallbackStateWaiting, "status": models.StatusFailed, "recipients": bson.M{"$not": transient}},
+			{"fallback.state": models.FallbackStateWaiting, "status": models.StatusBounced},
+			{"fallback.state": models.FallbackStateWaiting, "status": bson.M{"$in": []models.EmailStatus{models.StatusPending, models.StatusFailed}}, "fallback.escalate_at": bson.M{"$lte": now}},
+			{"fallback.state": models.FallbackStateEscalating},
+		},
+	}
+
+	var email models.Email
+	err := database.emailCollection.FindOneAndUpdate(
+		ctx,
+		filter,
+		bson.M{"$set": bson.M{"fallback.locked_until": now.Add(lease)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to claim email for escalation", "error", err)
+		return nil, err
+	}
+
+	return &email, nil
+}
+
+// UpdateEmailFallback persists the fallback plan progress, releasing the escalation lock
+func (database *Database) UpdateEmailFallback(ctx context.Context, id bson.ObjectID, plan *models.FallbackPlan) error {
+	plan.LockedUntil = time.Time{}
+
+	_, err := database.emailCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"fallback": plan, "updated_at": time.Now()}})
+	if err != nil {
+		slog.Error("Failed to update email fallback plan", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// WatchEmails streams every insert or update of the emails matching filter until ctx is done, leaving
+// out attachment content. It reads a change stream, and polls updated_at instead on a standalone
+// mongod, where change streams aren't available. A state may be delivered more than once.
+func (database *Database) WatchEmails(ctx context.Context, filter *models.EmailWatchFilter) <-chan *models.Email {
+	emails := make(chan *models.Email)
+
+	go func() {
+		defer close(emails)
+
+		since := time.Now()
+		stream, err := database.emailCollection.Watch(
+			ctx,
+			emailChangePipeline(filter),
+			options.ChangeStream().SetFullDocument(options.UpdateLookup))
+		if err == nil {
+			err = streamEmailChanges(ctx, stream, emails, &since)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Debug("Email change stream unavailable, polling for changes", "error", err)
+		database.pollEmailChanges(ctx, filter, since, emails)
+	}()
+
+	return emails
+}
+
+// streamEmailChanges forwards change stream events until ctx is done or the stream fails,
+// advancing since to the latest change seen
+func streamEmailChanges(ctx context.Context, stream *mongo.ChangeStream, emails chan<- *models.Email, since *time.Time) error {
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var change struct {
+			FullDocument *models.Email `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			slog.Error("Failed to decode email change", "error", err)
+			continue
+		}
+		// The email was deleted before its update could be looked up
+		if change.FullDocument == nil {
+			continue
+		}
+
+		if change.FullDocument.UpdatedAt.After(*since) {
+			*since = change.FullDocument.UpdatedAt
+		}
+
+		select {
+		case emails <- change.FullDocument:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return stream.Err()
+}
+
+// pollEmailChanges queries for emails updated after since until ctx is done. The window reaches
+// back emailWatchClockSkew so writes from instances whose clock runs behind aren't missed.
+func (database *Database) pollEmailChanges(ctx context.Context, filter *models.EmailWatchFilter, since time.Time, emails chan<- *models.Email) {
+	ticker := time.NewTicker(emailWatchPollInterval)
+	defer ticker.Stop()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"attachments.content": 0})
+
+	// Latest state delivered per email still inside the window
+	delivered := make(map[bson.ObjectID]time.Time)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		query := emailWatchQuery(filter)
+		query["updated_at"] = bson.M{"$gt": since.Add(-emailWatchClockSkew)}
+
+		cursor, err := database.emailCollection.Find(ctx, query, opts)
+		if err != nil {
+			slog.Error("Failed to poll email changes", "error", err)
+			continue
+		}
+
+		var changed []*models.Email
+		if err = cursor.All(ctx, &changed); err != nil {
+			slog.Error("Failed to decode email changes", "error", err)
+			continue
+		}
+
+		for _, email := range changed {
+			if last, ok := delivered[email.ID]; ok && !email.UpdatedAt.After(last) {
+				continue
+			}
+			delivered[email.ID] = email.UpdatedAt
+			if email.UpdatedAt.After(since) {
+				since = email.UpdatedAt
+			}
+
+			select {
+			case emails <- email:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for id, updatedAt := range delivered {
+			if !updatedAt.After(since.Add(-emailWatchClockSkew)) {
+				delete(delivered, id)
+			}
+		}
+	}
+}
+
+// emailChangePipeline matches inserts and the updates that changed an email, fallback plan locks
+// don't touch updated_at and are left out
+func emailChangePipeline(filter *models.EmailWatchFilter) mongo.Pipeline {
+	match := bson.M{"$or": []bson.M{
+		{"operationType": "insert"},
+		{"operationType": "update", "updateDescription.updatedFields.updated_at": bson.M{"$exists": true}},
+	}}
+	for key, value := range emailWatchQuery(filter) {
+		match["fullDocument."+key] = value
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$project", Value: bson.M{"fullDocument.attachments.content": 0}}},
+	}
+}
+
+func emailWatchQuery(filter *models.EmailWatchFilter) bson.M {
+	query := bson.M{}
+	if filter.Tenant != "" {
+		query["tenant"] = filter.Tenant
+	}
+	if !filter.ID.IsZero() {
+		query["_id"] = filter.ID
+	}
+	if len(filter.Statuses) > 0 {
+		query["status"] = bson.M{"$in": filter.Statuses}
+	}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+
+	return query
+}
+
 func (database *Database) initEmailCollection(ctx context.Context) *mongo.Collection {
 	database.createCollection(ctx, emailCollectionName, bson.M{
 		"$jsonSchema": bson.M{
 			"bsonType": "object",
-			"required": []string{"to", "subject", "body", "status", "created_at", "is_html"},
+			"required": []string{"subject", "body", "status", "created_at", "is_html"},
+			// Either explicit addresses or a recipient profile to resolve them from
+			"anyOf": []bson.M{
+				{"required": []string{"to"}},
+				{"required": []string{"user_id"}},
+			},
 			"properties": bson.M{
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"pattern":     "^[a-z0-9][a-z0-9_-]{0,62}$",
+					"description": "must be the ID of the tenant the email belongs to",
+				},
+				"from": bson.M{
+					"bsonType":    "string",
+					"maxLength":   320,
+					"description": "must be a sender address",
+				},
+				"user_id": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"maxLength":   255,
+					"description": "must be a recipient user ID",
+				},
 				"to": bson.M{
 					"bsonType": "array",
 					"minItems": 1,
@@ -96,7 +651,7 @@ func (database *Database) initEmailCollection(ctx context.Context) *mongo.Collec
 						"bsonType": "string",
 						"pattern":  "^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$",
 					},
-					"description": "must be an array of valid email addresses and is required",
+					"description": "must be an array of valid email addresses, required unless user_id is set",
 				},
 				"cc": bson.M{
 					"bsonType": "array",
@@ -134,8 +689,8 @@ func (database *Database) initEmailCollection(ctx context.Context) *mongo.Collec
 				},
 				"status": bson.M{
 					"bsonType":    "string",
-					"enum":        []string{"pending", "sent", "failed"},
-					"description": "must be one of: pending, sent, failed",
+					"enum":        []string{"pending", "sent", "failed", "suppressed", "bounced", "partial"},
+					"description": "must be one of: pending, sent, failed, suppressed, bounced, partial",
 				},
 				"error_message": bson.M{
 					"bsonType":    "string",
@@ -150,6 +705,10 @@ func (database *Database) initEmailCollection(ctx context.Context) *mongo.Collec
 					"bsonType":    "date",
 					"description": "must be a date",
 				},
+				"updated_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date",
+				},
 				"attachments": bson.M{
 					"bsonType": "array",
 					"maxItems": 10,
@@ -177,27 +736,155 @@ func (database *Database) initEmailCollection(ctx context.Context) *mongo.Collec
 					},
 					"description": "must be an array of attachment objects (max 10)",
 				},
+				"category": bson.M{
+					"bsonType":    "string",
+					"pattern":     "^[a-z0-9_-]{1,64}$",
+					"description": "must be a lower-case category name up to 64 characters",
+				},
+				"created_by": bson.M{
+					"bsonType": "object",
+					"required": []string{"kind", "id"},
+					"properties": bson.M{
+						"kind": bson.M{
+							"bsonType": "string",
+							"enum":     []string{"api_key", "bootstrap", "jwt", "certificate"},
+						},
+						"id": bson.M{
+							"bsonType": "string",
+						},
+						"tenant": bson.M{
+							"bsonType": "string",
+						},
+					},
+					"description": "must be the principal that created the email",
+				},
+				"track_opens": bson.M{
+					"bsonType":    "bool",
+					"description": "must be a boolean",
+				},
+				"suppressed_recipients": bson.M{
+					"bsonType": "array",
+					"maxItems": 200,
+					"items": bson.M{
+						"bsonType": "object",
+						"required": []string{"address", "reason"},
+					},
+					"description": "must be an array of addresses removed before dispatch",
+				},
+				"bounces": bson.M{
+					"bsonType": "array",
+					"items": bson.M{
+						"bsonType": "object",
+						"required": []string{"address", "action", "status", "reported_at"},
+					},
+					"description": "must be an array of bounce reports",
+				},
+				"complaints": bson.M{
+					"bsonType": "array",
+					"items": bson.M{
+						"bsonType": "object",
+						"required": []string{"feedback_type", "reported_at"},
+					},
+					"description": "must be an array of feedback loop reports",
+				},
+				"recipients": bson.M{
+					"bsonType": "array",
+					"maxItems": 200,
+					"items": bson.M{
+						"bsonType": "object",
+						"required": []string{"address", "kind", "status", "updated_at"},
+						"properties": bson.M{
+							"kind": bson.M{
+								"enum": []string{"to", "cc", "bcc", "fallback"},
+							},
+							"status": bson.M{
+								"enum": []string{"pending", "sent", "failed", "suppressed", "bounced"},
+							},
+						},
+					},
+					"description": "must be an array of per-recipient delivery states",
+				},
+				"fallback": bson.M{
+					"bsonType": "object",
+					"required": []string{"steps", "state", "current_step"},
+					"properties": bson.M{
+						"steps": bson.M{
+							"bsonType": "array",
+							"minItems": 1,
+							"maxItems": 10,
+						},
+						"state": bson.M{
+							"bsonType": "string",
+							"enum":     []string{"waiting", "escalating", "resolved", "exhausted"},
+						},
+						"current_step": bson.M{
+							"bsonType": []string{"int", "long"},
+							"minimum":  0,
+						},
+					},
+					"description": "must be a fallback plan object",
+				},
 			},
 		},
 	})
 
 	collection := database.db.Collection(emailCollectionName)
 
+	database.backfillTenant(ctx, collection, bson.M{})
+
+	// Replaced by the tenant-scoped indexes below
+	database.dropIndexes(ctx, collection, "to_asc", "category_asc", "created_by_id_asc", "user_id_asc")
+
 	database.createIndexes(ctx, collection, []mongo.IndexModel{
 		// Index on created_at for chronological queries
 		{
 			Keys:    bson.D{{Key: "created_at", Value: -1}},
 			Options: options.Index().SetName("created_at_desc"),
 		},
+		// Index for a tenant's emails in chronological order
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("tenant_created_at_desc"),
+		},
 		// Index on status for filtering by email status
 		{
 			Keys:    bson.D{{Key: "status", Value: 1}},
 			Options: options.Index().SetName("status_asc"),
 		},
-		// Index on to field for finding emails by recipient
+		// Index on updated_at for the polling fallback of email watches
+		{
+			Keys:    bson.D{{Key: "updated_at", Value: 1}},
+			Options: options.Index().SetName("updated_at_asc").SetSparse(true),
+		},
+		// Index for finding a tenant's emails by recipient
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "to", Value: 1}},
+			Options: options.Index().SetName("tenant_to"),
+		},
+		// Partial index for the escalation dispatcher, only emails with an active fallback plan
+		{
+			Keys: bson.D{
+				{Key: "fallback.state", Value: 1},
+				{Key: "fallback.escalate_at", Value: 1},
+			},
+			Options: options.Index().
+				SetName("fallback_state_escalate_at").
+				SetPartialFilterExpression(bson.M{"fallback.state": bson.M{"$in": []string{"waiting", "escalating"}}}),
+		},
+		// Index for filtering and reporting a tenant's emails by category
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "category", Value: 1}},
+			Options: options.Index().SetName("tenant_category"),
+		},
+		// Index for finding the emails sent with an API key
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "created_by.id", Value: 1}},
+			Options: options.Index().SetName("tenant_created_by_id"),
+		},
+		// Index for finding a tenant's emails by recipient profile
 		{
-			Keys:    bson.D{{Key: "to", Value: 1}},
-			Options: options.Index().SetName("to_asc"),
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("tenant_user_id"),
 		},
 		// Text index for full-text search on subject and body
 		{
@@ -0,0 +1,331 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const emailEventCollectionName = "email_events"
+
+// CreateEmailEvents appends events to their emails' timelines
+func (database *Database) CreateEmailEvents(ctx context.Context, events []*models.EmailEvent) error {
+	now := time.Now()
+	documents := make([]any, len(events))
+	for i, event := range events {
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = now
+		}
+		documents[i] = event
+	}
+
+	if _, err := database.emailEventCollection.InsertMany(ctx, documents); err != nil {
+		slog.Error("Failed to insert email events", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListEmailEvents returns an email's timeline oldest first
+func (database *Database) ListEmailEvents(ctx context.Context, emailID bson.ObjectID) ([]*models.EmailEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := database.emailEventCollection.Find(ctx, bson.M{"email_id": emailID}, opts)
+	if err != nil {
+		slog.Error("Failed to list email events", "error", err)
+		return nil, err
+	}
+
+	events := make([]*models.EmailEvent, 0)
+	if err = cursor.All(ctx, &events); err != nil {
+		slog.Error("Failed to decode email events", "error", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// HasRecentEmailEvent reports whether an event of the same type, recipient, IP hash and URL was
+// recorded for the email since the given time
+func (database *Database) HasRecentEmailEvent(ctx context.Context, event *models.EmailEvent, since time.Time) (bool, error) {
+	filter := bson.M{
+		"email_id":   event.EmailID,
+		"type":       event.Type,
+		"recipient":  event.Recipient,
+		"ip_hash":    event.IPHash,
+		"created_at": bson.M{"$gte": since},
+	}
+	if event.URL != "" {
+		filter["url"] = event.URL
+	}
+
+	count, err := database.emailEventCollection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		slog.Error("Failed to count email events", "error", err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// AggregateEmailClicks summarizes an email's clicks per link and per recipient, prefetches excluded
+func (database *Database) AggregateEmailClicks(ctx context.Context, emailID bson.ObjectID) (*models.EmailClickStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"email_id": emailID, "type": models.EventClicked, "prefetch": bson.M{"$ne": true}}}},
+		{{Key: "$facet", Value: bson.M{
+			"links": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":              "$url",
+					"clicks":           bson.M{"$sum": 1},
+					"recipients":       bson.M{"$addToSet": "$recipient"},
+					"first_clicked_at": bson.M{"$min": "$created_at"},
+					"last_clicked_at":  bson.M{"$max": "$created_at"},
+				}},
+				bson.M{"$project": bson.M{
+					"_id":               0,
+					"url":               "$_id",
+					"clicks":            1,
+					"unique_recipients": bson.M{"$size": "$recipients"},
+					"first_clicked_at":  1,
+					"last_clicked_at":   1,
+				}},
+				bson.M{"$sort": bson.D{{Key: "clicks", Value: -1}, {Key: "url", Value: 1}}},
+			},
+			"recipients": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":             "$recipient",
+					"clicks":          bson.M{"$sum": 1},
+					"links":           bson.M{"$addToSet": "$url"},
+					"last_clicked_at": bson.M{"$max": "$created_at"},
+				}},
+				bson.M{"$project": bson.M{
+					"_id":             0,
+					"address":         "$_id",
+					"clicks":          1,
+					"unique_links":    bson.M{"$size": "$links"},
+					"last_clicked_at": 1,
+				}},
+				bson.M{"$sort": bson.D{{Key: "clicks", Value: -1}, {Key: "address", Value: 1}}},
+			},
+		}}},
+	}
+
+	cursor, err := database.emailEventCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("Failed to aggregate email clicks", "error", err)
+		return nil, err
+	}
+
+	var results []*models.EmailClickStats
+	if err = cursor.All(ctx, &results); err != nil {
+		slog.Error("Failed to decode email clicks", "error", err)
+		return nil, err
+	}
+
+	stats := &models.EmailClickStats{EmailID: emailID.Hex()}
+	if len(results) > 0 {
+		stats.Links, stats.Recipients = results[0].Links, results[0].Recipients
+	}
+	if stats.Links == nil {
+		stats.Links = make([]*models.LinkClickStats, 0)
+	}
+	if stats.Recipients == nil {
+		stats.Recipients = make([]*models.RecipientClickStats, 0)
+	}
+
+	return stats, nil
+}
+
+// AggregateCategoryClicks summarizes the tenant's clicks per email category within [since, until),
+// with the most clicked links of each category. An empty category matches all categories, zero
+// times are unbounded.
+func (database *Database) AggregateCategoryClicks(ctx context.Context, tenant, category string, since, until time.Time, topLinks int) ([]*models.CategoryClickStats, error) {
+	match := bson.M{"tenant": tenant, "type": models.EventClicked, "prefetch": bson.M{"$ne": true}}
+	if category != "" {
+		match["category"] = category
+	}
+	createdAt := bson.M{}
+	if !since.IsZero() {
+		createdAt["$gte"] = since
+	}
+	if !until.IsZero() {
+		createdAt["$lt"] = until
+	}
+	if len(createdAt) > 0 {
+		match["created_at"] = createdAt
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":              bson.M{"category": bson.M{"$ifNull": bson.A{"$category", ""}}, "url": "$url"},
+			"clicks":           bson.M{"$sum": 1},
+			"unique":           bson.M{"$addToSet": bson.M{"email_id": "$email_id", "recipient": "$recipient"}},
+			"first_clicked_at": bson.M{"$min": "$created_at"},
+			"last_clicked_at":  bson.M{"$max": "$created_at"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"clicks":            1,
+			"unique_recipients": bson.M{"$size": "$unique"},
+			"first_clicked_at":  1,
+			"last_clicked_at":   1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "clicks", Value: -1}, {Key: "_id.url", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           "$_id.category",
+			"clicks":        bson.M{"$sum": "$clicks"},
+			"unique_clicks": bson.M{"$sum": "$unique_recipients"},
+			"links": bson.M{"$push": bson.M{
+				"url":               "$_id.url",
+				"clicks":            "$clicks",
+				"unique_recipients": "$unique_recipients",
+				"first_clicked_at":  "$first_clicked_at",
+				"last_clicked_at":   "$last_clicked_at",
+			}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":           0,
+			"category":      "$_id",
+			"clicks":        1,
+			"unique_clicks": 1,
+			"links":         bson.M{"$slice": bson.A{"$links", topLinks}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "clicks", Value: -1}, {Key: "category", Value: 1}}}},
+	}
+
+	cursor, err := database.emailEventCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		slog.Error("Failed to aggregate category clicks", "error", err)
+		return nil, err
+	}
+
+	categories := make([]*models.CategoryClickStats, 0)
+	if err = cursor.All(ctx, &categories); err != nil {
+		slog.Error("Failed to decode category clicks", "error", err)
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (database *Database) initEmailEventCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, emailEventCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"email_id", "type", "created_at"},
+			"properties": bson.M{
+				"email_id": bson.M{
+					"bsonType":    "objectId",
+					"description": "must be an email ID and is required",
+				},
+				"type": bson.M{
+					"bsonType": "string",
+					"enum": []string{
+						"queued", "suppressed", "rendering", "dispatched", "accepted", "refused", "failed",
+						"retry_scheduled", "escalated", "bounced", "complained", "opened", "clicked",
+					},
+					"description": "must be a known event type and is required",
+				},
+				"recipient": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string",
+				},
+				"server": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string",
+				},
+				"smtp_code": bson.M{
+					"bsonType":    []string{"int", "long"},
+					"description": "must be an SMTP reply code",
+				},
+				"queue_id": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string",
+				},
+				"detail": bson.M{
+					"bsonType":    "string",
+					"maxLength":   2000,
+					"description": "must be a string up to 2000 characters",
+				},
+				"user_agent": bson.M{
+					"bsonType":    "string",
+					"maxLength":   512,
+					"description": "must be a string up to 512 characters",
+				},
+				"ip_hash": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string",
+				},
+				"prefetch": bson.M{
+					"bsonType":    "bool",
+					"description": "must be a boolean",
+				},
+				"url": bson.M{
+					"bsonType":    "string",
+					"maxLength":   2048,
+					"description": "must be a string up to 2048 characters",
+				},
+				"category": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string",
+				},
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(emailEventCollectionName)
+
+	// Only opens and clicks carry their email's tenant
+	database.backfillTenant(ctx, collection, bson.M{"type": bson.M{"$in": []models.EmailEventType{models.EventOpened, models.EventClicked}}})
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Index for reading an email's timeline in order
+		{
+			Keys: bson.D{
+				{Key: "email_id", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("email_id_created_at"),
+		},
+		// Index for engagement reports over a time range
+		{
+			Keys: bson.D{
+				{Key: "type", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("type_created_at"),
+		},
+		// Index for a tenant's engagement reports
+		{
+			Keys: bson.D{
+				{Key: "tenant", Value: 1},
+				{Key: "type", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("tenant_type_created_at").SetSparse(true),
+		},
+		// TTL Index, events expire with their emails (90 days)
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().
+				SetName("email_events_ttl").
+				SetExpireAfterSeconds(90 * 24 * 60 * 60), // 90 days
+		},
+	})
+
+	return collection
+}
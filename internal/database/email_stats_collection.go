@@ -0,0 +1,304 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const emailStatsCollectionName = "email_stats"
+
+// EmailStatsHours returns the creation hours of the emails updated since the given time, oldest
+// first. A zero time returns the hours of all emails.
+func (database *Database) EmailStatsHours(ctx context.Context, updatedSince time.Time) ([]time.Time, error) {
+	match := bson.M{}
+	if !updatedSince.IsZero() {
+		match["updated_at"] = bson.M{"$gte": updatedSince}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"$dateTrunc": bson.M{"date": "$created_at", "unit": "hour"}}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := database.emailCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		slog.Error("Failed to aggregate email hours", "error", err)
+		return nil, err
+	}
+
+	var results []struct {
+		Hour time.Time `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		slog.Error("Failed to decode email hours", "error", err)
+		return nil, err
+	}
+
+	hours := make([]time.Time, len(results))
+	for i, result := range results {
+		hours[i] = result.Hour
+	}
+
+	return hours, nil
+}
+
+// RollupEmailStats recomputes the rollups of the emails created in the hour starting at hour,
+// one per tenant, category, server, sender and recipient domain
+func (database *Database) RollupEmailStats(ctx context.Context, hour time.Time) error {
+	now := time.Now()
+	countStatus := func(status models.RecipientStatus) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$recipients.status", status}}, 1, 0}}}
+	}
+	countTracked := func(field string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{
+			bson.M{"$and": bson.A{
+				bson.M{"$eq": bson.A{"$" + field, true}},
+				bson.M{"$eq": bson.A{"$recipients.status", models.RecipientSent}},
+			}},
+			1, 0,
+		}}}
+	}
+	countEngaged := func(field string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{bson.M{"$ifNull": bson.A{"$recipients." + field, 0}}, 0}}, 1, 0}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": hour, "$lt": hour.Add(time.Hour)}}}},
+		{{Key: "$project", Value: bson.M{
+			"tenant":       bson.M{"$ifNull": bson.A{"$tenant", models.DefaultTenant}},
+			"category":     bson.M{"$ifNull": bson.A{"$category", ""}},
+			"track_opens":  1,
+			"track_clicks": 1,
+			"recipients":   1,
+		}}},
+		{{Key: "$unwind", Value: "$recipients"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"tenant":   "$tenant",
+				"category": "$category",
+				"server":   bson.M{"$ifNull": bson.A{"$recipients.server", ""}},
+				"sender":   bson.M{"$ifNull": bson.A{"$recipients.sender", ""}},
+				"domain":   bson.M{"$toLower": bson.M{"$arrayElemAt": bson.A{bson.M{"$split": bson.A{"$recipients.address", "@"}}, -1}}},
+			},
+			"pending":       countStatus(models.RecipientPending),
+			"sent":          countStatus(models.RecipientSent),
+			"failed":        countStatus(models.RecipientFailed),
+			"bounced":       countStatus(models.RecipientBounced),
+			"suppressed":    countStatus(models.RecipientSuppressed),
+			"opened":        countEngaged("open_count"),
+			"clicked":       countEngaged("click_count"),
+			"open_tracked":  countTracked("track_opens"),
+			"click_tracked": countTracked("track_clicks"),
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "hour", Value: hour},
+			{Key: "tenant", Value: "$_id.tenant"},
+			{Key: "category", Value: "$_id.category"},
+			{Key: "server", Value: "$_id.server"},
+			{Key: "sender", Value: "$_id.sender"},
+			{Key: "domain", Value: "$_id.domain"},
+			{Key: "updated_at", Value: now},
+			// Rollups are keyed by their hour and dimensions, so a recompute replaces them in place
+			{Key: "_id", Value: bson.D{
+				{Key: "hour", Value: hour},
+				{Key: "tenant", Value: "$_id.tenant"},
+				{Key: "category", Value: "$_id.category"},
+				{Key: "server", Value: "$_id.server"},
+				{Key: "sender", Value: "$_id.sender"},
+				{Key: "domain", Value: "$_id.domain"},
+			}},
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           emailStatsCollectionName,
+			"on":             "_id",
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}}},
+	}
+
+	cursor, err := database.emailCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("Failed to roll up email stats", "error", err, "hour", hour)
+		return err
+	}
+	cursor.Close(ctx)
+
+	// Combinations that no longer occur, e.g. recipients that were pending before they got a
+	// server, weren't replaced by this run
+	_, err = database.emailStatsCollection.DeleteMany(ctx, bson.M{"hour": hour, "updated_at": bson.M{"$lt": now}})
+	if err != nil {
+		slog.Error("Failed to delete stale email stats", "error", err, "hour", hour)
+		return err
+	}
+
+	return nil
+}
+
+// LatestEmailStatsRollup returns when the rollups were last refreshed, or the zero time when there
+// are none
+func (database *Database) LatestEmailStatsRollup(ctx context.Context) (time.Time, error) {
+	var rollup models.EmailStatsRollup
+	err := database.emailStatsCollection.FindOne(
+		ctx,
+		bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "updated_at", Value: -1}}),
+	).Decode(&rollup)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return time.Time{}, nil
+		}
+
+		slog.Error("Failed to find email stats", "error", err)
+		return time.Time{}, err
+	}
+
+	return rollup.UpdatedAt, nil
+}
+
+// AggregateEmailStats sums the rollups of the hours in [since, until) matching the request's
+// filters, per group and in total. Groups are sorted by key.
+func (database *Database) AggregateEmailStats(ctx context.Context, request *models.StatsRequest) ([]*models.StatsGroup, *models.StatsGroup, error) {
+	match := bson.M{"tenant": request.Tenant, "hour": bson.M{"$gte": request.Since, "$lt": request.Until}}
+	for field, value := range map[string]string{
+		"category": request.Category,
+		"server":   request.Server,
+		"sender":   request.Sender,
+		"domain":   request.Domain,
+	} {
+		if value != "" {
+			match[field] = value
+		}
+	}
+
+	var key any
+	switch request.GroupBy {
+	case models.StatsByHour:
+		key = bson.M{"$dateToString": bson.M{"date": "$hour", "format": "%Y-%m-%dT%H:00:00Z"}}
+	case models.StatsByDay:
+		key = bson.M{"$dateToString": bson.M{"date": "$hour", "format": "%Y-%m-%dT00:00:00Z"}}
+	default:
+		key = "$" + string(request.GroupBy)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: bson.M{
+			"groups": bson.A{
+				bson.M{"$group": sumStatsCounts(key)},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+			"totals": bson.A{
+				bson.M{"$group": sumStatsCounts("")},
+			},
+		}}},
+	}
+
+	cursor, err := database.emailStatsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("Failed to aggregate email stats", "error", err)
+		return nil, nil, err
+	}
+
+	var results []struct {
+		Groups []*models.StatsGroup `bson:"groups"`
+		Totals []*models.StatsGroup `bson:"totals"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		slog.Error("Failed to decode email stats", "error", err)
+		return nil, nil, err
+	}
+
+	groups, totals := make([]*models.StatsGroup, 0), &models.StatsGroup{}
+	if len(results) > 0 {
+		if results[0].Groups != nil {
+			groups = results[0].Groups
+		}
+		if len(results[0].Totals) > 0 {
+			totals = results[0].Totals[0]
+		}
+	}
+
+	return groups, totals, nil
+}
+
+// sumStatsCounts is a $group stage summing every counter of the rollups per key
+func sumStatsCounts(key any) bson.M {
+	group := bson.M{"_id": key}
+	for _, field := range []string{"pending", "sent", "failed", "bounced", "suppressed", "opened", "clicked", "open_tracked", "click_tracked"} {
+		group[field] = bson.M{"$sum": "$" + field}
+	}
+
+	return group
+}
+
+func (database *Database) initEmailStatsCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, emailStatsCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"hour", "tenant", "category", "server", "sender", "domain", "updated_at"},
+			"properties": bson.M{
+				"hour": bson.M{
+					"bsonType":    "date",
+					"description": "must be the start of an hour and is required",
+				},
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"category": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"server": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"sender": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"domain": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"updated_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(emailStatsCollectionName)
+
+	// Rollups from before multi-tenancy keep their old key until their hour is rolled up again
+	database.backfillTenant(ctx, collection, bson.M{})
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Index for reading a time range and replacing an hour's rollups
+		{
+			Keys:    bson.D{{Key: "hour", Value: 1}, {Key: "updated_at", Value: 1}},
+			Options: options.Index().SetName("hour_updated_at"),
+		},
+		// Index for reading a tenant's time range
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "hour", Value: 1}},
+			Options: options.Index().SetName("tenant_hour"),
+		},
+		// Index for finding the latest refresh
+		{
+			Keys:    bson.D{{Key: "updated_at", Value: -1}},
+			Options: options.Index().SetName("updated_at_desc"),
+		},
+	})
+
+	return collection
+}
@@ -0,0 +1,334 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const inboxCollectionName = "inbox_items"
+
+func (database *Database) GetInboxItemByID(ctx context.Context, id string) (*models.InboxItem, error) {
+	itemID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		slog.Error("Failed to parse inbox item ID", "error", err)
+		return nil, err
+	}
+
+	var item models.InboxItem
+	if err = database.inboxCollection.FindOne(ctx, bson.M{"_id": itemID}).Decode(&item); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find inbox item", "error", err)
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (database *Database) CreateInboxItem(ctx context.Context, item *models.InboxItem) (*models.InboxItem, error) {
+	item.CreatedAt = time.Now()
+	item.Read = false
+	item.Archived = false
+
+	result, err := database.inboxCollection.InsertOne(ctx, item)
+	if err != nil {
+		slog.Error("Failed to insert inbox item", "error", err)
+		return nil, err
+	}
+
+	return database.GetInboxItemByID(ctx, result.InsertedID.(bson.ObjectID).Hex())
+}
+
+// WatchInboxItems streams the items created by every instance until ctx is done. It reads a change
+// stream, and polls created_at instead on a standalone mongod, where change streams aren't available.
+func (database *Database) WatchInboxItems(ctx context.Context) <-chan *models.InboxItem {
+	items := make(chan *models.InboxItem)
+
+	go func() {
+		defer close(items)
+
+		since := time.Now()
+		stream, err := database.inboxCollection.Watch(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"operationType": "insert"}}},
+		})
+		if err == nil {
+			err = streamInboxInserts(ctx, stream, items, &since)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Debug("Inbox change stream unavailable, polling for new items", "error", err)
+		database.pollInboxInserts(ctx, since, items)
+	}()
+
+	return items
+}
+
+// streamInboxInserts forwards inserted items until ctx is done or the stream fails, advancing since
+// to the latest item seen
+func streamInboxInserts(ctx context.Context, stream *mongo.ChangeStream, items chan<- *models.InboxItem, since *time.Time) error {
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var change struct {
+			FullDocument *models.InboxItem `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			slog.Error("Failed to decode inbox item insert", "error", err)
+			continue
+		}
+		if change.FullDocument == nil {
+			continue
+		}
+
+		if change.FullDocument.CreatedAt.After(*since) {
+			*since = change.FullDocument.CreatedAt
+		}
+
+		select {
+		case items <- change.FullDocument:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return stream.Err()
+}
+
+// pollInboxInserts queries for items created after since until ctx is done. Like email watches, the
+// window reaches back emailWatchClockSkew for instances whose clock runs behind.
+func (database *Database) pollInboxInserts(ctx context.Context, since time.Time, items chan<- *models.InboxItem) {
+	ticker := time.NewTicker(emailWatchPollInterval)
+	defer ticker.Stop()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	// Items delivered that are still inside the window
+	delivered := make(map[bson.ObjectID]time.Time)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cursor, err := database.inboxCollection.Find(ctx, bson.M{"created_at": bson.M{"$gt": since.Add(-emailWatchClockSkew)}}, opts)
+		if err != nil {
+			slog.Error("Failed to poll inbox items", "error", err)
+			continue
+		}
+
+		var created []*models.InboxItem
+		if err = cursor.All(ctx, &created); err != nil {
+			slog.Error("Failed to decode inbox items", "error", err)
+			continue
+		}
+
+		for _, item := range created {
+			if _, ok := delivered[item.ID]; ok {
+				continue
+			}
+			delivered[item.ID] = item.CreatedAt
+			if item.CreatedAt.After(since) {
+				since = item.CreatedAt
+			}
+
+			select {
+			case items <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for id, createdAt := range delivered {
+			if !createdAt.After(since.Add(-emailWatchClockSkew)) {
+				delete(delivered, id)
+			}
+		}
+	}
+}
+
+// ListInboxItems returns a user's items newest first
+func (database *Database) ListInboxItems(ctx context.Context, filter models.InboxFilter) ([]*models.InboxItem, error) {
+	query := bson.M{"user_id": filter.UserID, "archived": filter.Archived}
+	if filter.UnreadOnly {
+		query["read"] = false
+	}
+	if filter.Before != bson.NilObjectID {
+		query["_id"] = bson.M{"$lt": filter.Before}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(filter.Limit)
+
+	cursor, err := database.inboxCollection.Find(ctx, query, opts)
+	if err != nil {
+		slog.Error("Failed to list inbox items", "error", err)
+		return nil, err
+	}
+
+	items := make([]*models.InboxItem, 0)
+	if err = cursor.All(ctx, &items); err != nil {
+		slog.Error("Failed to decode inbox items", "error", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (database *Database) CountUnreadInboxItems(ctx context.Context, userID string) (int64, error) {
+	count, err := database.inboxCollection.CountDocuments(ctx, bson.M{"user_id": userID, "read": false, "archived": false})
+	if err != nil {
+		slog.Error("Failed to count unread inbox items", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// UpdateInboxItemRead marks a single item read or unread. Returns nil if the item doesn't belong to the user.
+func (database *Database) UpdateInboxItemRead(ctx context.Context, userID string, id bson.ObjectID, read bool) (*models.InboxItem, error) {
+	update := bson.M{"$set": bson.M{"read": true, "read_at": time.Now()}}
+	if !read {
+		update = bson.M{"$set": bson.M{"read": false}, "$unset": bson.M{"read_at": ""}}
+	}
+
+	return database.updateInboxItem(ctx, userID, id, update)
+}
+
+// UpdateInboxItemArchived archives an item, archived items are also considered read
+func (database *Database) UpdateInboxItemArchived(ctx context.Context, userID string, id bson.ObjectID) (*models.InboxItem, error) {
+	now := time.Now()
+	return database.updateInboxItem(ctx, userID, id, bson.M{"$set": bson.M{"archived": true, "archived_at": now, "read": true, "read_at": now}})
+}
+
+func (database *Database) MarkAllInboxItemsRead(ctx context.Context, userID string) (int64, error) {
+	result, err := database.inboxCollection.UpdateMany(
+		ctx,
+		bson.M{"user_id": userID, "read": false},
+		bson.M{"$set": bson.M{"read": true, "read_at": time.Now()}})
+	if err != nil {
+		slog.Error("Failed to mark inbox items read", "error", err)
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (database *Database) updateInboxItem(ctx context.Context, userID string, id bson.ObjectID, update bson.M) (*models.InboxItem, error) {
+	var item models.InboxItem
+	err := database.inboxCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "user_id": userID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&item)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to update inbox item", "error", err)
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (database *Database) initInboxCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, inboxCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"user_id", "title", "read", "archived", "created_at"},
+			"properties": bson.M{
+				"user_id": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"maxLength":   255,
+					"description": "must be a string between 1-255 characters and is required",
+				},
+				"title": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"maxLength":   255,
+					"description": "must be a string between 1-255 characters and is required",
+				},
+				"body": bson.M{
+					"bsonType":    "string",
+					"maxLength":   10000,
+					"description": "must be a string up to 10000 characters",
+				},
+				"link": bson.M{
+					"bsonType":    "string",
+					"maxLength":   2048,
+					"description": "must be a string up to 2048 characters",
+				},
+				"data": bson.M{
+					"bsonType":    "object",
+					"description": "must be an object of string values",
+				},
+				"read": bson.M{
+					"bsonType":    "bool",
+					"description": "must be a boolean and is required",
+				},
+				"archived": bson.M{
+					"bsonType":    "bool",
+					"description": "must be a boolean and is required",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+				"read_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date",
+				},
+				"archived_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(inboxCollectionName)
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Index for listing a user's inbox newest first
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "archived", Value: 1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("user_archived_id"),
+		},
+		// Index for counting unread items
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "read", Value: 1},
+			},
+			Options: options.Index().SetName("user_read"),
+		},
+		// TTL Index for automatic cleanup of old items (90 days, same as emails)
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().
+				SetName("inbox_ttl").
+				SetExpireAfterSeconds(90 * 24 * 60 * 60), // 90 days
+		},
+	})
+
+	return collection
+}
@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const preferenceCollectionName = "preferences"
+
+func (database *Database) GetPreference(ctx context.Context, tenant, address string) (*models.Preference, error) {
+	var preference models.Preference
+	if err := database.preferenceCollection.FindOne(ctx, bson.M{"tenant": tenant, "address": address}).Decode(&preference); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find preference", "error", err)
+		return nil, err
+	}
+
+	return &preference, nil
+}
+
+// GetPreferences returns the tenant's stored preferences of the given addresses, keyed by address
+func (database *Database) GetPreferences(ctx context.Context, tenant string, addresses []string) (map[string]*models.Preference, error) {
+	cursor, err := database.preferenceCollection.Find(ctx, bson.M{"tenant": tenant, "address": bson.M{"$in": addresses}})
+	if err != nil {
+		slog.Error("Failed to find preferences", "error", err)
+		return nil, err
+	}
+
+	var preferences []*models.Preference
+	if err = cursor.All(ctx, &preferences); err != nil {
+		slog.Error("Failed to decode preferences", "error", err)
+		return nil, err
+	}
+
+	result := make(map[string]*models.Preference, len(preferences))
+	for _, preference := range preferences {
+		result[preference.Address] = preference
+	}
+
+	return result, nil
+}
+
+// UpsertPreference replaces the rules stored for an address of the tenant
+func (database *Database) UpsertPreference(ctx context.Context, tenant, address string, rules []models.PreferenceRule) (*models.Preference, error) {
+	var preference models.Preference
+	err := database.preferenceCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"tenant": tenant, "address": address},
+		bson.M{"$set": bson.M{"rules": rules, "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&preference)
+	if err != nil {
+		slog.Error("Failed to upsert preference", "error", err)
+		return nil, err
+	}
+
+	return &preference, nil
+}
+
+// SetPreferenceRule adds or replaces the rule for one category and channel, keeping the address's other rules
+func (database *Database) SetPreferenceRule(ctx context.Context, tenant, address string, rule models.PreferenceRule) (*models.Preference, error) {
+	// Remove the existing rule for the same category and channel, then append the new one
+	_, err := database.preferenceCollection.UpdateOne(
+		ctx,
+		bson.M{"tenant": tenant, "address": address},
+		bson.M{"$pull": bson.M{"rules": bson.M{"category": rule.Category, "channel": rule.Channel}}})
+	if err != nil {
+		slog.Error("Failed to update preference", "error", err)
+		return nil, err
+	}
+
+	var preference models.Preference
+	err = database.preferenceCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"tenant": tenant, "address": address},
+		bson.M{
+			"$push": bson.M{"rules": rule},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&preference)
+	if err != nil {
+		slog.Error("Failed to update preference", "error", err)
+		return nil, err
+	}
+
+	return &preference, nil
+}
+
+func (database *Database) DeletePreference(ctx context.Context, tenant, address string) (bool, error) {
+	result, err := database.preferenceCollection.DeleteOne(ctx, bson.M{"tenant": tenant, "address": address})
+	if err != nil {
+		slog.Error("Failed to delete preference", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// removeUnsupportedChannels drops the telegram and inbox rules stored before they were rejected.
+// They never applied, and documents holding them would fail the schema on their next update.
+func (database *Database) removeUnsupportedChannels(ctx context.Context, collection *mongo.Collection) {
+	unsupported := bson.M{"$in": []string{"telegram", "inbox"}}
+
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"rules.channel": unsupported},
+		bson.M{"$pull": bson.M{"rules": bson.M{"channel": unsupported}}})
+	if err != nil {
+		slog.Error("Failed to remove preference rules of unsupported channels", "error", err)
+		return
+	}
+
+	if result.ModifiedCount > 0 {
+		slog.Info("Removed preference rules of unsupported channels", "count", result.ModifiedCount)
+	}
+}
+
+func (database *Database) initPreferenceCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, preferenceCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"address", "rules", "updated_at"},
+			"properties": bson.M{
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be the ID of the tenant the preference belongs to",
+				},
+				"address": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"maxLength":   320,
+					"description": "must be a lower-cased recipient address and is required",
+				},
+				"rules": bson.M{
+					"bsonType": "array",
+					"maxItems": 100,
+					"items": bson.M{
+						"bsonType": "object",
+						"required": []string{"category", "channel", "opted_in"},
+						"properties": bson.M{
+							"category": bson.M{
+								"bsonType":  "string",
+								"minLength": 1,
+								"maxLength": 64,
+							},
+							"channel": bson.M{
+								"bsonType": "string",
+								"enum":     []string{"*", "email"},
+							},
+							"opted_in": bson.M{
+								"bsonType": "bool",
+							},
+						},
+					},
+					"description": "must be an array of preference rules and is required",
+				},
+				"updated_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(preferenceCollectionName)
+
+	database.removeUnsupportedChannels(ctx, collection)
+	database.backfillTenant(ctx, collection, bson.M{})
+
+	// Replaced by the tenant-scoped index below, addresses are unique per tenant
+	database.dropIndexes(ctx, collection, "address_unique")
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Unique index on the recipient address of each tenant
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "address", Value: 1}},
+			Options: options.Index().SetName("tenant_address_unique").SetUnique(true),
+		},
+	})
+
+	return collection
+}
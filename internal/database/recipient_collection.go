@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const recipientCollectionName = "recipients"
+
+// ErrDuplicateRecipient is returned when creating a recipient whose user ID is already taken
+var ErrDuplicateRecipient = errors.New("recipient already exists")
+
+func (database *Database) GetRecipientByUserID(ctx context.Context, tenant, userID string) (*models.Recipient, error) {
+	var recipient models.Recipient
+	if err := database.recipientCollection.FindOne(ctx, bson.M{"tenant": tenant, "user_id": userID}).Decode(&recipient); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find recipient", "error", err)
+		return nil, err
+	}
+
+	return &recipient, nil
+}
+
+// ExistingRecipientUserIDs returns which of userIDs have a recipient profile in the tenant
+func (database *Database) ExistingRecipientUserIDs(ctx context.Context, tenant string, userIDs []string) (map[string]bool, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "user_id": 1})
+
+	cursor, err := database.recipientCollection.Find(ctx, bson.M{"tenant": tenant, "user_id": bson.M{"$in": userIDs}}, opts)
+	if err != nil {
+		slog.Error("Failed to find recipients", "error", err)
+		return nil, err
+	}
+
+	var recipients []struct {
+		UserID string `bson:"user_id"`
+	}
+	if err = cursor.All(ctx, &recipients); err != nil {
+		slog.Error("Failed to decode recipients", "error", err)
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(recipients))
+	for _, recipient := range recipients {
+		existing[recipient.UserID] = true
+	}
+
+	return existing, nil
+}
+
+func (database *Database) ListRecipients(ctx context.Context, tenant, after string, limit int64) ([]*models.Recipient, error) {
+	filter := bson.M{"tenant": tenant}
+	if after != "" {
+		filter["user_id"] = bson.M{"$gt": after}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "user_id", Value: 1}}).SetLimit(limit)
+
+	cursor, err := database.recipientCollection.Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("Failed to list recipients", "error", err)
+		return nil, err
+	}
+
+	recipients := make([]*models.Recipient, 0)
+	if err = cursor.All(ctx, &recipients); err != nil {
+		slog.Error("Failed to decode recipients", "error", err)
+		return nil, err
+	}
+
+	return recipients, nil
+}
+
+func (database *Database) CreateRecipient(ctx context.Context, recipient *models.Recipient) (*models.Recipient, error) {
+	recipient.CreatedAt = time.Now()
+	recipient.UpdatedAt = recipient.CreatedAt
+
+	if _, err := database.recipientCollection.InsertOne(ctx, recipient); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrDuplicateRecipient
+		}
+
+		slog.Error("Failed to insert recipient", "error", err)
+		return nil, err
+	}
+
+	return database.GetRecipientByUserID(ctx, recipient.Tenant, recipient.UserID)
+}
+
+// UpdateRecipient replaces the profile fields of an existing recipient of recipient.Tenant. Returns nil
+// if it doesn't exist.
+func (database *Database) UpdateRecipient(ctx context.Context, recipient *models.Recipient) (*models.Recipient, error) {
+	var updated models.Recipient
+	err := database.recipientCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"tenant": recipient.Tenant, "user_id": recipient.UserID},
+		bson.M{"$set": bson.M{
+			"emails":       recipient.Emails,
+			"display_name": recipient.DisplayName,
+			"locale":       recipient.Locale,
+			"timezone":     recipient.Timezone,
+			"updated_at":   time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to update recipient", "error", err)
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (database *Database) DeleteRecipient(ctx context.Context, tenant, userID string) (bool, error) {
+	result, err := database.recipientCollection.DeleteOne(ctx, bson.M{"tenant": tenant, "user_id": userID})
+	if err != nil {
+		slog.Error("Failed to delete recipient", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+func (database *Database) initRecipientCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, recipientCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"user_id", "emails", "created_at", "updated_at"},
+			"properties": bson.M{
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be the ID of the tenant the recipient belongs to",
+				},
+				"user_id": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"maxLength":   255,
+					"description": "must be a string between 1-255 characters and is required",
+				},
+				"emails": bson.M{
+					"bsonType": "array",
+					"minItems": 1,
+					"maxItems": 10,
+					"items": bson.M{
+						"bsonType": "string",
+						"pattern":  "^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}$",
+					},
+					"description": "must be an array of valid email addresses and is required",
+				},
+				"display_name": bson.M{
+					"bsonType":    "string",
+					"maxLength":   255,
+					"description": "must be a string up to 255 characters",
+				},
+				"locale": bson.M{
+					"bsonType":    "string",
+					"maxLength":   35,
+					"description": "must be a BCP 47 language tag",
+				},
+				"timezone": bson.M{
+					"bsonType":    "string",
+					"maxLength":   64,
+					"description": "must be an IANA timezone name",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+				"updated_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(recipientCollectionName)
+
+	database.backfillTenant(ctx, collection, bson.M{})
+
+	// Replaced by the tenant-scoped indexes below, user IDs are unique per tenant
+	database.dropIndexes(ctx, collection, "user_id_unique", "emails_asc")
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Unique index on the external user ID of each tenant
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("tenant_user_id_unique").SetUnique(true),
+		},
+		// Index on emails for finding a tenant's recipient by address
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "emails", Value: 1}},
+			Options: options.Index().SetName("tenant_emails_asc"),
+		},
+	})
+
+	return collection
+}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const suppressionCollectionName = "suppressions"
+
+// activeSuppression excludes expired entries the TTL monitor hasn't removed yet
+func activeSuppression(now time.Time) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"expires_at": bson.M{"$exists": false}},
+		{"expires_at": bson.M{"$gt": now}},
+	}}
+}
+
+// GetActiveSuppressions returns the tenant's unexpired suppressions of the given addresses, keyed by address
+func (database *Database) GetActiveSuppressions(ctx context.Context, tenant string, addresses []string) (map[string]*models.Suppression, error) {
+	filter := activeSuppression(time.Now())
+	filter["tenant"] = tenant
+	filter["address"] = bson.M{"$in": addresses}
+
+	cursor, err := database.suppressionCollection.Find(ctx, filter)
+	if err != nil {
+		slog.Error("Failed to find suppressions", "error", err)
+		return nil, err
+	}
+
+	var suppressions []*models.Suppression
+	if err = cursor.All(ctx, &suppressions); err != nil {
+		slog.Error("Failed to decode suppressions", "error", err)
+		return nil, err
+	}
+
+	result := make(map[string]*models.Suppression, len(suppressions))
+	for _, suppression := range suppressions {
+		result[suppression.Address] = suppression
+	}
+
+	return result, nil
+}
+
+func (database *Database) ListSuppressions(ctx context.Context, tenant string, reason models.SuppressionReason, after string, limit int64) ([]*models.Suppression, error) {
+	filter := activeSuppression(time.Now())
+	filter["tenant"] = tenant
+	if reason != "" {
+		filter["reason"] = reason
+	}
+	if after != "" {
+		filter["address"] = bson.M{"$gt": after}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "address", Value: 1}}).SetLimit(limit)
+
+	cursor, err := database.suppressionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("Failed to list suppressions", "error", err)
+		return nil, err
+	}
+
+	suppressions := make([]*models.Suppression, 0)
+	if err = cursor.All(ctx, &suppressions); err != nil {
+		slog.Error("Failed to decode suppressions", "error", err)
+		return nil, err
+	}
+
+	return suppressions, nil
+}
+
+// UpsertSuppressions adds or replaces suppressions by tenant and address
+func (database *Database) UpsertSuppressions(ctx context.Context, suppressions []*models.Suppression) error {
+	if len(suppressions) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(suppressions))
+	for i, suppression := range suppressions {
+		if suppression.CreatedAt.IsZero() {
+			suppression.CreatedAt = time.Now()
+		}
+
+		writes[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"tenant": suppression.Tenant, "address": suppression.Address}).
+			SetReplacement(suppression).
+			SetUpsert(true)
+	}
+
+	if _, err := database.suppressionCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		slog.Error("Failed to upsert suppressions", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (database *Database) GetSuppression(ctx context.Context, tenant, address string) (*models.Suppression, error) {
+	filter := activeSuppression(time.Now())
+	filter["tenant"] = tenant
+	filter["address"] = address
+
+	var suppression models.Suppression
+	if err := database.suppressionCollection.FindOne(ctx, filter).Decode(&suppression); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find suppression", "error", err)
+		return nil, err
+	}
+
+	return &suppression, nil
+}
+
+func (database *Database) DeleteSuppression(ctx context.Context, tenant, address string) (bool, error) {
+	result, err := database.suppressionCollection.DeleteOne(ctx, bson.M{"tenant": tenant, "address": address})
+	if err != nil {
+		slog.Error("Failed to delete suppression", "error", err)
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+func (database *Database) initSuppressionCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, suppressionCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"address", "reason", "source", "created_at"},
+			"properties": bson.M{
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be the ID of the tenant the suppression belongs to",
+				},
+				"address": bson.M{
+					"bsonType":    "string",
+					"pattern":     "^[a-z0-9._%+-]+@[a-z0-9.-]+\\.[a-z]{2,}$",
+					"description": "must be a lower-cased email address and is required",
+				},
+				"reason": bson.M{
+					"bsonType":    "string",
+					"enum":        []string{"hard_bounce", "complaint", "unsubscribe", "manual"},
+					"description": "must be one of: hard_bounce, complaint, unsubscribe, manual",
+				},
+				"source": bson.M{
+					"bsonType":    "string",
+					"enum":        []string{"api", "import", "smtp", "bounce", "fbl"},
+					"description": "must be one of: api, import, smtp, bounce, fbl",
+				},
+				"detail": bson.M{
+					"bsonType":    "string",
+					"maxLength":   1000,
+					"description": "must be a string up to 1000 characters",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+				"expires_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(suppressionCollectionName)
+
+	database.backfillTenant(ctx, collection, bson.M{})
+
+	// Replaced by the tenant-scoped indexes below, addresses are unique per tenant
+	database.dropIndexes(ctx, collection, "address_unique", "reason_address")
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Unique index on the suppressed address of each tenant
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "address", Value: 1}},
+			Options: options.Index().SetName("tenant_address_unique").SetUnique(true),
+		},
+		// Index on reason for filtering the admin list
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "reason", Value: 1}, {Key: "address", Value: 1}},
+			Options: options.Index().SetName("tenant_reason_address"),
+		},
+		// TTL Index removing suppressions once they expire
+		{
+			Keys: bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().
+				SetName("suppression_ttl").
+				SetExpireAfterSeconds(0),
+		},
+	})
+
+	return collection
+}
@@ -0,0 +1,275 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const telegramCollectionName = "telegram_messages"
+
+func (database *Database) GetTelegramMessageByID(ctx context.Context, id string) (*models.TelegramMessage, error) {
+	messageID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		slog.Error("Failed to parse telegram message ID", "error", err)
+		return nil, err
+	}
+
+	var message models.TelegramMessage
+	if err = database.telegramCollection.FindOne(ctx, bson.M{"_id": messageID}).Decode(&message); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find telegram message", "error", err)
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (database *Database) CreateTelegramMessage(ctx context.Context, message *models.TelegramMessage) (*models.TelegramMessage, error) {
+	message.CreatedAt = time.Now()
+	message.Status = models.StatusPending
+
+	message.Chats = make([]models.TelegramChat, len(message.ChatIDs))
+	for i, chatID := range message.ChatIDs {
+		message.Chats[i] = models.TelegramChat{ChatID: chatID, Status: models.StatusPending}
+	}
+
+	result, err := database.telegramCollection.InsertOne(ctx, message)
+	if err != nil {
+		slog.Error("Failed to insert telegram message", "error", err)
+		return nil, err
+	}
+
+	return database.GetTelegramMessageByID(ctx, result.InsertedID.(bson.ObjectID).Hex())
+}
+
+// ClaimTelegramMessageRetry sets a failed or partially sent message back to pending and returns it.
+// Returns nil if it doesn't exist or isn't failed, so concurrent retries send it once.
+func (database *Database) ClaimTelegramMessageRetry(ctx context.Context, id bson.ObjectID) (*models.TelegramMessage, error) {
+	var message models.TelegramMessage
+	err := database.telegramCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "status": bson.M{"$in": []models.EmailStatus{models.StatusFailed, models.StatusPartial}}},
+		bson.M{
+			"$set":   bson.M{"status": models.StatusPending},
+			"$unset": bson.M{"error_message": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&message)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to claim telegram message", "error", err)
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// UpdateTelegramChat records the delivery state of a message in one of its chats
+func (database *Database) UpdateTelegramChat(ctx context.Context, id bson.ObjectID, chat *models.TelegramChat) error {
+	set := bson.M{"chats.$[chat].status": chat.Status}
+	update := bson.M{"$set": set}
+	if chat.Status == models.StatusSent {
+		set["chats.$[chat].sent_at"] = chat.SentAt
+		update["$unset"] = bson.M{"chats.$[chat].error_message": ""}
+	} else {
+		set["chats.$[chat].error_message"] = chat.ErrorMsg
+	}
+
+	_, err := database.telegramCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		update,
+		options.UpdateOne().SetArrayFilters([]any{bson.M{"chat.chat_id": chat.ChatID}}))
+	if err != nil {
+		slog.Error("Failed to update telegram chat", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateTelegramMessageFail marks a message failed, or partial when message.Status says some chats got it
+func (database *Database) UpdateTelegramMessageFail(ctx context.Context, message *models.TelegramMessage) (*models.TelegramMessage, error) {
+	if message.ID == bson.NilObjectID {
+		return nil, fmt.Errorf("ID is required for updating a telegram message")
+	}
+
+	status := models.StatusFailed
+	if message.Status == models.StatusPartial {
+		status = models.StatusPartial
+	}
+
+	_, err := database.telegramCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": message.ID},
+		bson.M{"$set": bson.M{"status": status, "error_message": message.ErrorMsg}})
+	if err != nil {
+		slog.Error("Failed to update telegram message", "error", err)
+		return nil, err
+	}
+
+	return database.GetTelegramMessageByID(ctx, message.ID.Hex())
+}
+
+func (database *Database) UpdateTelegramMessageSent(ctx context.Context, message *models.TelegramMessage) (*models.TelegramMessage, error) {
+	if message.ID == bson.NilObjectID {
+		return nil, fmt.Errorf("ID is required for updating a telegram message")
+	}
+
+	_, err := database.telegramCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": message.ID},
+		bson.M{"$set": bson.M{"status": models.StatusSent, "sent_at": message.SentAt}})
+	if err != nil {
+		slog.Error("Failed to update telegram message", "error", err)
+		return nil, err
+	}
+
+	return database.GetTelegramMessageByID(ctx, message.ID.Hex())
+}
+
+func (database *Database) initTelegramCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, telegramCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"bot", "chat_ids", "status", "created_at"},
+			"properties": bson.M{
+				"bot": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"description": "must be the name of a configured bot and is required",
+				},
+				"chat_ids": bson.M{
+					"bsonType": "array",
+					"minItems": 1,
+					"maxItems": 100,
+					"items": bson.M{
+						"bsonType":  "string",
+						"minLength": 1,
+					},
+					"description": "must be an array of chat IDs or @channel usernames and is required",
+				},
+				"text": bson.M{
+					"bsonType":    "string",
+					"maxLength":   1048576, // 1MB limit, split into 4096 character messages on send
+					"description": "must be a string up to 1MB",
+				},
+				"parse_mode": bson.M{
+					"bsonType":    "string",
+					"enum":        []string{"Markdown", "MarkdownV2", "HTML"},
+					"description": "must be one of: Markdown, MarkdownV2, HTML",
+				},
+				"document": bson.M{
+					"bsonType": "object",
+					"required": []string{"filename", "content", "content_type"},
+					"properties": bson.M{
+						"filename": bson.M{
+							"bsonType":    "string",
+							"minLength":   1,
+							"maxLength":   255,
+							"description": "must be a string between 1-255 characters",
+						},
+						"content": bson.M{
+							"bsonType":    "binData",
+							"description": "must be binary data",
+						},
+						"content_type": bson.M{
+							"bsonType":    "string",
+							"minLength":   1,
+							"maxLength":   100,
+							"description": "must be a string between 1-100 characters",
+						},
+					},
+					"description": "must be a document attachment object",
+				},
+				"status": bson.M{
+					"bsonType":    "string",
+					"enum":        []string{"pending", "sent", "failed", "partial"},
+					"description": "must be one of: pending, sent, failed, partial",
+				},
+				"error_message": bson.M{
+					"bsonType":    "string",
+					"maxLength":   1000,
+					"description": "must be a string up to 1000 characters",
+				},
+				"chats": bson.M{
+					"bsonType": "array",
+					"maxItems": 100,
+					"items": bson.M{
+						"bsonType": "object",
+						"required": []string{"chat_id", "status"},
+						"properties": bson.M{
+							"chat_id": bson.M{
+								"bsonType":  "string",
+								"minLength": 1,
+							},
+							"status": bson.M{
+								"bsonType": "string",
+								"enum":     []string{"pending", "sent", "failed"},
+							},
+							"error_message": bson.M{
+								"bsonType":  "string",
+								"maxLength": 1000,
+							},
+							"sent_at": bson.M{
+								"bsonType": "date",
+							},
+						},
+					},
+					"description": "must be an array of the delivery state of each chat",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+				"sent_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(telegramCollectionName)
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Index on created_at for chronological queries
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("created_at_desc"),
+		},
+		// Index on status for filtering by message status
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetName("status_asc"),
+		},
+		// Index on chat_ids for finding messages by chat
+		{
+			Keys:    bson.D{{Key: "chat_ids", Value: 1}},
+			Options: options.Index().SetName("chat_ids_asc"),
+		},
+		// TTL Index for automatic cleanup of old messages (90 days, same as emails)
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().
+				SetName("telegram_ttl").
+				SetExpireAfterSeconds(90 * 24 * 60 * 60), // 90 days
+		},
+	})
+
+	return collection
+}
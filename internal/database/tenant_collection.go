@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const tenantCollectionName = "tenants"
+
+// ErrDuplicateTenant is returned when creating a tenant whose ID is already taken
+var ErrDuplicateTenant = errors.New("tenant already exists")
+
+func (database *Database) CreateTenant(ctx context.Context, tenant *models.Tenant) (*models.Tenant, error) {
+	tenant.CreatedAt = time.Now()
+	tenant.UpdatedAt = tenant.CreatedAt
+
+	if _, err := database.tenantCollection.InsertOne(ctx, tenant); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrDuplicateTenant
+		}
+
+		slog.Error("Failed to insert tenant", "error", err)
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+func (database *Database) ListTenants(ctx context.Context) ([]*models.Tenant, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := database.tenantCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		slog.Error("Failed to list tenants", "error", err)
+		return nil, err
+	}
+
+	tenants := make([]*models.Tenant, 0)
+	if err = cursor.All(ctx, &tenants); err != nil {
+		slog.Error("Failed to decode tenants", "error", err)
+		return nil, err
+	}
+
+	return tenants, nil
+}
+
+// GetTenant returns the tenant including its SMTP passwords, or nil when it doesn't exist
+func (database *Database) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := database.tenantCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&tenant); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find tenant", "error", err)
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+// UpdateTenant replaces the tenant's settings. Returns nil if it doesn't exist.
+func (database *Database) UpdateTenant(ctx context.Context, tenant *models.Tenant) (*models.Tenant, error) {
+	set := bson.M{
+		"name":         tenant.Name,
+		"smtp_servers": tenant.SMTPServers,
+		"senders":      tenant.Senders,
+		"disabled":     tenant.Disabled,
+		"updated_at":   time.Now(),
+	}
+	update := bson.M{"$set": set}
+	if tenant.Limits != nil {
+		set["limits"] = tenant.Limits
+	} else {
+		update["$unset"] = bson.M{"limits": ""}
+	}
+
+	var updated models.Tenant
+	err := database.tenantCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": tenant.ID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to update tenant", "error", err)
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// backfillTenant assigns the documents stored before multi-tenancy to the default tenant
+func (database *Database) backfillTenant(ctx context.Context, collection *mongo.Collection, filter bson.M) {
+	filter["tenant"] = bson.M{"$exists": false}
+
+	result, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"tenant": models.DefaultTenant}})
+	if err != nil {
+		slog.Error("Failed to assign documents to the default tenant", "collection", collection.Name(), "error", err)
+		return
+	}
+
+	if result.ModifiedCount > 0 {
+		slog.Info("Assigned documents to the default tenant", "collection", collection.Name(), "count", result.ModifiedCount)
+	}
+}
+
+func (database *Database) initTenantCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, tenantCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"_id", "name", "smtp_servers", "disabled", "created_at", "updated_at"},
+			"properties": bson.M{
+				"_id": bson.M{
+					"bsonType":    "string",
+					"pattern":     "^[a-z0-9][a-z0-9_-]{0,62}$",
+					"description": "must be a lower-case tenant ID up to 63 characters and is required",
+				},
+				"name": bson.M{
+					"bsonType":    "string",
+					"minLength":   1,
+					"maxLength":   255,
+					"description": "must be a string up to 255 characters and is required",
+				},
+				"smtp_servers": bson.M{
+					"bsonType": "array",
+					"minItems": 1,
+					"maxItems": 20,
+					"items": bson.M{
+						"bsonType": "object",
+						"required": []string{"host", "port", "from_email"},
+						"properties": bson.M{
+							"host": bson.M{
+								"bsonType": "string",
+							},
+							"port": bson.M{
+								"bsonType": []string{"int", "long"},
+								"minimum":  1,
+								"maximum":  65535,
+							},
+							"from_email": bson.M{
+								"bsonType": "string",
+							},
+						},
+					},
+					"description": "must be a non-empty array of SMTP servers and is required",
+				},
+				"senders": bson.M{
+					"bsonType": "array",
+					"maxItems": 100,
+					"items": bson.M{
+						"bsonType": "string",
+					},
+					"description": "must be an array of sender addresses",
+				},
+				"disabled": bson.M{
+					"bsonType":    "bool",
+					"description": "must be a boolean and is required",
+				},
+				"limits": bson.M{
+					"bsonType": "object",
+					"properties": bson.M{
+						"rate": bson.M{
+							"bsonType": []string{"double", "int", "long"},
+							"minimum":  0,
+						},
+						"burst": bson.M{
+							"bsonType": []string{"int", "long"},
+							"minimum":  0,
+						},
+						"monthly_quota": bson.M{
+							"bsonType": []string{"int", "long"},
+							"minimum":  0,
+						},
+					},
+					"description": "must be an object of limit overrides",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+				"updated_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	return database.db.Collection(tenantCollectionName)
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const usageCollectionName = "usage"
+
+// ReserveUsage adds emails and recipients to the tenant's usage of month unless that takes the emails
+// over quota, 0 being unlimited. Reports whether they were added.
+func (database *Database) ReserveUsage(ctx context.Context, tenant, month string, emails, recipients, quota int64) (bool, error) {
+	if quota > 0 && emails > quota {
+		return false, nil
+	}
+
+	filter := bson.M{"tenant": tenant, "month": month}
+	if quota > 0 {
+		filter["emails"] = bson.M{"$lte": quota - emails}
+	}
+	update := bson.M{
+		"$inc": bson.M{"emails": emails, "recipients": recipients},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	// A month's first reservation creates the counter. When the counter exists but is over the
+	// limit, the upsert collides with it instead.
+	result, err := database.usageCollection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err == nil {
+		return true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		slog.Error("Failed to reserve usage", "tenant", tenant, "error", err)
+		return false, err
+	}
+
+	// Two first reservations can also race, one of them collides though it fits
+	result, err = database.usageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		slog.Error("Failed to reserve usage", "tenant", tenant, "error", err)
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// ReleaseUsage takes back a reservation whose emails weren't accepted
+func (database *Database) ReleaseUsage(ctx context.Context, tenant, month string, emails, recipients int64) error {
+	_, err := database.usageCollection.UpdateOne(
+		ctx,
+		bson.M{"tenant": tenant, "month": month},
+		bson.M{
+			"$inc": bson.M{"emails": -emails, "recipients": -recipients},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		slog.Error("Failed to release usage", "tenant", tenant, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetUsage returns the tenant's usage of month, or nil when it hasn't sent anything
+func (database *Database) GetUsage(ctx context.Context, tenant, month string) (*models.Usage, error) {
+	var usage models.Usage
+	if err := database.usageCollection.FindOne(ctx, bson.M{"tenant": tenant, "month": month}).Decode(&usage); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find usage", "error", err)
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+func (database *Database) initUsageCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, usageCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"tenant", "month", "emails", "recipients", "updated_at"},
+			"properties": bson.M{
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"month": bson.M{
+					"bsonType":    "string",
+					"pattern":     "^[0-9]{4}-[0-9]{2}$",
+					"description": "must be a YYYY-MM month and is required",
+				},
+				"emails": bson.M{
+					"bsonType":    []string{"int", "long"},
+					"description": "must be an integer and is required",
+				},
+				"recipients": bson.M{
+					"bsonType":    []string{"int", "long"},
+					"description": "must be an integer and is required",
+				},
+				"updated_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(usageCollectionName)
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Unique index so each tenant has one counter per month
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "month", Value: 1}},
+			Options: options.Index().SetName("tenant_month_unique").SetUnique(true),
+		},
+	})
+
+	return collection
+}
@@ -0,0 +1,235 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	webhookDeliveryCollectionName = "webhook_deliveries"
+	// Only the latest attempts are kept in a delivery's log
+	maxWebhookAttemptLog = 20
+)
+
+// CreateWebhookDeliveries queues deliveries to be sent by the webhook dispatcher
+func (database *Database) CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	documents := make([]any, len(deliveries))
+	for i, delivery := range deliveries {
+		delivery.CreatedAt = now
+		if delivery.NextAttemptAt.IsZero() {
+			delivery.NextAttemptAt = now
+		}
+		documents[i] = delivery
+	}
+
+	result, err := database.webhookDeliveryCollection.InsertMany(ctx, documents)
+	if err != nil {
+		slog.Error("Failed to insert webhook deliveries", "error", err)
+		return err
+	}
+
+	for i, id := range result.InsertedIDs {
+		deliveries[i].ID = id.(bson.ObjectID)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDelivery locks the next pending delivery that is due, or returns nil when there is none
+func (database *Database) ClaimWebhookDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          models.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+		"locked_until":    bson.M{"$not": bson.M{"$gt": now}},
+	}
+
+	var delivery models.WebhookDelivery
+	err := database.webhookDeliveryCollection.FindOneAndUpdate(
+		ctx,
+		filter,
+		bson.M{"$set": bson.M{"locked_until": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to claim webhook delivery", "error", err)
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// RecordWebhookAttempt logs an attempt and stores the delivery's new state, releasing its lock
+func (database *Database) RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt models.WebhookAttempt) error {
+	set := bson.M{
+		"status":        delivery.Status,
+		"attempt_count": delivery.AttemptCount,
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		set["next_attempt_at"] = delivery.NextAttemptAt
+	} else {
+		set["completed_at"] = delivery.CompletedAt
+	}
+
+	_, err := database.webhookDeliveryCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": delivery.ID},
+		bson.M{
+			"$set":   set,
+			"$unset": bson.M{"locked_until": ""},
+			"$push": bson.M{"attempts": bson.M{
+				"$each":  []models.WebhookAttempt{attempt},
+				"$slice": -maxWebhookAttemptLog,
+			}},
+		})
+	if err != nil {
+		slog.Error("Failed to update webhook delivery", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// CountOverdueWebhookDeliveries counts the pending deliveries that were due before the given time
+func (database *Database) CountOverdueWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	count, err := database.webhookDeliveryCollection.CountDocuments(ctx, bson.M{
+		"status":          models.WebhookDeliveryPending,
+		"next_attempt_at": bson.M{"$lt": before},
+	})
+	if err != nil {
+		slog.Error("Failed to count webhook deliveries", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ListWebhookDeliveries returns an endpoint's deliveries newest first
+func (database *Database) ListWebhookDeliveries(ctx context.Context, endpointID bson.ObjectID, status models.WebhookDeliveryStatus, before bson.ObjectID, limit int64) ([]*models.WebhookDelivery, error) {
+	filter := bson.M{"endpoint_id": endpointID}
+	if status != "" {
+		filter["status"] = status
+	}
+	if !before.IsZero() {
+		filter["_id"] = bson.M{"$lt": before}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+
+	cursor, err := database.webhookDeliveryCollection.Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("Failed to list webhook deliveries", "error", err)
+		return nil, err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		slog.Error("Failed to decode webhook deliveries", "error", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (database *Database) GetWebhookDelivery(ctx context.Context, endpointID, id bson.ObjectID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := database.webhookDeliveryCollection.FindOne(ctx, bson.M{"_id": id, "endpoint_id": endpointID}).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find webhook delivery", "error", err)
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (database *Database) initWebhookDeliveryCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, webhookDeliveryCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"endpoint_id", "event_id", "event_type", "payload", "status", "attempt_count", "created_at"},
+			"properties": bson.M{
+				"endpoint_id": bson.M{
+					"bsonType":    "objectId",
+					"description": "must be a webhook endpoint ID and is required",
+				},
+				"event_id": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"event_type": bson.M{
+					"bsonType":    "string",
+					"enum":        []string{"sent", "failed", "bounced", "complained", "opened", "clicked", "unsubscribed"},
+					"description": "must be a known event type and is required",
+				},
+				"payload": bson.M{
+					"bsonType":    "string",
+					"description": "must be the JSON body and is required",
+				},
+				"status": bson.M{
+					"bsonType":    "string",
+					"enum":        []string{"pending", "succeeded", "failed"},
+					"description": "must be one of: pending, succeeded, failed",
+				},
+				"attempt_count": bson.M{
+					"bsonType":    []string{"int", "long"},
+					"minimum":     0,
+					"description": "must be a non-negative integer and is required",
+				},
+				"attempts": bson.M{
+					"bsonType":    "array",
+					"maxItems":    maxWebhookAttemptLog,
+					"description": "must be an array of attempts",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(webhookDeliveryCollectionName)
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Index for the dispatcher picking up due deliveries
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("status_next_attempt_at"),
+		},
+		// Index for an endpoint's delivery log
+		{
+			Keys:    bson.D{{Key: "endpoint_id", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("endpoint_id_id"),
+		},
+		// TTL Index, the delivery log is kept for 30 days
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().
+				SetName("webhook_deliveries_ttl").
+				SetExpireAfterSeconds(30 * 24 * 60 * 60), // 30 days
+		},
+	})
+
+	return collection
+}
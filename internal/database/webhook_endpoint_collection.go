@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const webhookEndpointCollectionName = "webhook_endpoints"
+
+func (database *Database) CreateWebhookEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	endpoint.CreatedAt = time.Now()
+	endpoint.UpdatedAt = endpoint.CreatedAt
+
+	result, err := database.webhookEndpointCollection.InsertOne(ctx, endpoint)
+	if err != nil {
+		slog.Error("Failed to insert webhook endpoint", "error", err)
+		return nil, err
+	}
+
+	endpoint.ID = result.InsertedID.(bson.ObjectID)
+	return endpoint, nil
+}
+
+func (database *Database) ListWebhookEndpoints(ctx context.Context, tenant string) ([]*models.WebhookEndpoint, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetProjection(bson.M{"secret": 0})
+
+	cursor, err := database.webhookEndpointCollection.Find(ctx, bson.M{"tenant": tenant}, opts)
+	if err != nil {
+		slog.Error("Failed to list webhook endpoints", "error", err)
+		return nil, err
+	}
+
+	endpoints := make([]*models.WebhookEndpoint, 0)
+	if err = cursor.All(ctx, &endpoints); err != nil {
+		slog.Error("Failed to decode webhook endpoints", "error", err)
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// ListWebhookEndpointsForEvent returns the tenant's enabled endpoints subscribed to an event type
+func (database *Database) ListWebhookEndpointsForEvent(ctx context.Context, tenant string, eventType models.WebhookEventType) ([]*models.WebhookEndpoint, error) {
+	cursor, err := database.webhookEndpointCollection.Find(ctx, bson.M{"tenant": tenant, "enabled": true, "events": eventType})
+	if err != nil {
+		slog.Error("Failed to find webhook endpoints", "error", err)
+		return nil, err
+	}
+
+	var endpoints []*models.WebhookEndpoint
+	if err = cursor.All(ctx, &endpoints); err != nil {
+		slog.Error("Failed to decode webhook endpoints", "error", err)
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// GetWebhookEndpoint returns the endpoint including its secret, or nil when it doesn't exist
+func (database *Database) GetWebhookEndpoint(ctx context.Context, id bson.ObjectID) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	if err := database.webhookEndpointCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&endpoint); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+
+		slog.Error("Failed to find webhook endpoint", "error", err)
+		return nil, err
+	}
+
+	return &endpoint, nil
+}
+
+// UpdateWebhookEndpoint replaces the subscriber settings of the tenant's endpoint, enabling an endpoint
+// clears its failure state
+func (database *Database) UpdateWebhookEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) (bool, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"url":         endpoint.URL,
+			"description": endpoint.Description,
+			"events":      endpoint.Events,
+			"enabled":     endpoint.Enabled,
+			"updated_at":  time.Now(),
+		},
+	}
+	if endpoint.Enabled {
+		update["$set"].(bson.M)["consecutive_failures"] = 0
+		update["$unset"] = bson.M{"disabled_reason": "", "disabled_at": ""}
+	}
+
+	result, err := database.webhookEndpointCollection.UpdateOne(ctx, bson.M{"_id": endpoint.ID, "tenant": endpoint.Tenant}, update)
+	if err != nil {
+		slog.Error("Failed to update webhook endpoint", "error", err)
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// RecordWebhookEndpointResult resets the endpoint's failure count on success, or counts the
+// failure and disables the endpoint once disableAfter consecutive attempts failed.
+// Returns whether the endpoint got disabled.
+func (database *Database) RecordWebhookEndpointResult(ctx context.Context, id bson.ObjectID, succeeded bool, disableAfter int) (bool, error) {
+	if succeeded {
+		_, err := database.webhookEndpointCollection.UpdateOne(
+			ctx,
+			bson.M{"_id": id, "consecutive_failures": bson.M{"$ne": 0}},
+			bson.M{"$set": bson.M{"consecutive_failures": 0}})
+		if err != nil {
+			slog.Error("Failed to update webhook endpoint", "error", err)
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	var endpoint models.WebhookEndpoint
+	err := database.webhookEndpointCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"consecutive_failures": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&endpoint)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+
+		slog.Error("Failed to update webhook endpoint", "error", err)
+		return false, err
+	}
+
+	if disableAfter <= 0 || !endpoint.Enabled || endpoint.ConsecutiveFailures < disableAfter {
+		return false, nil
+	}
+
+	now := time.Now()
+	_, err = database.webhookEndpointCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "enabled": true},
+		bson.M{"$set": bson.M{
+			"enabled":         false,
+			"disabled_reason": "too many consecutive delivery failures",
+			"disabled_at":     now,
+			"updated_at":      now,
+		}})
+	if err != nil {
+		slog.Error("Failed to disable webhook endpoint", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteWebhookEndpoint removes the tenant's endpoint together with its delivery log
+func (database *Database) DeleteWebhookEndpoint(ctx context.Context, tenant string, id bson.ObjectID) (bool, error) {
+	result, err := database.webhookEndpointCollection.DeleteOne(ctx, bson.M{"_id": id, "tenant": tenant})
+	if err != nil {
+		slog.Error("Failed to delete webhook endpoint", "error", err)
+		return false, err
+	}
+	if result.DeletedCount == 0 {
+		return false, nil
+	}
+
+	if _, err = database.webhookDeliveryCollection.DeleteMany(ctx, bson.M{"endpoint_id": id}); err != nil {
+		slog.Error("Failed to delete webhook deliveries", "error", err)
+		return true, err
+	}
+
+	return true, nil
+}
+
+func (database *Database) initWebhookEndpointCollection(ctx context.Context) *mongo.Collection {
+	database.createCollection(ctx, webhookEndpointCollectionName, bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": []string{"url", "events", "secret", "enabled", "consecutive_failures", "created_at", "updated_at"},
+			"properties": bson.M{
+				"tenant": bson.M{
+					"bsonType":    "string",
+					"description": "must be the ID of the tenant the endpoint belongs to",
+				},
+				"url": bson.M{
+					"bsonType":    "string",
+					"pattern":     "^https?://",
+					"maxLength":   2048,
+					"description": "must be an http(s) URL and is required",
+				},
+				"description": bson.M{
+					"bsonType":    "string",
+					"maxLength":   255,
+					"description": "must be a string up to 255 characters",
+				},
+				"events": bson.M{
+					"bsonType": "array",
+					"minItems": 1,
+					"items": bson.M{
+						"bsonType": "string",
+						"enum":     []string{"sent", "failed", "bounced", "complained", "opened", "clicked", "unsubscribed"},
+					},
+					"description": "must be a non-empty array of event types and is required",
+				},
+				"secret": bson.M{
+					"bsonType":    "string",
+					"description": "must be a string and is required",
+				},
+				"enabled": bson.M{
+					"bsonType":    "bool",
+					"description": "must be a boolean and is required",
+				},
+				"consecutive_failures": bson.M{
+					"bsonType":    []string{"int", "long"},
+					"minimum":     0,
+					"description": "must be a non-negative integer and is required",
+				},
+				"created_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+				"updated_at": bson.M{
+					"bsonType":    "date",
+					"description": "must be a date and is required",
+				},
+			},
+		},
+	})
+
+	collection := database.db.Collection(webhookEndpointCollectionName)
+
+	database.backfillTenant(ctx, collection, bson.M{})
+
+	// Replaced by the tenant-scoped index below, events only go to their tenant's subscribers
+	database.dropIndexes(ctx, collection, "events_enabled")
+
+	database.createIndexes(ctx, collection, []mongo.IndexModel{
+		// Index for finding the subscribers of an event
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "events", Value: 1}, {Key: "enabled", Value: 1}},
+			Options: options.Index().SetName("tenant_events_enabled"),
+		},
+		// Index for listing the tenant's endpoints
+		{
+			Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("tenant_created_at"),
+		},
+	})
+
+	return collection
+}
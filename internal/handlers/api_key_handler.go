@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyService types.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService types.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) RegisterRouter(router *gin.Engine) {
+	apiKeysV1 := router.Group("/api/v1/api_keys")
+	{
+		apiKeysV1.GET("/", h.ListAPIKeys)
+		apiKeysV1.POST("/", h.CreateAPIKey)
+		apiKeysV1.DELETE("/:id", h.RevokeAPIKey)
+	}
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIKeyListResponse{APIKeys: keys})
+}
+
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var params models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// RevokeAPIKey returns the revoked key, requests made with it fail from then on
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	key, err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Routes that need the send scope, every other /api/v1 route needs read for GET and admin otherwise
+var sendRoutes = map[string]bool{
+	"POST /api/v1/email/":                     true,
+	"POST /api/v1/email/batch":                true,
+	"POST /api/v1/telegram/":                  true,
+	"POST /api/v1/telegram/:id/retry":         true,
+	"POST /api/v1/inbox/":                     true,
+	"POST /api/v1/inbox/:user_id/read_all":    true,
+	"POST /api/v1/inbox/:user_id/:id/read":    true,
+	"POST /api/v1/inbox/:user_id/:id/unread":  true,
+	"POST /api/v1/inbox/:user_id/:id/archive": true,
+}
+
+// Scope each gRPC method needs, methods missing here need admin. Health checks are public.
+var grpcMethodScopes = map[string]models.APIKeyScope{
+	"/email.EmailService/SendEmail":         models.ScopeSend,
+	"/email.EmailService/SendEmailBatch":    models.ScopeSend,
+	"/email.EmailService/GetEmail":          models.ScopeRead,
+	"/email.EmailService/ListEmailEvents":   models.ScopeRead,
+	"/email.EmailService/WatchEmail":        models.ScopeRead,
+	"/email.EmailService/WatchEmails":       models.ScopeRead,
+	"/telegram.TelegramService/SendMessage": models.ScopeSend,
+	"/inbox.InboxService/CreateItem":        models.ScopeSend,
+	"/inbox.InboxService/MarkRead":          models.ScopeSend,
+	"/inbox.InboxService/Archive":           models.ScopeSend,
+	"/inbox.InboxService/ListItems":         models.ScopeRead,
+	"/inbox.InboxService/CountUnread":       models.ScopeRead,
+	"/inbox.InboxService/StreamItems":       models.ScopeRead,
+	"/stats.StatsService/GetEmailStats":     models.ScopeRead,
+}
+
+// Route prefixes that need admin for every method, since they manage credentials and secrets
+var adminRoutePrefixes = []string{"/api/v1/api_keys", "/api/v1/webhooks", "/api/v1/tenants"}
+
+// Routes and gRPC services that work on the caller's tenant's data. The others manage tenants or
+// resources shared by every tenant and are reserved to platform principals.
+var (
+	tenantRoutePrefixes = []string{"/api/v1/email/", "/api/v1/clicks", "/api/v1/stats", "/api/v1/suppressions/", "/api/v1/preferences/", "/api/v1/recipients/", "/api/v1/api_keys/", "/api/v1/usage", "/api/v1/webhooks"}
+	tenantGRPCServices  = []string{"/email.EmailService/", "/stats.StatsService/"}
+)
+
+const grpcHealthService = "/grpc.health.v1.Health/"
+
+// Authenticator checks the bearer JWT, API key or client certificate of HTTP and gRPC requests against
+// the scope and tenant of the route or method called, and puts the caller's principal on the request
+// context
+type Authenticator struct {
+	apiKeyService types.APIKeyService
+	tenantService types.TenantService
+	jwtVerifier   *auth.JWTVerifier
+	certificates  *auth.CertificateMapper
+	disabled      bool
+}
+
+func NewAuthenticator(apiKeyService types.APIKeyService, tenantService types.TenantService, cfg *config.Config) (*Authenticator, error) {
+	if cfg.Auth.Disabled {
+		slog.Warn("API authentication is disabled, every caller has full access")
+	}
+
+	jwtVerifier, err := auth.NewJWTVerifier(cfg.Auth.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates, err := auth.NewCertificateMapper(cfg.Server.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{
+		apiKeyService: apiKeyService,
+		tenantService: tenantService,
+		jwtVerifier:   jwtVerifier,
+		certificates:  certificates,
+		disabled:      cfg.Auth.Disabled,
+	}, nil
+}
+
+// GinMiddleware authenticates requests to /api/v1. Tracking, unsubscribe, health and metrics
+// routes stay public since they are opened by recipients' mail clients and by orchestrators.
+func (a *Authenticator) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if a.disabled || !strings.HasPrefix(route, "/api/v1/") {
+			c.Next()
+			return
+		}
+
+		ctx, err := a.authenticate(
+			c.Request.Context(),
+			bearerKey(c.GetHeader("Authorization"), c.GetHeader("X-API-Key")),
+			c.Request.TLS,
+			routeScope(c.Request.Method, route),
+			hasAnyPrefix(route, tenantRoutePrefixes),
+		)
+		if err != nil {
+			if httpStatus(err) == http.StatusUnauthorized {
+				c.Header("WWW-Authenticate", `Bearer realm="notiflow"`)
+			}
+			c.AbortWithStatusJSON(httpStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func (a *Authenticator) UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticateGRPC(ctx, info.FullMethod)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *Authenticator) StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticateGRPC(stream.Context(), info.FullMethod)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+func (a *Authenticator) authenticateGRPC(ctx context.Context, method string) (context.Context, error) {
+	if a.disabled || strings.HasPrefix(method, grpcHealthService) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var authorization, apiKey string
+	if values := md.Get("authorization"); len(values) > 0 {
+		authorization = values[0]
+	}
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		apiKey = values[0]
+	}
+
+	scope, ok := grpcMethodScopes[method]
+	if !ok {
+		scope = models.ScopeAdmin
+	}
+
+	var connection *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			connection = &info.State
+		}
+	}
+
+	return a.authenticate(ctx, bearerKey(authorization, apiKey), connection, scope, hasAnyPrefix(method, tenantGRPCServices))
+}
+
+// authenticate returns ctx carrying the principal of a bearer JWT or API key, or without one of the
+// connection's client certificate, when it is valid and grants scope. Principals of a tenant must
+// belong to an enabled one and may only call tenantScoped routes.
+func (a *Authenticator) authenticate(ctx context.Context, token string, connection *tls.ConnectionState, scope models.APIKeyScope, tenantScoped bool) (context.Context, error) {
+	var principal *models.Principal
+	var err error
+	certificatePrincipal := a.certificates.Principal(connection)
+	switch {
+	case token == "" && certificatePrincipal != nil:
+		principal = certificatePrincipal
+	case a.jwtVerifier.Enabled() && auth.IsJWT(token):
+		principal, err = a.jwtVerifier.Verify(ctx, token)
+	default:
+		principal, err = a.apiKeyService.Authenticate(ctx, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !principal.HasScope(scope) {
+		return nil, fmt.Errorf("%w: %s %q lacks the %s scope", types.ErrPermissionDenied, principal.Kind, principal.Name, scope)
+	}
+	if !principal.IsPlatform() {
+		if !tenantScoped {
+			return nil, fmt.Errorf("%w: %s %q of tenant %s can't manage shared resources", types.ErrPermissionDenied, principal.Kind, principal.Name, principal.Tenant)
+		}
+		if err = a.tenantService.CheckTenant(ctx, principal.Tenant); err != nil {
+			return nil, err
+		}
+	}
+
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+// routeScope returns the scope a /api/v1 route needs
+func routeScope(method, route string) models.APIKeyScope {
+	switch {
+	case hasAnyPrefix(route, adminRoutePrefixes):
+		return models.ScopeAdmin
+	case sendRoutes[method+" "+route]:
+		return models.ScopeSend
+	case method == http.MethodGet || method == http.MethodHead:
+		return models.ScopeRead
+	default:
+		return models.ScopeAdmin
+	}
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bearerKey returns the token of an "Authorization: Bearer" header, or the key of an X-API-Key header
+func bearerKey(authorization, apiKey string) string {
+	if scheme, key, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(key)
+	}
+
+	return strings.TrimSpace(apiKey)
+}
+
+// authenticatedStream replaces the stream's context with one carrying the caller's principal
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
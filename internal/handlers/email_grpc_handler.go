@@ -2,10 +2,16 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"github.com/aarondever/notiflow/internal/models"
 	"github.com/aarondever/notiflow/internal/types"
 	pb "github.com/aarondever/notiflow/proto/email"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -21,7 +27,318 @@ func NewEmailGRPCHandler(emailService types.EmailService) *EmailGRPCHandler {
 }
 
 func (h *EmailGRPCHandler) SendEmail(ctx context.Context, request *pb.SendEmailRequest) (*pb.SendEmailResponse, error) {
-	// Convert proto request to internal model
+	email, err := h.emailService.SendEmail(ctx, emailFromProto(request))
+	if err != nil {
+		if seconds, ok := retryAfter(err); ok {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", seconds))
+		}
+		return nil, grpcError(err)
+	}
+
+	return &pb.SendEmailResponse{
+		Id:        email.ID.Hex(),
+		Status:    string(models.StatusPending),
+		Message:   "Email queued for sending",
+		CreatedAt: timestamppb.New(email.CreatedAt),
+	}, nil
+}
+
+// SendEmailBatch queues many emails at once, invalid emails get an error in their result
+func (h *EmailGRPCHandler) SendEmailBatch(ctx context.Context, request *pb.SendEmailBatchRequest) (*pb.SendEmailBatchResponse, error) {
+	if len(request.Emails) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "emails is required")
+	}
+
+	emails := make([]*models.Email, len(request.Emails))
+	for i, emailRequest := range request.Emails {
+		emails[i] = emailFromProto(emailRequest)
+	}
+
+	errs, err := h.emailService.SendEmailBatch(ctx, emails)
+	if err != nil {
+		if seconds, ok := retryAfter(err); ok {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", seconds))
+		}
+		return nil, grpcError(err)
+	}
+
+	response := &pb.SendEmailBatchResponse{Results: make([]*pb.SendEmailBatchResult, len(emails))}
+	for i, email := range emails {
+		result := &pb.SendEmailBatchResult{Index: int32(i)}
+		if errs[i] != nil {
+			result.Error = errs[i].Error()
+			response.Failed++
+		} else {
+			result.Id = email.ID.Hex()
+			response.Queued++
+			response.CreatedAt = timestamppb.New(email.CreatedAt)
+		}
+		response.Results[i] = result
+	}
+
+	return response, nil
+}
+
+func (h *EmailGRPCHandler) GetEmail(ctx context.Context, request *pb.GetEmailRequest) (*pb.Email, error) {
+	if _, err := bson.ObjectIDFromHex(request.Id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid email ID")
+	}
+
+	email, err := h.emailService.GetEmail(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if email == nil {
+		return nil, status.Error(codes.NotFound, "email not found")
+	}
+
+	return emailToProto(email), nil
+}
+
+func (h *EmailGRPCHandler) ListEmailEvents(ctx context.Context, request *pb.ListEmailEventsRequest) (*pb.ListEmailEventsResponse, error) {
+	events, err := h.emailService.ListEmailEvents(ctx, request.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	response := &pb.ListEmailEventsResponse{Events: make([]*pb.EmailEvent, len(events))}
+	for i, event := range events {
+		response.Events[i] = emailEventToProto(event)
+	}
+
+	return response, nil
+}
+
+func (h *EmailGRPCHandler) WatchEmail(request *pb.WatchEmailRequest, stream grpc.ServerStreamingServer[pb.Email]) error {
+	emails, err := h.emailService.WatchEmail(stream.Context(), request.Id)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	return sendEmails(stream, emails)
+}
+
+func (h *EmailGRPCHandler) WatchEmails(request *pb.WatchEmailsRequest, stream grpc.ServerStreamingServer[pb.Email]) error {
+	filter := &models.EmailWatchFilter{
+		Category: request.Category,
+		UserID:   request.UserId,
+	}
+	for _, status := range request.Statuses {
+		filter.Statuses = append(filter.Statuses, models.EmailStatus(status))
+	}
+
+	emails, err := h.emailService.WatchEmails(stream.Context(), filter)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	return sendEmails(stream, emails)
+}
+
+// sendEmails forwards a watch to the client, the watch ends when the client cancels or the deadline passes
+func sendEmails(stream grpc.ServerStreamingServer[pb.Email], emails <-chan *models.Email) error {
+	for email := range emails {
+		if err := stream.Send(emailToProto(email)); err != nil {
+			return err
+		}
+	}
+
+	return status.FromContextError(stream.Context().Err()).Err()
+}
+
+func emailToProto(email *models.Email) *pb.Email {
+	return &pb.Email{
+		Id:           email.ID.Hex(),
+		UserId:       email.UserID,
+		To:           email.To,
+		Cc:           email.CC,
+		Bcc:          email.BCC,
+		Subject:      email.Subject,
+		IsHtml:       email.IsHTML,
+		Status:       string(email.Status),
+		ErrorMessage: email.ErrorMsg,
+		CreatedAt:    timestamppb.New(email.CreatedAt),
+		SentAt:       optionalTimestamp(email.SentAt),
+		Fallback:     fallbackPlanToProto(email.Fallback),
+		Category:     email.Category,
+		Suppressed:   suppressedRecipientsToProto(email.Suppressed),
+		Bounces:      bouncesToProto(email.Bounces),
+		Complaints:   complaintsToProto(email.Complaints),
+		Recipients:   emailRecipientsToProto(email.Recipients),
+		UpdatedAt:    optionalTimestamp(email.UpdatedAt),
+		TrackOpens:   email.TrackOpens,
+		TrackClicks:  email.TrackClicks,
+		CreatedBy:    principalToProto(email.CreatedBy),
+		Tenant:       email.Tenant,
+		From:         email.From,
+	}
+}
+
+func principalToProto(principal *models.Principal) *pb.Principal {
+	if principal == nil {
+		return nil
+	}
+
+	return &pb.Principal{
+		Kind:   string(principal.Kind),
+		Id:     principal.ID,
+		Name:   principal.Name,
+		Tenant: principal.Tenant,
+	}
+}
+
+func suppressedRecipientsToProto(suppressed []models.SuppressedRecipient) []*pb.SuppressedRecipient {
+	result := make([]*pb.SuppressedRecipient, len(suppressed))
+	for i, recipient := range suppressed {
+		result[i] = &pb.SuppressedRecipient{
+			Address: recipient.Address,
+			Reason:  recipient.Reason,
+		}
+	}
+
+	return result
+}
+
+func emailRecipientsToProto(recipients []models.EmailRecipient) []*pb.EmailRecipient {
+	result := make([]*pb.EmailRecipient, len(recipients))
+	for i, recipient := range recipients {
+		result[i] = &pb.EmailRecipient{
+			Address:        recipient.Address,
+			Kind:           string(recipient.Kind),
+			Status:         string(recipient.Status),
+			SmtpCode:       int32(recipient.SMTPCode),
+			SmtpResponse:   recipient.SMTPResponse,
+			Server:         recipient.Server,
+			Sender:         recipient.Sender,
+			Reason:         recipient.Reason,
+			SentAt:         optionalTimestamp(recipient.SentAt),
+			UpdatedAt:      timestamppb.New(recipient.UpdatedAt),
+			OpenCount:      int32(recipient.OpenCount),
+			FirstOpenedAt:  optionalTimestamp(recipient.FirstOpenedAt),
+			LastOpenedAt:   optionalTimestamp(recipient.LastOpenedAt),
+			ClickCount:     int32(recipient.ClickCount),
+			FirstClickedAt: optionalTimestamp(recipient.FirstClickedAt),
+			LastClickedAt:  optionalTimestamp(recipient.LastClickedAt),
+		}
+		if recipient.Bounce != nil {
+			result[i].Bounce = bounceToProto(*recipient.Bounce)
+		}
+	}
+
+	return result
+}
+
+func bouncesToProto(bounces []models.Bounce) []*pb.Bounce {
+	result := make([]*pb.Bounce, len(bounces))
+	for i, bounce := range bounces {
+		result[i] = bounceToProto(bounce)
+	}
+
+	return result
+}
+
+func bounceToProto(bounce models.Bounce) *pb.Bounce {
+	return &pb.Bounce{
+		Address:        bounce.Address,
+		Action:         bounce.Action,
+		Status:         bounce.Status,
+		DiagnosticCode: bounce.DiagnosticCode,
+		Permanent:      bounce.Permanent,
+		ReportedAt:     timestamppb.New(bounce.ReportedAt),
+	}
+}
+
+func complaintsToProto(complaints []models.Complaint) []*pb.Complaint {
+	result := make([]*pb.Complaint, len(complaints))
+	for i, complaint := range complaints {
+		result[i] = &pb.Complaint{
+			Address:      complaint.Address,
+			FeedbackType: complaint.FeedbackType,
+			UserAgent:    complaint.UserAgent,
+			ReportedAt:   timestamppb.New(complaint.ReportedAt),
+		}
+	}
+
+	return result
+}
+
+func emailEventToProto(event *models.EmailEvent) *pb.EmailEvent {
+	return &pb.EmailEvent{
+		Id:        event.ID.Hex(),
+		EmailId:   event.EmailID.Hex(),
+		Type:      string(event.Type),
+		Recipient: event.Recipient,
+		Server:    event.Server,
+		SmtpCode:  int32(event.SMTPCode),
+		QueueId:   event.QueueID,
+		Detail:    event.Detail,
+		CreatedAt: timestamppb.New(event.CreatedAt),
+		UserAgent: event.UserAgent,
+		IpHash:    event.IPHash,
+		Prefetch:  event.Prefetch,
+		Url:       event.URL,
+		Category:  event.Category,
+	}
+}
+
+func fallbackPlanFromProto(plan *pb.FallbackPlan) *models.FallbackPlan {
+	if plan == nil {
+		return nil
+	}
+
+	result := &models.FallbackPlan{
+		EscalateAfterMinutes: int(plan.EscalateAfterMinutes),
+		Steps:                make([]models.FallbackStep, len(plan.Steps)),
+	}
+	for i, step := range plan.Steps {
+		result.Steps[i] = models.FallbackStep{
+			Action:  models.FallbackAction(step.Action),
+			To:      step.To,
+			Webhook: step.Webhook,
+		}
+	}
+
+	return result
+}
+
+func fallbackPlanToProto(plan *models.FallbackPlan) *pb.FallbackPlan {
+	if plan == nil {
+		return nil
+	}
+
+	result := &pb.FallbackPlan{
+		EscalateAfterMinutes: int32(plan.EscalateAfterMinutes),
+		Steps:                make([]*pb.FallbackStep, len(plan.Steps)),
+		State:                string(plan.State),
+		CurrentStep:          int32(plan.CurrentStep),
+		Reason:               plan.Reason,
+		EscalateAt:           optionalTimestamp(plan.EscalateAt),
+		EscalatedAt:          optionalTimestamp(plan.EscalatedAt),
+	}
+	for i, step := range plan.Steps {
+		result.Steps[i] = &pb.FallbackStep{
+			Action:      string(step.Action),
+			To:          step.To,
+			Webhook:     step.Webhook,
+			Status:      string(step.Status),
+			Error:       step.Error,
+			CompletedAt: optionalTimestamp(step.CompletedAt),
+		}
+	}
+
+	return result
+}
+
+// optionalTimestamp leaves unset times out of the response instead of sending the zero time
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+func emailFromProto(request *pb.SendEmailRequest) *models.Email {
 	attachments := make([]models.Attachment, len(request.Attachments))
 	for i, att := range request.Attachments {
 		attachments[i] = models.Attachment{
@@ -31,7 +348,9 @@ func (h *EmailGRPCHandler) SendEmail(ctx context.Context, request *pb.SendEmailR
 		}
 	}
 
-	email, err := h.emailService.SendEmail(ctx, &models.Email{
+	return &models.Email{
+		From:        request.From,
+		UserID:      request.UserId,
 		To:          request.To,
 		CC:          request.Cc,
 		BCC:         request.Bcc,
@@ -39,15 +358,9 @@ func (h *EmailGRPCHandler) SendEmail(ctx context.Context, request *pb.SendEmailR
 		Body:        request.Body,
 		IsHTML:      request.IsHtml,
 		Attachments: attachments,
-	})
-	if err != nil {
-		return nil, err
+		Fallback:    fallbackPlanFromProto(request.Fallback),
+		Category:    request.Category,
+		TrackOpens:  request.TrackOpens,
+		TrackClicks: request.TrackClicks,
 	}
-
-	return &pb.SendEmailResponse{
-		Id:        email.ID.Hex(),
-		Status:    string(models.StatusPending),
-		Message:   "Email queued for sending",
-		CreatedAt: timestamppb.New(email.CreatedAt),
-	}, nil
 }
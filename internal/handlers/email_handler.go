@@ -6,6 +6,7 @@ import (
 	"github.com/aarondever/notiflow/internal/models"
 	"github.com/aarondever/notiflow/internal/types"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type EmailHandler struct {
@@ -22,6 +23,9 @@ func (h *EmailHandler) RegisterRouter(router *gin.Engine) {
 	emailV1 := router.Group("/api/v1/email")
 	{
 		emailV1.POST("/", h.SendEmail)
+		emailV1.POST("/batch", h.SendEmailBatch)
+		emailV1.GET("/:id", h.GetEmail)
+		emailV1.GET("/:id/events", h.ListEmailEvents)
 	}
 }
 
@@ -32,17 +36,12 @@ func (h *EmailHandler) SendEmail(c *gin.Context) {
 		return
 	}
 
-	email, err := h.emailService.SendEmail(c.Request.Context(), &models.Email{
-		To:          params.To,
-		CC:          params.CC,
-		BCC:         params.BCC,
-		Subject:     params.Subject,
-		Body:        params.Body,
-		IsHTML:      params.IsHTML,
-		Attachments: params.Attachments,
-	})
+	email, err := h.emailService.SendEmail(c.Request.Context(), params.ToEmail())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if seconds, ok := retryAfter(err); ok {
+			c.Header("Retry-After", seconds)
+		}
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -53,3 +52,77 @@ func (h *EmailHandler) SendEmail(c *gin.Context) {
 		CreatedAt: email.CreatedAt,
 	})
 }
+
+// SendEmailBatch queues many emails at once. A body that fails binding is rejected as a whole, the
+// service's checks don't fail the batch and give the email an error in its result instead.
+func (h *EmailHandler) SendEmailBatch(c *gin.Context) {
+	var params models.SendEmailBatchRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	emails := make([]*models.Email, len(params.Emails))
+	for i := range params.Emails {
+		emails[i] = params.Emails[i].ToEmail()
+	}
+
+	errs, err := h.emailService.SendEmailBatch(c.Request.Context(), emails)
+	if err != nil {
+		if seconds, ok := retryAfter(err); ok {
+			c.Header("Retry-After", seconds)
+		}
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := models.SendEmailBatchResponse{Results: make([]models.SendEmailBatchResult, len(emails))}
+	for i, email := range emails {
+		response.Results[i].Index = i
+		if errs[i] != nil {
+			response.Results[i].Error = errs[i].Error()
+			response.Failed++
+			continue
+		}
+
+		response.Results[i].ID = email.ID.Hex()
+		response.Queued++
+		response.CreatedAt = email.CreatedAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *EmailHandler) GetEmail(c *gin.Context) {
+	if _, err := bson.ObjectIDFromHex(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid email ID"})
+		return
+	}
+
+	email, err := h.emailService.GetEmail(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if email == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "email not found"})
+		return
+	}
+
+	// Attachment content can be large, only return its metadata
+	for i := range email.Attachments {
+		email.Attachments[i].Content = nil
+	}
+
+	c.JSON(http.StatusOK, email)
+}
+
+func (h *EmailHandler) ListEmailEvents(c *gin.Context) {
+	events, err := h.emailService.ListEmailEvents(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.EmailEventsResponse{Events: events})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/aarondever/notiflow/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// httpStatus maps a service error to the HTTP status code returned to the client
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, types.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, types.ErrResourceExhausted):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// grpcError maps a service error to a gRPC status error
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, types.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, types.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, types.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, types.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, types.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, types.ErrResourceExhausted):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return err
+	}
+}
+
+// retryAfter returns the Retry-After value in whole seconds for a rate limit error, false for any
+// other error
+func retryAfter(err error) (string, bool) {
+	var rateLimitErr *types.RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter <= 0 {
+		return "", false
+	}
+
+	return strconv.Itoa(int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))), true
+}
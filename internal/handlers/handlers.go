@@ -5,4 +5,24 @@ import "github.com/google/wire"
 var ProviderSet = wire.NewSet(
 	NewEmailHandler,
 	NewEmailGRPCHandler,
+	NewTelegramHandler,
+	NewTelegramGRPCHandler,
+	NewInboxHandler,
+	NewInboxGRPCHandler,
+	NewRecipientHandler,
+	NewPreferenceHandler,
+	NewSuppressionHandler,
+	NewUnsubscribeHandler,
+	NewInboundSMTPHandler,
+	NewWebhookHandler,
+	NewTrackingHandler,
+	NewStatsHandler,
+	NewStatsGRPCHandler,
+	NewMetricsHandler,
+	NewHealthHandler,
+	NewHealthGRPCHandler,
+	NewAPIKeyHandler,
+	NewTenantHandler,
+	NewUsageHandler,
+	NewAuthenticator,
 )
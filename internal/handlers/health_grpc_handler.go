@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/types"
+	emailpb "github.com/aarondever/notiflow/proto/email"
+	inboxpb "github.com/aarondever/notiflow/proto/inbox"
+	statspb "github.com/aarondever/notiflow/proto/stats"
+	telegrampb "github.com/aarondever/notiflow/proto/telegram"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthGRPCHandler serves grpc.health.v1, its Run keeps the status of the server ("") and of each
+// service in sync with the readiness checks
+type HealthGRPCHandler struct {
+	*health.Server
+	healthService types.HealthService
+	cfg           *config.Config
+}
+
+func NewHealthGRPCHandler(healthService types.HealthService, cfg *config.Config) *HealthGRPCHandler {
+	h := &HealthGRPCHandler{
+		Server:        health.NewServer(),
+		healthService: healthService,
+		cfg:           cfg,
+	}
+
+	// Not serving until the first readiness check passed
+	for _, service := range grpcHealthServices {
+		h.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return h
+}
+
+// Services reported individually, all share the readiness of the server
+var grpcHealthServices = []string{
+	"",
+	emailpb.EmailService_ServiceDesc.ServiceName,
+	telegrampb.TelegramService_ServiceDesc.ServiceName,
+	inboxpb.InboxService_ServiceDesc.ServiceName,
+	statspb.StatsService_ServiceDesc.ServiceName,
+}
+
+func (h *HealthGRPCHandler) Run(ctx context.Context) {
+	interval := time.Duration(h.cfg.Health.CheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	serving := healthpb.HealthCheckResponse_NOT_SERVING
+	for {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if h.healthService.Readiness(ctx).Ready() {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+
+		if status != serving {
+			slog.Info("gRPC health status changed", "status", status)
+			for _, service := range grpcHealthServices {
+				h.SetServingStatus(service, status)
+			}
+			serving = status
+		}
+
+		select {
+		case <-ctx.Done():
+			// Tell clients to move on while in-flight calls drain
+			h.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
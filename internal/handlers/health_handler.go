@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService types.HealthService
+}
+
+func NewHealthHandler(healthService types.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+func (h *HealthHandler) RegisterRouter(router *gin.Engine) {
+	router.GET("/api/health", h.Live)
+	router.GET("/api/health/live", h.Live)
+	router.GET("/api/health/ready", h.Ready)
+}
+
+// Live only tells the process is serving requests, so a dependency outage doesn't get it restarted
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "healthy", "service": "notiflow"})
+}
+
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+	if !report.Ready() {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/emersion/go-smtp"
+)
+
+// How long the inbound service may take with a message before the sender is told to retry
+const inboundProcessTimeout = 30 * time.Second
+
+// InboundSMTPHandler accepts bounces, feedback reports and replies addressed to the VERP and
+// reply domains. It's an smtp.Backend for the server returned by NewServer.
+type InboundSMTPHandler struct {
+	inboundService types.InboundService
+	cfg            *config.Config
+	domains        []string
+}
+
+func NewInboundSMTPHandler(inboundService types.InboundService, cfg *config.Config) *InboundSMTPHandler {
+	var domains []string
+	for _, domain := range []string{cfg.Bounce.VERPDomain, cfg.Inbound.ReplyDomain} {
+		if domain != "" {
+			domains = append(domains, strings.ToLower(domain))
+		}
+	}
+
+	return &InboundSMTPHandler{
+		inboundService: inboundService,
+		cfg:            cfg,
+		domains:        domains,
+	}
+}
+
+// NewServer returns the inbound SMTP server, or nil when the listener is disabled
+func (h *InboundSMTPHandler) NewServer() *smtp.Server {
+	if h.cfg.Inbound.Port == 0 {
+		return nil
+	}
+	if len(h.domains) == 0 {
+		slog.Warn("Inbound SMTP listener has no domains to accept mail for, set a VERP or reply domain")
+	}
+
+	server := smtp.NewServer(h)
+	server.Addr = fmt.Sprintf("%s:%d", h.cfg.Server.Host, h.cfg.Inbound.Port)
+	server.Domain = h.cfg.Bounce.MessageIDDomain
+	server.MaxMessageBytes = h.cfg.Inbound.MaxMessageBytes
+	server.MaxRecipients = 50
+	server.ReadTimeout = time.Minute
+	server.WriteTimeout = time.Minute
+
+	return server
+}
+
+// Login rejects authentication, the listener only receives mail for its own domains
+func (h *InboundSMTPHandler) Login(state *smtp.ConnectionState, username, password string) (smtp.Session, error) {
+	return nil, smtp.ErrAuthUnsupported
+}
+
+func (h *InboundSMTPHandler) AnonymousLogin(state *smtp.ConnectionState) (smtp.Session, error) {
+	return &inboundSession{handler: h, remote: state.RemoteAddr.String()}, nil
+}
+
+func (h *InboundSMTPHandler) accepts(address string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(address), "@")
+	if !ok {
+		return false
+	}
+
+	for _, accepted := range h.domains {
+		if domain == accepted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inboundSession is one SMTP transaction
+type inboundSession struct {
+	handler *InboundSMTPHandler
+	remote  string
+	from    string
+	to      []string
+}
+
+func (s *inboundSession) Mail(from string, opts smtp.MailOptions) error {
+	s.from = from
+	return nil
+}
+
+func (s *inboundSession) Rcpt(to string) error {
+	// Never relay, only accept our own domains
+	if !s.handler.accepts(to) {
+		return &smtp.SMTPError{
+			Code:         550,
+			EnhancedCode: smtp.EnhancedCode{5, 1, 1},
+			Message:      "Mailbox does not exist",
+		}
+	}
+
+	s.to = append(s.to, to)
+	return nil
+}
+
+func (s *inboundSession) Data(r io.Reader) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inboundProcessTimeout)
+	defer cancel()
+
+	if err = s.handler.inboundService.Receive(ctx, s.from, s.to, raw); err != nil {
+		slog.Error("Failed to process inbound message", "error", err, "remote", s.remote)
+		return &smtp.SMTPError{
+			Code:         451,
+			EnhancedCode: smtp.EnhancedCode{4, 3, 0},
+			Message:      "Temporary failure, try again later",
+		}
+	}
+
+	return nil
+}
+
+func (s *inboundSession) Reset() {
+	s.from = ""
+	s.to = nil
+}
+
+func (s *inboundSession) Logout() error {
+	return nil
+}
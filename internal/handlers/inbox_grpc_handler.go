@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	pb "github.com/aarondever/notiflow/proto/inbox"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type InboxGRPCHandler struct {
+	inboxService types.InboxService
+	pb.UnimplementedInboxServiceServer
+}
+
+func NewInboxGRPCHandler(inboxService types.InboxService) *InboxGRPCHandler {
+	return &InboxGRPCHandler{
+		inboxService: inboxService,
+	}
+}
+
+func (h *InboxGRPCHandler) CreateItem(ctx context.Context, request *pb.CreateItemRequest) (*pb.InboxItem, error) {
+	if request.UserId == "" || request.Title == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and title are required")
+	}
+
+	item, err := h.inboxService.CreateItem(ctx, &models.InboxItem{
+		UserID: request.UserId,
+		Title:  request.Title,
+		Body:   request.Body,
+		Link:   request.Link,
+		Data:   request.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inboxItemToProto(item), nil
+}
+
+func (h *InboxGRPCHandler) ListItems(ctx context.Context, request *pb.ListItemsRequest) (*pb.ListItemsResponse, error) {
+	filter := models.InboxFilter{
+		UserID:     request.UserId,
+		UnreadOnly: request.UnreadOnly,
+		Archived:   request.Archived,
+		Limit:      request.Limit,
+	}
+
+	if request.Before != "" {
+		before, err := bson.ObjectIDFromHex(request.Before)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		filter.Before = before
+	}
+
+	items, err := h.inboxService.ListItems(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ListItemsResponse{Items: make([]*pb.InboxItem, len(items))}
+	for i, item := range items {
+		response.Items[i] = inboxItemToProto(item)
+	}
+	if len(items) > 0 {
+		response.NextCursor = items[len(items)-1].ID.Hex()
+	}
+
+	return response, nil
+}
+
+func (h *InboxGRPCHandler) MarkRead(ctx context.Context, request *pb.MarkReadRequest) (*pb.InboxItem, error) {
+	if _, err := bson.ObjectIDFromHex(request.Id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid inbox item ID")
+	}
+
+	item, err := h.inboxService.MarkRead(ctx, request.UserId, request.Id, request.Read)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, status.Error(codes.NotFound, "inbox item not found")
+	}
+
+	return inboxItemToProto(item), nil
+}
+
+func (h *InboxGRPCHandler) Archive(ctx context.Context, request *pb.ArchiveRequest) (*pb.InboxItem, error) {
+	if _, err := bson.ObjectIDFromHex(request.Id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid inbox item ID")
+	}
+
+	item, err := h.inboxService.Archive(ctx, request.UserId, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, status.Error(codes.NotFound, "inbox item not found")
+	}
+
+	return inboxItemToProto(item), nil
+}
+
+func (h *InboxGRPCHandler) CountUnread(ctx context.Context, request *pb.CountUnreadRequest) (*pb.CountUnreadResponse, error) {
+	count, err := h.inboxService.CountUnread(ctx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CountUnreadResponse{
+		UserId: request.UserId,
+		Unread: count,
+	}, nil
+}
+
+func (h *InboxGRPCHandler) StreamItems(request *pb.StreamItemsRequest, stream grpc.ServerStreamingServer[pb.InboxItem]) error {
+	if request.UserId == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	for item := range h.inboxService.Subscribe(stream.Context(), request.UserId) {
+		if err := stream.Send(inboxItemToProto(item)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func inboxItemToProto(item *models.InboxItem) *pb.InboxItem {
+	result := &pb.InboxItem{
+		Id:        item.ID.Hex(),
+		UserId:    item.UserID,
+		Title:     item.Title,
+		Body:      item.Body,
+		Link:      item.Link,
+		Data:      item.Data,
+		Read:      item.Read,
+		Archived:  item.Archived,
+		CreatedAt: timestamppb.New(item.CreatedAt),
+	}
+	if item.ReadAt != nil {
+		result.ReadAt = timestamppb.New(*item.ReadAt)
+	}
+	if item.ArchivedAt != nil {
+		result.ArchivedAt = timestamppb.New(*item.ArchivedAt)
+	}
+
+	return result
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Keeps idle SSE connections open through proxies that close silent streams
+const inboxStreamHeartbeat = 30 * time.Second
+
+type InboxHandler struct {
+	inboxService types.InboxService
+}
+
+func NewInboxHandler(inboxService types.InboxService) *InboxHandler {
+	return &InboxHandler{
+		inboxService: inboxService,
+	}
+}
+
+func (h *InboxHandler) RegisterRouter(router *gin.Engine) {
+	inboxV1 := router.Group("/api/v1/inbox")
+	{
+		inboxV1.POST("/", h.CreateItem)
+		inboxV1.GET("/:user_id", h.ListItems)
+		inboxV1.GET("/:user_id/unread_count", h.CountUnread)
+		inboxV1.GET("/:user_id/stream", h.Stream)
+		inboxV1.POST("/:user_id/read_all", h.MarkAllRead)
+		inboxV1.POST("/:user_id/:id/read", h.MarkRead)
+		inboxV1.POST("/:user_id/:id/unread", h.MarkUnread)
+		inboxV1.POST("/:user_id/:id/archive", h.Archive)
+	}
+}
+
+func (h *InboxHandler) CreateItem(c *gin.Context) {
+	var params models.CreateInboxItemRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.inboxService.CreateItem(c.Request.Context(), &models.InboxItem{
+		UserID: params.UserID,
+		Title:  params.Title,
+		Body:   params.Body,
+		Link:   params.Link,
+		Data:   params.Data,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *InboxHandler) ListItems(c *gin.Context) {
+	var params models.ListInboxRequest
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := models.InboxFilter{
+		UserID:     c.Param("user_id"),
+		UnreadOnly: params.UnreadOnly,
+		Archived:   params.Archived,
+		Limit:      params.Limit,
+	}
+
+	if params.Before != "" {
+		before, err := bson.ObjectIDFromHex(params.Before)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		filter.Before = before
+	}
+
+	items, err := h.inboxService.ListItems(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := models.InboxListResponse{Items: items}
+	if len(items) > 0 {
+		response.NextCursor = items[len(items)-1].ID.Hex()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *InboxHandler) CountUnread(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	count, err := h.inboxService.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.InboxUnreadCountResponse{
+		UserID: userID,
+		Unread: count,
+	})
+}
+
+func (h *InboxHandler) MarkRead(c *gin.Context) {
+	h.markRead(c, true)
+}
+
+func (h *InboxHandler) MarkUnread(c *gin.Context) {
+	h.markRead(c, false)
+}
+
+func (h *InboxHandler) markRead(c *gin.Context, read bool) {
+	if _, err := bson.ObjectIDFromHex(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid inbox item ID"})
+		return
+	}
+
+	item, err := h.inboxService.MarkRead(c.Request.Context(), c.Param("user_id"), c.Param("id"), read)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if item == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "inbox item not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *InboxHandler) MarkAllRead(c *gin.Context) {
+	updated, err := h.inboxService.MarkAllRead(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (h *InboxHandler) Archive(c *gin.Context) {
+	if _, err := bson.ObjectIDFromHex(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid inbox item ID"})
+		return
+	}
+
+	item, err := h.inboxService.Archive(c.Request.Context(), c.Param("user_id"), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if item == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "inbox item not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// Stream pushes new inbox items to the client as Server-Sent Events
+func (h *InboxHandler) Stream(c *gin.Context) {
+	items := h.inboxService.Subscribe(c.Request.Context(), c.Param("user_id"))
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+
+	heartbeat := time.NewTicker(inboxStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case item, ok := <-items:
+			if !ok {
+				return false
+			}
+			c.SSEvent("item", item)
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now()})
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/aarondever/notiflow/internal/metrics"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type MetricsHandler struct {
+	registry *prometheus.Registry
+}
+
+// NewMetricsHandler exposes the default Prometheus registry, which holds the application metrics
+// and the Go runtime and process collectors, together with the email queue counts
+func NewMetricsHandler(statsService types.StatsService) *MetricsHandler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewQueueCollector(statsService.CountEmailsByStatus))
+
+	return &MetricsHandler{
+		registry: registry,
+	}
+}
+
+func (h *MetricsHandler) RegisterRouter(router *gin.Engine) {
+	handler := promhttp.HandlerFor(
+		prometheus.Gatherers{prometheus.DefaultGatherer, h.registry},
+		promhttp.HandlerOpts{},
+	)
+	router.GET("/metrics", gin.WrapH(handler))
+}
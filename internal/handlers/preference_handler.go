@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type PreferenceHandler struct {
+	preferenceService types.PreferenceService
+}
+
+func NewPreferenceHandler(preferenceService types.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{
+		preferenceService: preferenceService,
+	}
+}
+
+func (h *PreferenceHandler) RegisterRouter(router *gin.Engine) {
+	preferencesV1 := router.Group("/api/v1/preferences")
+	{
+		preferencesV1.GET("/:address", h.GetPreference)
+		preferencesV1.PUT("/:address", h.UpdatePreference)
+		preferencesV1.DELETE("/:address", h.DeletePreference)
+	}
+}
+
+func (h *PreferenceHandler) GetPreference(c *gin.Context) {
+	preference, err := h.preferenceService.GetPreference(c.Request.Context(), c.Param("address"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+func (h *PreferenceHandler) UpdatePreference(c *gin.Context) {
+	var params models.UpdatePreferenceRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preference, err := h.preferenceService.UpdatePreference(c.Request.Context(), c.Param("address"), params.Rules)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+func (h *PreferenceHandler) DeletePreference(c *gin.Context) {
+	if err := h.preferenceService.DeletePreference(c.Request.Context(), c.Param("address")); err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
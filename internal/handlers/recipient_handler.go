@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type RecipientHandler struct {
+	recipientService types.RecipientService
+}
+
+func NewRecipientHandler(recipientService types.RecipientService) *RecipientHandler {
+	return &RecipientHandler{
+		recipientService: recipientService,
+	}
+}
+
+func (h *RecipientHandler) RegisterRouter(router *gin.Engine) {
+	recipientsV1 := router.Group("/api/v1/recipients")
+	{
+		recipientsV1.POST("/", h.CreateRecipient)
+		recipientsV1.GET("/", h.ListRecipients)
+		recipientsV1.GET("/:user_id", h.GetRecipient)
+		recipientsV1.PUT("/:user_id", h.UpdateRecipient)
+		recipientsV1.DELETE("/:user_id", h.DeleteRecipient)
+	}
+}
+
+func (h *RecipientHandler) CreateRecipient(c *gin.Context) {
+	var params models.RecipientRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipient, err := h.recipientService.CreateRecipient(c.Request.Context(), recipientFromRequest(&params))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, recipient)
+}
+
+func (h *RecipientHandler) ListRecipients(c *gin.Context) {
+	var params models.ListRecipientsRequest
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipients, err := h.recipientService.ListRecipients(c.Request.Context(), params.After, params.Limit)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := models.RecipientListResponse{Recipients: recipients}
+	if len(recipients) > 0 {
+		response.NextCursor = recipients[len(recipients)-1].UserID
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RecipientHandler) GetRecipient(c *gin.Context) {
+	recipient, err := h.recipientService.GetRecipient(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recipient)
+}
+
+func (h *RecipientHandler) UpdateRecipient(c *gin.Context) {
+	var params models.RecipientRequest
+	params.UserID = c.Param("user_id")
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if params.UserID != c.Param("user_id") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id cannot be changed"})
+		return
+	}
+
+	recipient, err := h.recipientService.UpdateRecipient(c.Request.Context(), recipientFromRequest(&params))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recipient)
+}
+
+func (h *RecipientHandler) DeleteRecipient(c *gin.Context) {
+	if err := h.recipientService.DeleteRecipient(c.Request.Context(), c.Param("user_id")); err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func recipientFromRequest(params *models.RecipientRequest) *models.Recipient {
+	return &models.Recipient{
+		UserID:      params.UserID,
+		Emails:      params.Emails,
+		DisplayName: params.DisplayName,
+		Locale:      params.Locale,
+		Timezone:    params.Timezone,
+	}
+}
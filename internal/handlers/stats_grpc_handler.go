@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	pb "github.com/aarondever/notiflow/proto/stats"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type StatsGRPCHandler struct {
+	statsService types.StatsService
+	pb.UnimplementedStatsServiceServer
+}
+
+func NewStatsGRPCHandler(statsService types.StatsService) *StatsGRPCHandler {
+	return &StatsGRPCHandler{
+		statsService: statsService,
+	}
+}
+
+func (h *StatsGRPCHandler) GetEmailStats(ctx context.Context, request *pb.EmailStatsRequest) (*pb.EmailStatsResponse, error) {
+	stats, err := h.statsService.EmailStats(ctx, &models.StatsRequest{
+		GroupBy:  models.StatsGroupBy(request.GroupBy),
+		Since:    timeFromProto(request.Since),
+		Until:    timeFromProto(request.Until),
+		Category: request.Category,
+		Server:   request.Server,
+		Sender:   request.Sender,
+		Domain:   request.Domain,
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	groups := make([]*pb.StatsGroup, len(stats.Groups))
+	for i, group := range stats.Groups {
+		groups[i] = statsGroupToProto(group)
+	}
+
+	return &pb.EmailStatsResponse{
+		GroupBy: string(stats.GroupBy),
+		Since:   timestamppb.New(stats.Since),
+		Until:   timestamppb.New(stats.Until),
+		Totals:  statsGroupToProto(stats.Totals),
+		Groups:  groups,
+	}, nil
+}
+
+// timeFromProto converts an optional timestamp, unset is the zero time
+func timeFromProto(timestamp *timestamppb.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+
+	return timestamp.AsTime()
+}
+
+func statsGroupToProto(group *models.StatsGroup) *pb.StatsGroup {
+	return &pb.StatsGroup{
+		Key:          group.Key,
+		Pending:      int32(group.Pending),
+		Sent:         int32(group.Sent),
+		Failed:       int32(group.Failed),
+		Bounced:      int32(group.Bounced),
+		Suppressed:   int32(group.Suppressed),
+		Opened:       int32(group.Opened),
+		Clicked:      int32(group.Clicked),
+		OpenTracked:  int32(group.OpenTracked),
+		ClickTracked: int32(group.ClickTracked),
+		DeliveryRate: group.DeliveryRate,
+		BounceRate:   group.BounceRate,
+		OpenRate:     group.OpenRate,
+		ClickRate:    group.ClickRate,
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	statsService types.StatsService
+}
+
+func NewStatsHandler(statsService types.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+func (h *StatsHandler) RegisterRouter(router *gin.Engine) {
+	router.GET("/api/v1/stats", h.EmailStats)
+}
+
+func (h *StatsHandler) EmailStats(c *gin.Context) {
+	var params models.StatsRequest
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.statsService.EmailStats(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+// Upper bound on the size of an uploaded suppression CSV
+const maxSuppressionImportSize = 32 << 20 // 32MB
+
+type SuppressionHandler struct {
+	suppressionService types.SuppressionService
+}
+
+func NewSuppressionHandler(suppressionService types.SuppressionService) *SuppressionHandler {
+	return &SuppressionHandler{
+		suppressionService: suppressionService,
+	}
+}
+
+func (h *SuppressionHandler) RegisterRouter(router *gin.Engine) {
+	suppressionsV1 := router.Group("/api/v1/suppressions")
+	{
+		suppressionsV1.GET("/", h.ListSuppressions)
+		suppressionsV1.POST("/", h.AddSuppression)
+		suppressionsV1.POST("/import", h.ImportSuppressions)
+		suppressionsV1.GET("/:address", h.GetSuppression)
+		suppressionsV1.DELETE("/:address", h.RemoveSuppression)
+	}
+}
+
+func (h *SuppressionHandler) ListSuppressions(c *gin.Context) {
+	var params models.ListSuppressionsRequest
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suppressions, err := h.suppressionService.ListSuppressions(c.Request.Context(), params.Reason, params.After, params.Limit)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := models.SuppressionListResponse{Suppressions: suppressions}
+	if len(suppressions) > 0 {
+		response.NextCursor = suppressions[len(suppressions)-1].Address
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *SuppressionHandler) AddSuppression(c *gin.Context) {
+	var params models.CreateSuppressionRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suppression, err := h.suppressionService.AddSuppression(c.Request.Context(), &models.Suppression{
+		Address:   params.Address,
+		Reason:    params.Reason,
+		Source:    models.SuppressionSourceAPI,
+		Detail:    params.Detail,
+		ExpiresAt: params.ExpiresAt,
+	})
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, suppression)
+}
+
+// ImportSuppressions accepts a CSV either as the raw request body or as a multipart "file" field
+func (h *SuppressionHandler) ImportSuppressions(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSuppressionImportSize)
+
+	// Only a multipart form is parsed, any other body is the CSV itself
+	reader := c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		opened, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer opened.Close()
+
+		reader = opened
+	}
+
+	result, err := h.suppressionService.ImportCSV(c.Request.Context(), reader)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SuppressionHandler) GetSuppression(c *gin.Context) {
+	suppression, err := h.suppressionService.GetSuppression(c.Request.Context(), c.Param("address"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suppression)
+}
+
+func (h *SuppressionHandler) RemoveSuppression(c *gin.Context) {
+	if err := h.suppressionService.RemoveSuppression(c.Request.Context(), c.Param("address")); err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	pb "github.com/aarondever/notiflow/proto/telegram"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TelegramGRPCHandler struct {
+	telegramService types.TelegramService
+	pb.UnimplementedTelegramServiceServer
+}
+
+func NewTelegramGRPCHandler(telegramService types.TelegramService) *TelegramGRPCHandler {
+	return &TelegramGRPCHandler{
+		telegramService: telegramService,
+	}
+}
+
+func (h *TelegramGRPCHandler) SendMessage(ctx context.Context, request *pb.SendMessageRequest) (*pb.SendMessageResponse, error) {
+	// Convert proto request to internal model
+	var document *models.Attachment
+	if request.Document != nil {
+		document = &models.Attachment{
+			Filename:    request.Document.Filename,
+			Content:     request.Document.Content,
+			ContentType: request.Document.ContentType,
+		}
+	}
+
+	message, err := h.telegramService.SendMessage(ctx, &models.TelegramMessage{
+		Bot:       request.Bot,
+		ChatIDs:   request.ChatIds,
+		Text:      request.Text,
+		ParseMode: models.TelegramParseMode(request.ParseMode),
+		Document:  document,
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.SendMessageResponse{
+		Id:        message.ID.Hex(),
+		Status:    string(models.StatusPending),
+		Message:   "Telegram message queued for sending",
+		CreatedAt: timestamppb.New(message.CreatedAt),
+	}, nil
+}
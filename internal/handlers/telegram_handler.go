@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type TelegramHandler struct {
+	telegramService types.TelegramService
+}
+
+func NewTelegramHandler(telegramService types.TelegramService) *TelegramHandler {
+	return &TelegramHandler{
+		telegramService: telegramService,
+	}
+}
+
+func (h *TelegramHandler) RegisterRouter(router *gin.Engine) {
+	telegramV1 := router.Group("/api/v1/telegram")
+	{
+		telegramV1.POST("/", h.SendMessage)
+		telegramV1.GET("/:id", h.GetMessage)
+		telegramV1.POST("/:id/retry", h.RetryMessage)
+	}
+}
+
+func (h *TelegramHandler) SendMessage(c *gin.Context) {
+	var params models.SendTelegramRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	message, err := h.telegramService.SendMessage(c.Request.Context(), &models.TelegramMessage{
+		Bot:       params.Bot,
+		ChatIDs:   params.ChatIDs,
+		Text:      params.Text,
+		ParseMode: params.ParseMode,
+		Document:  params.Document,
+	})
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.TelegramResponse{
+		ID:        message.ID.Hex(),
+		Status:    models.StatusPending,
+		Message:   "Telegram message queued for sending",
+		CreatedAt: message.CreatedAt,
+	})
+}
+
+func (h *TelegramHandler) GetMessage(c *gin.Context) {
+	message, err := h.telegramService.GetMessage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+func (h *TelegramHandler) RetryMessage(c *gin.Context) {
+	message, err := h.telegramService.RetryMessage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.TelegramResponse{
+		ID:        message.ID.Hex(),
+		Status:    models.StatusPending,
+		Message:   "Telegram message queued for retry",
+		CreatedAt: message.CreatedAt,
+	})
+}
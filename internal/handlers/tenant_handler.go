@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type TenantHandler struct {
+	tenantService types.TenantService
+}
+
+func NewTenantHandler(tenantService types.TenantService) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+	}
+}
+
+func (h *TenantHandler) RegisterRouter(router *gin.Engine) {
+	tenantsV1 := router.Group("/api/v1/tenants")
+	{
+		tenantsV1.POST("/", h.CreateTenant)
+		tenantsV1.GET("/", h.ListTenants)
+		tenantsV1.GET("/:id", h.GetTenant)
+		tenantsV1.PUT("/:id", h.UpdateTenant)
+	}
+}
+
+func (h *TenantHandler) CreateTenant(c *gin.Context) {
+	var params models.CreateTenantRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenant, err := h.tenantService.CreateTenant(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tenant)
+}
+
+func (h *TenantHandler) ListTenants(c *gin.Context) {
+	tenants, err := h.tenantService.ListTenants(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TenantListResponse{Tenants: tenants})
+}
+
+func (h *TenantHandler) GetTenant(c *gin.Context) {
+	tenant, err := h.tenantService.GetTenant(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
+
+// UpdateTenant replaces the tenant's settings, disabling it locks out its API keys and tokens
+func (h *TenantHandler) UpdateTenant(c *gin.Context) {
+	var params models.UpdateTenantRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenant, err := h.tenantService.UpdateTenant(c.Request.Context(), c.Param("id"), &params)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
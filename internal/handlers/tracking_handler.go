@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+// Transparent 1x1 GIF served for open tracking
+var trackingPixel, _ = base64.StdEncoding.DecodeString("R0lGODlhAQABAIAAAP///wAAACH5BAEAAAAALAAAAAABAAEAAAICRAEAOw==")
+
+type TrackingHandler struct {
+	trackingService types.TrackingService
+}
+
+func NewTrackingHandler(trackingService types.TrackingService) *TrackingHandler {
+	return &TrackingHandler{
+		trackingService: trackingService,
+	}
+}
+
+func (h *TrackingHandler) RegisterRouter(router *gin.Engine) {
+	router.GET("/t/open/:token", h.Open)
+	router.GET("/t/click/:token", h.Click)
+
+	router.GET("/api/v1/email/:id/clicks", h.EmailClicks)
+	router.GET("/api/v1/clicks", h.CategoryClicks)
+}
+
+// Open records the open and serves the pixel. The image is served even when the token is invalid
+// or recording fails, so the email never shows a broken image.
+func (h *TrackingHandler) Open(c *gin.Context) {
+	_ = h.trackingService.RecordOpen(c.Request.Context(), c.Param("token"), c.Request.UserAgent(), c.ClientIP())
+
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	c.Data(http.StatusOK, "image/gif", trackingPixel)
+}
+
+// Click records the click and redirects to the link target. Only targets from a validly signed token
+// are redirected to, so the endpoint can't be used as an open redirect.
+func (h *TrackingHandler) Click(c *gin.Context) {
+	target, err := h.trackingService.RecordClick(c.Request.Context(), c.Param("token"), c.Request.UserAgent(), c.ClientIP())
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	if target == "" {
+		c.String(httpStatus(err), "This link is invalid.")
+		return
+	}
+
+	c.Redirect(http.StatusFound, target)
+}
+
+func (h *TrackingHandler) EmailClicks(c *gin.Context) {
+	stats, err := h.trackingService.EmailClicks(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *TrackingHandler) CategoryClicks(c *gin.Context) {
+	var params models.CategoryClicksRequest
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.trackingService.CategoryClicks(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Unsubscribe</title></head>
+<body style="font-family: sans-serif; max-width: 32rem; margin: 4rem auto; text-align: center">
+{{if .Done}}
+<p>{{.Address}} has been unsubscribed from {{.Category}} emails.</p>
+{{else}}
+<p>Unsubscribe {{.Address}} from {{.Category}} emails?</p>
+<form method="post"><button type="submit">Unsubscribe</button></form>
+{{end}}
+</body>
+</html>`))
+
+type UnsubscribeHandler struct {
+	unsubscribeService types.UnsubscribeService
+}
+
+func NewUnsubscribeHandler(unsubscribeService types.UnsubscribeService) *UnsubscribeHandler {
+	return &UnsubscribeHandler{
+		unsubscribeService: unsubscribeService,
+	}
+}
+
+func (h *UnsubscribeHandler) RegisterRouter(router *gin.Engine) {
+	router.GET("/u/:token", h.ConfirmUnsubscribe)
+	router.POST("/u/:token", h.Unsubscribe)
+}
+
+// ConfirmUnsubscribe renders a confirmation form. GET never unsubscribes by itself because
+// mail scanners and link previews fetch every URL in a message.
+func (h *UnsubscribeHandler) ConfirmUnsubscribe(c *gin.Context) {
+	unsubscribe, err := h.unsubscribeService.Verify(c.Param("token"))
+	if err != nil {
+		c.String(httpStatus(err), "This unsubscribe link is invalid.")
+		return
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = unsubscribePage.Execute(c.Writer, gin.H{
+		"Done":     false,
+		"Address":  unsubscribe.Address,
+		"Category": unsubscribe.Category,
+	})
+}
+
+// Unsubscribe handles both RFC 8058 one-click POSTs from mailbox providers and the confirmation form
+func (h *UnsubscribeHandler) Unsubscribe(c *gin.Context) {
+	unsubscribe, err := h.unsubscribeService.Unsubscribe(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if status := httpStatus(err); status != http.StatusBadRequest {
+			c.String(status, "We couldn't process your request, please try again later.")
+			return
+		}
+
+		c.String(http.StatusBadRequest, "This unsubscribe link is invalid.")
+		return
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = unsubscribePage.Execute(c.Writer, gin.H{
+		"Done":     true,
+		"Address":  unsubscribe.Address,
+		"Category": unsubscribe.Category,
+	})
+}
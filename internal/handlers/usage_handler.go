@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type UsageHandler struct {
+	usageService types.UsageService
+}
+
+func NewUsageHandler(usageService types.UsageService) *UsageHandler {
+	return &UsageHandler{
+		usageService: usageService,
+	}
+}
+
+func (h *UsageHandler) RegisterRouter(router *gin.Engine) {
+	router.GET("/api/v1/usage", h.GetUsage)
+}
+
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	var params models.UsageRequest
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	usage, err := h.usageService.GetUsage(c.Request.Context(), &params)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
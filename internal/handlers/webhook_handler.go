@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService types.WebhookService
+}
+
+func NewWebhookHandler(webhookService types.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) RegisterRouter(router *gin.Engine) {
+	webhooksV1 := router.Group("/api/v1/webhooks")
+	{
+		webhooksV1.GET("/", h.ListEndpoints)
+		webhooksV1.POST("/", h.CreateEndpoint)
+		webhooksV1.GET("/:id", h.GetEndpoint)
+		webhooksV1.PUT("/:id", h.UpdateEndpoint)
+		webhooksV1.DELETE("/:id", h.DeleteEndpoint)
+		webhooksV1.GET("/:id/deliveries", h.ListDeliveries)
+		webhooksV1.GET("/:id/deliveries/:delivery_id", h.GetDelivery)
+		webhooksV1.POST("/:id/deliveries/:delivery_id/redeliver", h.Redeliver)
+	}
+}
+
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	endpoints, err := h.webhookService.ListEndpoints(c.Request.Context())
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookEndpointListResponse{Endpoints: endpoints})
+}
+
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	var params models.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Request.Context(), endpointFromRequest(&params))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+func (h *WebhookHandler) GetEndpoint(c *gin.Context) {
+	endpoint, err := h.webhookService.GetEndpoint(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *WebhookHandler) UpdateEndpoint(c *gin.Context) {
+	var params models.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(c.Request.Context(), c.Param("id"), endpointFromRequest(&params))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	if err := h.webhookService.DeleteEndpoint(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	var params models.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("id"), params.Status, params.Before, params.Limit)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := models.WebhookDeliveryListResponse{Deliveries: deliveries}
+	if len(deliveries) > 0 {
+		response.NextCursor = deliveries[len(deliveries)-1].ID.Hex()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func endpointFromRequest(params *models.WebhookEndpointRequest) *models.WebhookEndpoint {
+	endpoint := &models.WebhookEndpoint{
+		URL:         params.URL,
+		Description: params.Description,
+		Events:      params.Events,
+		Enabled:     true,
+	}
+	if params.Enabled != nil {
+		endpoint.Enabled = *params.Enabled
+	}
+
+	return endpoint
+}
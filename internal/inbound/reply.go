@@ -0,0 +1,160 @@
+package inbound
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Replies are sent to reply+<email id>@<reply domain> when reply routing is enabled
+const replyPrefix = "reply+"
+
+// Limit on the body text forwarded to the reply webhook
+const maxBodyLength = 64 * 1024
+
+// Reply is a human reply to an email notiflow sent
+type Reply struct {
+	MessageID  string
+	InReplyTo  string
+	References []string
+	From       string
+	To         []string
+	Subject    string
+	Date       time.Time
+	// Set for out-of-office and other automatic responses (RFC 3834)
+	AutoSubmitted bool
+	Text          string
+	HTML          string
+}
+
+// ReplyAddress returns the Reply-To address that routes replies to an email back to notiflow
+func ReplyAddress(emailID bson.ObjectID, domain string) string {
+	return fmt.Sprintf("%s%s@%s", replyPrefix, emailID.Hex(), domain)
+}
+
+// DecodeReplyAddress extracts the email ID from an address made by ReplyAddress
+func DecodeReplyAddress(address string) (bson.ObjectID, bool) {
+	local, _, ok := strings.Cut(strings.ToLower(address), "@")
+	if !ok || !strings.HasPrefix(local, replyPrefix) {
+		return bson.NilObjectID, false
+	}
+
+	emailID, err := bson.ObjectIDFromHex(strings.TrimPrefix(local, replyPrefix))
+	if err != nil {
+		return bson.NilObjectID, false
+	}
+
+	return emailID, true
+}
+
+// ParseReply reads the headers used for threading and the first text and HTML bodies
+func ParseReply(r io.Reader) (*Reply, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("inbound: failed to read message: %w", err)
+	}
+
+	reply := &Reply{
+		MessageID:  strings.TrimSpace(message.Header.Get("Message-Id")),
+		InReplyTo:  strings.TrimSpace(message.Header.Get("In-Reply-To")),
+		References: strings.Fields(message.Header.Get("References")),
+		Subject:    decodeHeader(message.Header.Get("Subject")),
+	}
+
+	if from, err := mail.ParseAddress(message.Header.Get("From")); err == nil {
+		reply.From = from.Address
+	}
+	if to, err := message.Header.AddressList("To"); err == nil {
+		for _, address := range to {
+			reply.To = append(reply.To, address.Address)
+		}
+	}
+	if autoSubmitted := strings.ToLower(strings.TrimSpace(message.Header.Get("Auto-Submitted"))); autoSubmitted != "" && autoSubmitted != "no" {
+		reply.AutoSubmitted = true
+	}
+	if date, err := message.Header.Date(); err == nil {
+		reply.Date = date
+	}
+
+	if err = reply.readBody(message.Header.Get("Content-Type"), message.Header.Get("Content-Transfer-Encoding"), message.Body); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// readBody walks nested multiparts, keeping the first text/plain and text/html parts
+func (reply *Reply) readBody(contentType, encoding string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			// NextRawPart keeps Content-Transfer-Encoding, which decode handles
+			part, err := reader.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("inbound: failed to read message part: %w", err)
+			}
+
+			if err = reply.readBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part); err != nil {
+				return err
+			}
+		}
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return nil
+	}
+	if (mediaType == "text/plain" && reply.Text != "") || (mediaType == "text/html" && reply.HTML != "") {
+		return nil
+	}
+
+	content, err := io.ReadAll(io.LimitReader(decode(encoding, body), maxBodyLength))
+	if err != nil {
+		return fmt.Errorf("inbound: failed to read message body: %w", err)
+	}
+
+	if mediaType == "text/plain" {
+		reply.Text = string(content)
+	} else {
+		reply.HTML = string(content)
+	}
+
+	return nil
+}
+
+func decode(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	default:
+		return body
+	}
+}
+
+// decodeHeader decodes RFC 2047 encoded words, e.g. in non-ASCII subjects
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+
+	return decoded
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "gRPC calls by full method and status code.",
+	}, []string{"method", "code"})
+
+	grpcRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "gRPC call latency by full method, streams are measured until they end.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observeGRPC(info.FullMethod, start, err)
+
+	return resp, err
+}
+
+func StreamServerInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	observeGRPC(info.FullMethod, start, err)
+
+	return err
+}
+
+func observeGRPC(method string, start time.Time, err error) {
+	grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	grpcRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
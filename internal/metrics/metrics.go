@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "notiflow"
+
+var (
+	smtpSendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "smtp_send_duration_seconds",
+		Help:      "Time to deliver one message to an SMTP server, from connecting to the final reply.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"server", "result"})
+
+	smtpSends = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "smtp_sends_total",
+		Help:      "Messages handed to SMTP servers, by server and result (success or failure).",
+	}, []string{"server", "result"})
+
+	smtpRefusedRecipients = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "smtp_refused_recipients_total",
+		Help:      "Recipients refused by SMTP servers while the rest of the message was accepted or attempted.",
+	}, []string{"server"})
+
+	retries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "retries_total",
+		Help:      "Retries scheduled or run, by kind: email_fallback, escalation_step or webhook.",
+	}, []string{"kind"})
+
+	sendsThrottled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sends_throttled_total",
+		Help:      "Send requests refused by a limit: key_rate, tenant_rate or monthly_quota.",
+	}, []string{"limit"})
+)
+
+// Retry kinds
+const (
+	RetryEmailFallback  = "email_fallback"  // A failed email's fallback plan was scheduled
+	RetryEscalationStep = "escalation_step" // A fallback step ran
+	RetryWebhook        = "webhook"         // A webhook delivery will be attempted again
+)
+
+// ObserveSMTPSend records one message sent through server. refused is the number of recipients the
+// server refused individually.
+func ObserveSMTPSend(server string, duration time.Duration, refused int, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	smtpSendDuration.WithLabelValues(server, result).Observe(duration.Seconds())
+	smtpSends.WithLabelValues(server, result).Inc()
+	if refused > 0 {
+		smtpRefusedRecipients.WithLabelValues(server).Add(float64(refused))
+	}
+}
+
+func CountRetry(kind string) {
+	retries.WithLabelValues(kind).Inc()
+}
+
+// Send limits
+const (
+	LimitKeyRate      = "key_rate"      // The caller's API key or token subject sent too fast
+	LimitTenantRate   = "tenant_rate"   // The caller's tenant sent too fast
+	LimitMonthlyQuota = "monthly_quota" // The caller's tenant used up its emails for the month
+)
+
+func CountThrottledSend(limit string) {
+	sendsThrottled.WithLabelValues(limit).Inc()
+}
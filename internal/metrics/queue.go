@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// How long a scrape waits for the queue counts before reporting nothing
+const queueScrapeTimeout = 5 * time.Second
+
+var queueDepthDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "emails"),
+	"Stored emails by status, pending is the send queue.",
+	[]string{"status"}, nil,
+)
+
+// QueueCollector reports the number of emails per status, counted when scraped
+type QueueCollector struct {
+	count func(ctx context.Context) (map[string]int64, error)
+}
+
+func NewQueueCollector(count func(ctx context.Context) (map[string]int64, error)) *QueueCollector {
+	return &QueueCollector{count: count}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueScrapeTimeout)
+	defer cancel()
+
+	counts, err := c.count(ctx)
+	if err != nil {
+		slog.Warn("Failed to count emails for metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(queueDepthDesc, err)
+		return
+	}
+
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(count), status)
+	}
+}
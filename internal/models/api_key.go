@@ -0,0 +1,85 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type APIKeyScope string
+
+const (
+	ScopeSend  APIKeyScope = "send"  // Send emails, Telegram messages and inbox items
+	ScopeRead  APIKeyScope = "read"  // Read emails, events, stats and inboxes
+	ScopeAdmin APIKeyScope = "admin" // Everything, including API keys, webhooks, recipients and suppressions
+)
+
+// APIKey authenticates HTTP and gRPC callers. Only a hash of the key is stored, the key itself is
+// returned once when it is created.
+type APIKey struct {
+	ID         bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name       string        `json:"name" bson:"name"`
+	Prefix     string        `json:"prefix" bson:"prefix"` // First characters of the key, to tell keys apart
+	Hash       string        `json:"-" bson:"hash"`        // Hex SHA-256 of the key
+	Scopes     []APIKeyScope `json:"scopes" bson:"scopes"`
+	Tenant     string        `json:"tenant,omitempty" bson:"tenant,omitempty"` // Tenant the key acts for, empty for platform keys
+	CreatedAt  time.Time     `json:"created_at" bson:"created_at"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"` // Updated at most once a minute
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+type PrincipalKind string
+
+const (
+	PrincipalAPIKey      PrincipalKind = "api_key"
+	PrincipalBootstrap   PrincipalKind = "bootstrap"   // The configured bootstrap key, which isn't stored
+	PrincipalJWT         PrincipalKind = "jwt"         // A bearer JWT from the platform's issuer
+	PrincipalCertificate PrincipalKind = "certificate" // A verified TLS client certificate of a configured identity
+)
+
+// Principal is the identity a request was authenticated as
+type Principal struct {
+	Kind   PrincipalKind `json:"kind" bson:"kind"`
+	ID     string        `json:"id" bson:"id"` // API key ID, the JWT subject, or the certificate identity's subject
+	Name   string        `json:"name,omitempty" bson:"name,omitempty"`
+	Tenant string        `json:"tenant,omitempty" bson:"tenant,omitempty"` // Tenant it acts for, empty for platform principals
+	Scopes []APIKeyScope `json:"-" bson:"-"`
+}
+
+// TenantID returns the tenant whose data the principal works with, platform principals work with
+// the default tenant's
+func (principal *Principal) TenantID() string {
+	if principal == nil || principal.Tenant == "" {
+		return DefaultTenant
+	}
+
+	return principal.Tenant
+}
+
+// IsPlatform reports whether the principal isn't bound to a tenant and may manage tenants and
+// the resources shared by all of them. A nil principal means authentication is disabled.
+func (principal *Principal) IsPlatform() bool {
+	return principal == nil || principal.Tenant == ""
+}
+
+// HasScope reports whether the principal was granted scope, admin grants every scope
+func (principal *Principal) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(principal.Scopes, scope) || slices.Contains(principal.Scopes, ScopeAdmin)
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string        `json:"name" binding:"required,max=255"`
+	Scopes []APIKeyScope `json:"scopes" binding:"required,min=1,dive,oneof=send read admin"`
+	Tenant string        `json:"tenant,omitempty"` // Only platform principals may create keys for another tenant
+}
+
+// CreateAPIKeyResponse is the only response that includes the key
+type CreateAPIKeyResponse struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyListResponse struct {
+	APIKeys []*APIKey `json:"api_keys"`
+}
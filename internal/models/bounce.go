@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Bounce is a delivery status notification reported for one recipient of an email
+type Bounce struct {
+	Address        string    `json:"address" bson:"address"`
+	Action         string    `json:"action" bson:"action"`                                       // failed or delayed
+	Status         string    `json:"status" bson:"status"`                                       // Enhanced status code, e.g. 5.1.1
+	DiagnosticCode string    `json:"diagnostic_code,omitempty" bson:"diagnostic_code,omitempty"` // Remote server's reply
+	Permanent      bool      `json:"permanent" bson:"permanent"`
+	ReportedAt     time.Time `json:"reported_at" bson:"reported_at"`
+}
+
+// Complaint is a feedback loop report of a recipient marking an email as spam
+type Complaint struct {
+	Address      string    `json:"address,omitempty" bson:"address,omitempty"` // Empty when the provider redacts it
+	FeedbackType string    `json:"feedback_type" bson:"feedback_type"`
+	UserAgent    string    `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	ReportedAt   time.Time `json:"reported_at" bson:"reported_at"`
+}
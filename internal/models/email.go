@@ -9,24 +9,119 @@ import (
 type EmailStatus string
 
 const (
-	StatusPending EmailStatus = "pending"
-	StatusSent    EmailStatus = "sent"
-	StatusFailed  EmailStatus = "failed"
+	StatusPending    EmailStatus = "pending"
+	StatusSent       EmailStatus = "sent"
+	StatusFailed     EmailStatus = "failed"
+	StatusSuppressed EmailStatus = "suppressed" // Not sent because no recipient accepts it
+	StatusBounced    EmailStatus = "bounced"    // Sent, but every recipient bounced permanently
+	StatusPartial    EmailStatus = "partial"    // Delivered to some recipients, refused or bounced for others
+)
+
+type RecipientStatus string
+
+const (
+	RecipientPending    RecipientStatus = "pending"
+	RecipientSent       RecipientStatus = "sent"       // Accepted by the SMTP server
+	RecipientFailed     RecipientStatus = "failed"     // Refused by the SMTP server
+	RecipientSuppressed RecipientStatus = "suppressed" // Removed before sending
+	RecipientBounced    RecipientStatus = "bounced"    // Accepted, then bounced permanently
+)
+
+type RecipientKind string
+
+const (
+	RecipientTo  RecipientKind = "to"
+	RecipientCC  RecipientKind = "cc"
+	RecipientBCC RecipientKind = "bcc"
+	// An alternate address of a fallback plan's resend step, it doesn't count toward the email status
+	RecipientFallback RecipientKind = "fallback"
 )
 
 type Email struct {
-	ID          bson.ObjectID `json:"id" bson:"_id,omitempty"`
-	To          []string      `json:"to" bson:"to"`
-	CC          []string      `json:"cc,omitempty" bson:"cc,omitempty"`
-	BCC         []string      `json:"bcc,omitempty" bson:"bcc,omitempty"`
-	Subject     string        `json:"subject" bson:"subject"`
-	Body        string        `json:"body" bson:"body"`
-	IsHTML      bool          `json:"is_html" bson:"is_html"`
-	Status      EmailStatus   `json:"status" bson:"status"`
-	ErrorMsg    string        `json:"error_message,omitempty" bson:"error_message,omitempty"`
-	CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
-	SentAt      time.Time     `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
-	Attachments []Attachment  `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	ID           bson.ObjectID         `json:"id" bson:"_id,omitempty"`
+	Tenant       string                `json:"tenant" bson:"tenant"`
+	From         string                `json:"from,omitempty" bson:"from,omitempty"`       // Sender identity, the SMTP server's from address when empty
+	UserID       string                `json:"user_id,omitempty" bson:"user_id,omitempty"` // Recipient profile resolved into To at dispatch time
+	To           []string              `json:"to" bson:"to,omitempty"`
+	CC           []string              `json:"cc,omitempty" bson:"cc,omitempty"`
+	BCC          []string              `json:"bcc,omitempty" bson:"bcc,omitempty"`
+	Subject      string                `json:"subject" bson:"subject"`
+	Body         string                `json:"body" bson:"body"`
+	IsHTML       bool                  `json:"is_html" bson:"is_html"`
+	Status       EmailStatus           `json:"status" bson:"status"`
+	ErrorMsg     string                `json:"error_message,omitempty" bson:"error_message,omitempty"`
+	CreatedAt    time.Time             `json:"created_at" bson:"created_at"`
+	SentAt       time.Time             `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+	Attachments  []Attachment          `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	Fallback     *FallbackPlan         `json:"fallback,omitempty" bson:"fallback,omitempty"`
+	Category     string                `json:"category,omitempty" bson:"category,omitempty"`
+	Suppressed   []SuppressedRecipient `json:"suppressed_recipients,omitempty" bson:"suppressed_recipients,omitempty"`
+	Bounces      []Bounce              `json:"bounces,omitempty" bson:"bounces,omitempty"`
+	Complaints   []Complaint           `json:"complaints,omitempty" bson:"complaints,omitempty"`
+	Recipients   []EmailRecipient      `json:"recipients,omitempty" bson:"recipients,omitempty"` // Delivery state per address, set at dispatch
+	UpdatedAt    time.Time             `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	TrackOpens   bool                  `json:"track_opens,omitempty" bson:"track_opens,omitempty"`   // HTML emails get a tracking pixel per recipient
+	TrackClicks  bool                  `json:"track_clicks,omitempty" bson:"track_clicks,omitempty"` // HTML links are rewritten to the click redirect
+	TraceContext map[string]string     `json:"-" bson:"trace_context,omitempty"`                     // W3C trace context of the request, delivery continues its trace
+	CreatedBy    *Principal            `json:"created_by,omitempty" bson:"created_by,omitempty"`     // API key the email was sent with
+}
+
+// EmailRecipient is the delivery state of one address of an email
+type EmailRecipient struct {
+	Address        string          `json:"address" bson:"address"`
+	Kind           RecipientKind   `json:"kind" bson:"kind"`
+	Status         RecipientStatus `json:"status" bson:"status"`
+	SMTPCode       int             `json:"smtp_code,omitempty" bson:"smtp_code,omitempty"`
+	SMTPResponse   string          `json:"smtp_response,omitempty" bson:"smtp_response,omitempty"`
+	Server         string          `json:"server,omitempty" bson:"server,omitempty"` // SMTP server the address was sent through
+	Sender         string          `json:"sender,omitempty" bson:"sender,omitempty"` // From address it was sent with
+	Reason         string          `json:"reason,omitempty" bson:"reason,omitempty"` // Why a suppressed recipient was removed
+	SentAt         time.Time       `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+	UpdatedAt      time.Time       `json:"updated_at" bson:"updated_at"`
+	Bounce         *Bounce         `json:"bounce,omitempty" bson:"bounce,omitempty"`         // Latest bounce report for the address
+	OpenCount      int             `json:"open_count,omitempty" bson:"open_count,omitempty"` // Opens by the recipient, prefetches excluded
+	FirstOpenedAt  time.Time       `json:"first_opened_at,omitempty" bson:"first_opened_at,omitempty"`
+	LastOpenedAt   time.Time       `json:"last_opened_at,omitempty" bson:"last_opened_at,omitempty"`
+	ClickCount     int             `json:"click_count,omitempty" bson:"click_count,omitempty"` // Tracked link clicks, prefetches excluded
+	FirstClickedAt time.Time       `json:"first_clicked_at,omitempty" bson:"first_clicked_at,omitempty"`
+	LastClickedAt  time.Time       `json:"last_clicked_at,omitempty" bson:"last_clicked_at,omitempty"`
+}
+
+// AggregateStatus derives the email status from its recipients: sent when every recipient that
+// wasn't suppressed was delivered, partial when only some were, and failed or bounced when none were
+func AggregateStatus(recipients []EmailRecipient) EmailStatus {
+	var pending, delivered, failed, bounced int
+	for _, recipient := range recipients {
+		if recipient.Kind == RecipientFallback {
+			continue
+		}
+
+		switch recipient.Status {
+		case RecipientPending:
+			pending++
+		case RecipientSent:
+			delivered++
+		case RecipientFailed:
+			failed++
+		case RecipientBounced:
+			bounced++
+		}
+	}
+
+	switch {
+	case pending > 0:
+		return StatusPending
+	case delivered > 0 && failed+bounced == 0:
+		return StatusSent
+	case delivered > 0:
+		return StatusPartial
+	case failed > 0:
+		return StatusFailed
+	case bounced > 0:
+		return StatusBounced
+	default:
+		return StatusSuppressed
+	}
 }
 
 type Attachment struct {
@@ -36,13 +131,62 @@ type Attachment struct {
 }
 
 type SendEmailRequest struct {
-	To          []string     `json:"to" bind:"required"`
-	CC          []string     `json:"cc,omitempty"`
-	BCC         []string     `json:"bcc,omitempty"`
-	Subject     string       `json:"subject" bind:"required"`
-	Body        string       `json:"body" bind:"required"`
-	IsHTML      bool         `json:"is_html"`
-	Attachments []Attachment `json:"attachments,omitempty"`
+	From        string               `json:"from,omitempty"`
+	UserID      string               `json:"user_id,omitempty"`
+	To          []string             `json:"to,omitempty"`
+	CC          []string             `json:"cc,omitempty"`
+	BCC         []string             `json:"bcc,omitempty"`
+	Subject     string               `json:"subject" bind:"required"`
+	Body        string               `json:"body" bind:"required"`
+	IsHTML      bool                 `json:"is_html"`
+	Attachments []Attachment         `json:"attachments,omitempty"`
+	Fallback    *FallbackPlanRequest `json:"fallback,omitempty"`
+	Category    string               `json:"category,omitempty" binding:"omitempty,max=64"`
+	TrackOpens  bool                 `json:"track_opens,omitempty"`  // Ignored for plain-text emails
+	TrackClicks bool                 `json:"track_clicks,omitempty"` // Ignored for plain-text emails
+}
+
+// ToEmail converts the request into an email, the service validates it and fills in the rest
+func (request *SendEmailRequest) ToEmail() *Email {
+	return &Email{
+		From:        request.From,
+		UserID:      request.UserID,
+		To:          request.To,
+		CC:          request.CC,
+		BCC:         request.BCC,
+		Subject:     request.Subject,
+		Body:        request.Body,
+		IsHTML:      request.IsHTML,
+		Attachments: request.Attachments,
+		Fallback:    request.Fallback.ToPlan(),
+		Category:    request.Category,
+		TrackOpens:  request.TrackOpens,
+		TrackClicks: request.TrackClicks,
+	}
+}
+
+// MaxEmailBatch is the most emails one batch send may queue
+const MaxEmailBatch = 5000
+
+// SendEmailBatchRequest queues many emails in one call. Every email must pass binding, the service's
+// checks then give each email its own result.
+type SendEmailBatchRequest struct {
+	Emails []SendEmailRequest `json:"emails" binding:"required,min=1,max=5000,dive"`
+}
+
+// SendEmailBatchResult is the outcome of one email of a batch, ID is set when it was queued and
+// Error when it wasn't
+type SendEmailBatchResult struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type SendEmailBatchResponse struct {
+	Queued    int                    `json:"queued"`
+	Failed    int                    `json:"failed"`
+	Results   []SendEmailBatchResult `json:"results"`
+	CreatedAt time.Time              `json:"created_at"`
 }
 
 type EmailResponse struct {
@@ -51,3 +195,12 @@ type EmailResponse struct {
 	Message   string      `json:"message"`
 	CreatedAt time.Time   `json:"created_at"`
 }
+
+// EmailWatchFilter selects the emails whose changes are streamed, empty fields match any email
+type EmailWatchFilter struct {
+	Tenant   string // Always set by the service
+	ID       bson.ObjectID
+	Statuses []EmailStatus
+	Category string
+	UserID   string
+}
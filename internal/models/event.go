@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type EmailEventType string
+
+const (
+	EventQueued         EmailEventType = "queued"          // Accepted by the API
+	EventSuppressed     EmailEventType = "suppressed"      // Recipient removed by the suppression list or preferences
+	EventRendering      EmailEventType = "rendering"       // Message being built for dispatch
+	EventDispatched     EmailEventType = "dispatched"      // Handed to an SMTP server
+	EventAccepted       EmailEventType = "accepted"        // SMTP server accepted the message
+	EventRefused        EmailEventType = "refused"         // SMTP server refused a recipient
+	EventFailed         EmailEventType = "failed"          // Message could not be sent
+	EventRetryScheduled EmailEventType = "retry_scheduled" // Fallback plan will retry the email
+	EventEscalated      EmailEventType = "escalated"       // Fallback step ran
+	EventBounced        EmailEventType = "bounced"         // Bounce report received
+	EventComplained     EmailEventType = "complained"      // Feedback loop report received
+	EventOpened         EmailEventType = "opened"
+	EventClicked        EmailEventType = "clicked"
+)
+
+// EmailEvent is an entry in an email's append-only delivery timeline
+type EmailEvent struct {
+	ID        bson.ObjectID  `json:"id" bson:"_id,omitempty"`
+	EmailID   bson.ObjectID  `json:"email_id" bson:"email_id"`
+	Type      EmailEventType `json:"type" bson:"type"`
+	Recipient string         `json:"recipient,omitempty" bson:"recipient,omitempty"`
+	Server    string         `json:"server,omitempty" bson:"server,omitempty"` // SMTP server the email was dispatched to
+	SMTPCode  int            `json:"smtp_code,omitempty" bson:"smtp_code,omitempty"`
+	QueueID   string         `json:"queue_id,omitempty" bson:"queue_id,omitempty"`
+	Detail    string         `json:"detail,omitempty" bson:"detail,omitempty"`
+	UserAgent string         `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	IPHash    string         `json:"ip_hash,omitempty" bson:"ip_hash,omitempty"`   // Salted hash of the opener's IP address
+	Prefetch  bool           `json:"prefetch,omitempty" bson:"prefetch,omitempty"` // Open or click by a privacy proxy or scanner, not a person
+	URL       string         `json:"url,omitempty" bson:"url,omitempty"`           // Link target of a click
+	Category  string         `json:"category,omitempty" bson:"category,omitempty"` // Email category, set on opens and clicks for reporting
+	Tenant    string         `json:"-" bson:"tenant,omitempty"`                    // Email tenant, set on opens and clicks for reporting
+	CreatedAt time.Time      `json:"created_at" bson:"created_at"`
+}
+
+type EmailEventsResponse struct {
+	Events []*EmailEvent `json:"events"`
+}
+
+// LinkClickStats aggregates the clicks on one link
+type LinkClickStats struct {
+	URL              string    `json:"url" bson:"url"`
+	Clicks           int       `json:"clicks" bson:"clicks"`
+	UniqueRecipients int       `json:"unique_recipients" bson:"unique_recipients"`
+	FirstClickedAt   time.Time `json:"first_clicked_at,omitempty" bson:"first_clicked_at,omitempty"`
+	LastClickedAt    time.Time `json:"last_clicked_at,omitempty" bson:"last_clicked_at,omitempty"`
+}
+
+type RecipientClickStats struct {
+	Address       string    `json:"address" bson:"address"`
+	Clicks        int       `json:"clicks" bson:"clicks"`
+	UniqueLinks   int       `json:"unique_links" bson:"unique_links"`
+	LastClickedAt time.Time `json:"last_clicked_at" bson:"last_clicked_at"`
+}
+
+type EmailClickStats struct {
+	EmailID    string                 `json:"email_id" bson:"-"`
+	Links      []*LinkClickStats      `json:"links" bson:"links"`
+	Recipients []*RecipientClickStats `json:"recipients" bson:"recipients"`
+}
+
+// CategoryClickStats aggregates the clicks on emails of one category, uncategorized emails have
+// an empty category
+type CategoryClickStats struct {
+	Category     string            `json:"category" bson:"category"`
+	Clicks       int               `json:"clicks" bson:"clicks"`
+	UniqueClicks int               `json:"unique_clicks" bson:"unique_clicks"` // Distinct email, recipient and link combinations
+	Links        []*LinkClickStats `json:"links" bson:"links"`                 // Most clicked links first
+}
+
+type CategoryClicksRequest struct {
+	Category string    `form:"category"`
+	Since    time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until    time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type CategoryClicksResponse struct {
+	Categories []*CategoryClickStats `json:"categories"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+type FallbackAction string
+
+const (
+	FallbackActionResend  FallbackAction = "resend"
+	FallbackActionWebhook FallbackAction = "webhook"
+)
+
+type FallbackState string
+
+const (
+	FallbackStateWaiting    FallbackState = "waiting"    // Primary delivery not yet sent, failed or timed out
+	FallbackStateEscalating FallbackState = "escalating" // Running steps
+	FallbackStateResolved   FallbackState = "resolved"   // Delivered by the primary send or a resend step
+	FallbackStateExhausted  FallbackState = "exhausted"  // All steps ran without a successful delivery
+)
+
+type FallbackStepStatus string
+
+const (
+	FallbackStepPending   FallbackStepStatus = "pending"
+	FallbackStepSucceeded FallbackStepStatus = "succeeded"
+	FallbackStepFailed    FallbackStepStatus = "failed"
+	FallbackStepSkipped   FallbackStepStatus = "skipped"
+)
+
+// FallbackPlan is the escalation state machine persisted on an email so it survives restarts
+type FallbackPlan struct {
+	EscalateAfterMinutes int            `json:"escalate_after_minutes,omitempty" bson:"escalate_after_minutes,omitempty"`
+	Steps                []FallbackStep `json:"steps" bson:"steps"`
+	State                FallbackState  `json:"state" bson:"state"`
+	CurrentStep          int            `json:"current_step" bson:"current_step"` // Index of the next step to run
+	Reason               string         `json:"reason,omitempty" bson:"reason,omitempty"`
+	EscalateAt           time.Time      `json:"escalate_at,omitempty" bson:"escalate_at,omitempty"`
+	EscalatedAt          time.Time      `json:"escalated_at,omitempty" bson:"escalated_at,omitempty"`
+	LockedUntil          time.Time      `json:"-" bson:"locked_until,omitempty"`
+}
+
+type FallbackStep struct {
+	Action      FallbackAction     `json:"action" bson:"action"`
+	To          []string           `json:"to,omitempty" bson:"to,omitempty"`
+	Webhook     string             `json:"webhook,omitempty" bson:"webhook,omitempty"`
+	Status      FallbackStepStatus `json:"status" bson:"status"`
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+	CompletedAt time.Time          `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+type FallbackPlanRequest struct {
+	EscalateAfterMinutes int                   `json:"escalate_after_minutes" binding:"min=0,max=10080"`
+	Steps                []FallbackStepRequest `json:"steps" binding:"required,min=1,max=10,dive"`
+}
+
+type FallbackStepRequest struct {
+	Action  FallbackAction `json:"action" binding:"required,oneof=resend webhook"`
+	To      []string       `json:"to,omitempty" binding:"max=100,dive,email"`
+	Webhook string         `json:"webhook,omitempty"` // Name of a configured escalation webhook, defaults to the first one
+}
+
+// ToPlan converts the request into a plan, the service fills in the initial state
+func (request *FallbackPlanRequest) ToPlan() *FallbackPlan {
+	if request == nil {
+		return nil
+	}
+
+	plan := &FallbackPlan{
+		EscalateAfterMinutes: request.EscalateAfterMinutes,
+		Steps:                make([]FallbackStep, len(request.Steps)),
+	}
+	for i, step := range request.Steps {
+		plan.Steps[i] = FallbackStep{
+			Action:  step.Action,
+			To:      step.To,
+			Webhook: step.Webhook,
+		}
+	}
+
+	return plan
+}
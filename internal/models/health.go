@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthUp       HealthStatus = "up"
+	HealthDegraded HealthStatus = "degraded" // Working, but some of it is failing
+	HealthDown     HealthStatus = "down"
+	HealthUnknown  HealthStatus = "unknown" // Not checked yet
+)
+
+// HealthCheck is the result of checking one dependency
+type HealthCheck struct {
+	Status    HealthStatus       `json:"status"`
+	LatencyMs int64              `json:"latency_ms,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	Servers   []SMTPServerHealth `json:"servers,omitempty"`
+	Backlog   *Backlog           `json:"backlog,omitempty"`
+	CheckedAt time.Time          `json:"checked_at"`
+}
+
+type SMTPServerHealth struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	Error     string       `json:"error,omitempty"`
+	CheckedAt time.Time    `json:"checked_at,omitempty"`
+}
+
+// Backlog is the work the background dispatchers are behind on
+type Backlog struct {
+	StalePendingEmails       int64 `json:"stale_pending_emails"`       // Pending longer than the backlog age
+	OverdueWebhookDeliveries int64 `json:"overdue_webhook_deliveries"` // Due longer than the backlog age
+}
+
+// HealthReport is the readiness of the service, ready unless a check it depends on is down
+type HealthReport struct {
+	Status string                  `json:"status"` // ready or not_ready
+	Checks map[string]*HealthCheck `json:"checks"`
+}
+
+func (report *HealthReport) Ready() bool {
+	return report.Status == "ready"
+}
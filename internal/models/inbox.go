@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type InboxItem struct {
+	ID         bson.ObjectID     `json:"id" bson:"_id,omitempty"`
+	UserID     string            `json:"user_id" bson:"user_id"`
+	Title      string            `json:"title" bson:"title"`
+	Body       string            `json:"body,omitempty" bson:"body,omitempty"`
+	Link       string            `json:"link,omitempty" bson:"link,omitempty"`
+	Data       map[string]string `json:"data,omitempty" bson:"data,omitempty"`
+	Read       bool              `json:"read" bson:"read"`
+	Archived   bool              `json:"archived" bson:"archived"`
+	CreatedAt  time.Time         `json:"created_at" bson:"created_at"`
+	ReadAt     *time.Time        `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	ArchivedAt *time.Time        `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+}
+
+// InboxFilter selects the items returned when listing a user's inbox
+type InboxFilter struct {
+	UserID     string
+	UnreadOnly bool
+	Archived   bool
+	Before     bson.ObjectID // Cursor, only items older than this ID are returned
+	Limit      int64
+}
+
+type CreateInboxItemRequest struct {
+	UserID string            `json:"user_id" binding:"required,max=255"`
+	Title  string            `json:"title" binding:"required,max=255"`
+	Body   string            `json:"body,omitempty" binding:"max=10000"`
+	Link   string            `json:"link,omitempty" binding:"omitempty,url"`
+	Data   map[string]string `json:"data,omitempty"`
+}
+
+type ListInboxRequest struct {
+	UnreadOnly bool   `form:"unread_only"`
+	Archived   bool   `form:"archived"`
+	Before     string `form:"before"`
+	Limit      int64  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type InboxListResponse struct {
+	Items      []*InboxItem `json:"items"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+type InboxUnreadCountResponse struct {
+	UserID string `json:"user_id"`
+	Unread int64  `json:"unread"`
+}
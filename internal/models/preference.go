@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type Channel string
+
+// Telegram messages and inbox items have no category, so email is the only channel preferences apply to
+const ChannelEmail Channel = "email"
+
+// PreferenceWildcard matches every category or channel in a preference rule
+const PreferenceWildcard = "*"
+
+// Preference holds a recipient's opt-ins and opt-outs with a tenant. Categories and channels without
+// a rule are opted in.
+type Preference struct {
+	ID        bson.ObjectID    `json:"id" bson:"_id,omitempty"`
+	Tenant    string           `json:"tenant" bson:"tenant"`
+	Address   string           `json:"address" bson:"address"`
+	Rules     []PreferenceRule `json:"rules" bson:"rules"`
+	UpdatedAt time.Time        `json:"updated_at" bson:"updated_at"`
+}
+
+type PreferenceRule struct {
+	Category string `json:"category" bson:"category" binding:"required,max=64"` // Category name or "*"
+	Channel  string `json:"channel" bson:"channel" binding:"required,oneof=* email"`
+	OptedIn  bool   `json:"opted_in" bson:"opted_in"`
+}
+
+// Allows reports whether the recipient accepts messages of category on channel.
+// The most specific matching rule wins: exact, category on any channel, any category on channel, then "*"/"*".
+func (preference *Preference) Allows(category string, channel Channel) bool {
+	best, allowed := -1, true
+	for _, rule := range preference.Rules {
+		specificity := 0
+		switch rule.Category {
+		case category:
+			specificity += 2
+		case PreferenceWildcard:
+		default:
+			continue
+		}
+		switch rule.Channel {
+		case string(channel):
+			specificity++
+		case PreferenceWildcard:
+		default:
+			continue
+		}
+
+		if specificity > best {
+			best, allowed = specificity, rule.OptedIn
+		}
+	}
+
+	return allowed
+}
+
+type UpdatePreferenceRequest struct {
+	Rules []PreferenceRule `json:"rules" binding:"max=100,dive"`
+}
+
+// SuppressedRecipient is an address removed from an email before dispatch
+type SuppressedRecipient struct {
+	Address string `json:"address" bson:"address"`
+	Reason  string `json:"reason" bson:"reason"`
+}
+
+// UnsubscribeToken is the content of a signed one-click unsubscribe link
+type UnsubscribeToken struct {
+	EmailID  bson.ObjectID `json:"email_id"`
+	Tenant   string        `json:"tenant"` // Empty in links issued before tokens carried it
+	Address  string        `json:"address"`
+	Category string        `json:"category"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Recipient is a user profile of a tenant that its emails can target by user ID instead of raw addresses
+type Recipient struct {
+	ID          bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Tenant      string        `json:"tenant" bson:"tenant"`
+	UserID      string        `json:"user_id" bson:"user_id"`
+	Emails      []string      `json:"emails" bson:"emails"` // The first address is the primary one used for delivery
+	DisplayName string        `json:"display_name,omitempty" bson:"display_name,omitempty"`
+	Locale      string        `json:"locale,omitempty" bson:"locale,omitempty"`
+	Timezone    string        `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+// PrimaryEmail returns the address emails to this recipient are delivered to
+func (recipient *Recipient) PrimaryEmail() string {
+	if len(recipient.Emails) == 0 {
+		return ""
+	}
+
+	return recipient.Emails[0]
+}
+
+type RecipientRequest struct {
+	UserID      string   `json:"user_id" binding:"required,max=255"`
+	Emails      []string `json:"emails" binding:"required,min=1,max=10,dive,email"`
+	DisplayName string   `json:"display_name,omitempty" binding:"max=255"`
+	Locale      string   `json:"locale,omitempty" binding:"max=35"`
+	Timezone    string   `json:"timezone,omitempty" binding:"max=64"`
+}
+
+type ListRecipientsRequest struct {
+	After string `form:"after"` // Cursor, only recipients with a greater user ID are returned
+	Limit int64  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type RecipientListResponse struct {
+	Recipients []*Recipient `json:"recipients"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
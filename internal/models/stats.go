@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+type StatsGroupBy string
+
+const (
+	StatsByDay      StatsGroupBy = "day"
+	StatsByHour     StatsGroupBy = "hour"
+	StatsByServer   StatsGroupBy = "server"
+	StatsBySender   StatsGroupBy = "sender"
+	StatsByCategory StatsGroupBy = "category"
+	StatsByDomain   StatsGroupBy = "domain" // Recipient domain
+)
+
+// StatsCounts are recipient counts of the emails created in a period. Opens and clicks are counted
+// once per recipient, prefetches excluded.
+type StatsCounts struct {
+	Pending      int `json:"pending" bson:"pending"`
+	Sent         int `json:"sent" bson:"sent"`
+	Failed       int `json:"failed" bson:"failed"`
+	Bounced      int `json:"bounced" bson:"bounced"`
+	Suppressed   int `json:"suppressed" bson:"suppressed"`
+	Opened       int `json:"opened" bson:"opened"`
+	Clicked      int `json:"clicked" bson:"clicked"`
+	OpenTracked  int `json:"open_tracked" bson:"open_tracked"`   // Sent recipients of emails with open tracking
+	ClickTracked int `json:"click_tracked" bson:"click_tracked"` // Sent recipients of emails with click tracking
+}
+
+// EmailStatsRollup holds the counts of one hour of emails for one combination of dimensions
+type EmailStatsRollup struct {
+	Tenant      string    `json:"tenant" bson:"tenant"`
+	Hour        time.Time `json:"hour" bson:"hour"`
+	Category    string    `json:"category" bson:"category"`
+	Server      string    `json:"server" bson:"server"`
+	Sender      string    `json:"sender" bson:"sender"`
+	Domain      string    `json:"domain" bson:"domain"`
+	StatsCounts `bson:",inline"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// StatsGroup is the counts and rates of one group, rates are 0 when their base is 0
+type StatsGroup struct {
+	Key          string `json:"key" bson:"_id"` // Start of the day or hour in RFC 3339, or the dimension value
+	StatsCounts  `bson:",inline"`
+	DeliveryRate float64 `json:"delivery_rate" bson:"-"` // sent / (sent + failed + bounced)
+	BounceRate   float64 `json:"bounce_rate" bson:"-"`   // bounced / (sent + bounced)
+	OpenRate     float64 `json:"open_rate" bson:"-"`     // opened / open_tracked
+	ClickRate    float64 `json:"click_rate" bson:"-"`    // clicked / click_tracked
+}
+
+type StatsRequest struct {
+	GroupBy  StatsGroupBy `form:"group_by" binding:"omitempty,oneof=day hour server sender category domain"`
+	Since    time.Time    `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until    time.Time    `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Category string       `form:"category"`
+	Server   string       `form:"server"`
+	Sender   string       `form:"sender"`
+	Domain   string       `form:"domain"`
+	Tenant   string       `form:"-"` // Set from the caller's principal
+}
+
+type StatsResponse struct {
+	GroupBy StatsGroupBy  `json:"group_by"`
+	Since   time.Time     `json:"since"`
+	Until   time.Time     `json:"until"`
+	Totals  *StatsGroup   `json:"totals"`
+	Groups  []*StatsGroup `json:"groups"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type SuppressionReason string
+
+const (
+	SuppressionHardBounce  SuppressionReason = "hard_bounce"
+	SuppressionComplaint   SuppressionReason = "complaint"
+	SuppressionUnsubscribe SuppressionReason = "unsubscribe"
+	SuppressionManual      SuppressionReason = "manual"
+)
+
+type SuppressionSource string
+
+const (
+	SuppressionSourceAPI    SuppressionSource = "api"
+	SuppressionSourceImport SuppressionSource = "import"
+	SuppressionSourceSMTP   SuppressionSource = "smtp"   // Permanent rejection while sending
+	SuppressionSourceBounce SuppressionSource = "bounce" // Delivery status notification
+	SuppressionSourceFBL    SuppressionSource = "fbl"    // Feedback loop complaint report
+)
+
+// Suppression blocks all email of its tenant to an address until it is removed or expires
+type Suppression struct {
+	ID        bson.ObjectID     `json:"id" bson:"_id,omitempty"`
+	Tenant    string            `json:"-" bson:"tenant"`
+	Address   string            `json:"address" bson:"address"`
+	Reason    SuppressionReason `json:"reason" bson:"reason"`
+	Source    SuppressionSource `json:"source" bson:"source"`
+	Detail    string            `json:"detail,omitempty" bson:"detail,omitempty"`
+	CreatedAt time.Time         `json:"created_at" bson:"created_at"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+}
+
+type CreateSuppressionRequest struct {
+	Address   string            `json:"address" binding:"required,email"`
+	Reason    SuppressionReason `json:"reason" binding:"required,oneof=hard_bounce complaint unsubscribe manual"`
+	Detail    string            `json:"detail,omitempty" binding:"max=1000"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
+}
+
+type ListSuppressionsRequest struct {
+	Reason SuppressionReason `form:"reason" binding:"omitempty,oneof=hard_bounce complaint unsubscribe manual"`
+	After  string            `form:"after"` // Cursor, only addresses sorting after this one are returned
+	Limit  int64             `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type SuppressionListResponse struct {
+	Suppressions []*Suppression `json:"suppressions"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
+}
+
+type SuppressionImportResult struct {
+	Imported int                      `json:"imported"`
+	Skipped  int                      `json:"skipped"`
+	Errors   []SuppressionImportError `json:"errors,omitempty"`
+}
+
+type SuppressionImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
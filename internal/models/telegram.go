@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type TelegramParseMode string
+
+const (
+	ParseModeText       TelegramParseMode = ""
+	ParseModeMarkdown   TelegramParseMode = "Markdown"
+	ParseModeMarkdownV2 TelegramParseMode = "MarkdownV2"
+	ParseModeHTML       TelegramParseMode = "HTML"
+)
+
+type TelegramMessage struct {
+	ID        bson.ObjectID     `json:"id" bson:"_id,omitempty"`
+	Bot       string            `json:"bot" bson:"bot"`
+	ChatIDs   []string          `json:"chat_ids" bson:"chat_ids"`
+	Text      string            `json:"text,omitempty" bson:"text,omitempty"`
+	ParseMode TelegramParseMode `json:"parse_mode,omitempty" bson:"parse_mode,omitempty"`
+	Document  *Attachment       `json:"document,omitempty" bson:"document,omitempty"`
+	Status    EmailStatus       `json:"status" bson:"status"` // partial when some chats got it and others didn't
+	ErrorMsg  string            `json:"error_message,omitempty" bson:"error_message,omitempty"`
+	Chats     []TelegramChat    `json:"chats,omitempty" bson:"chats,omitempty"`
+	CreatedAt time.Time         `json:"created_at" bson:"created_at"`
+	SentAt    time.Time         `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+}
+
+// TelegramChat is the delivery state of a message in one of its chats, so a retry only sends it to the
+// chats that didn't get it
+type TelegramChat struct {
+	ChatID   string      `json:"chat_id" bson:"chat_id"`
+	Status   EmailStatus `json:"status" bson:"status"` // pending | sent | failed
+	ErrorMsg string      `json:"error_message,omitempty" bson:"error_message,omitempty"`
+	SentAt   time.Time   `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+}
+
+type SendTelegramRequest struct {
+	Bot       string            `json:"bot,omitempty"`
+	ChatIDs   []string          `json:"chat_ids" binding:"required,min=1,max=100"`
+	Text      string            `json:"text,omitempty"`
+	ParseMode TelegramParseMode `json:"parse_mode,omitempty" binding:"omitempty,oneof=Markdown MarkdownV2 HTML"`
+	Document  *Attachment       `json:"document,omitempty"`
+}
+
+type TelegramResponse struct {
+	ID        string      `json:"id"`
+	Status    EmailStatus `json:"status"`
+	Message   string      `json:"message"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// DefaultTenant owns the data of platform principals and of deployments without tenants. It isn't
+// stored and sends through the configured SMTP servers.
+const DefaultTenant = "default"
+
+// Tenant is an isolated customer of the platform with its own SMTP servers, sender identities,
+// suppression list, API keys and emails
+type Tenant struct {
+	ID          string             `json:"id" bson:"_id"`
+	Name        string             `json:"name" bson:"name"`
+	SMTPServers []TenantSMTPServer `json:"smtp_servers" bson:"smtp_servers"`
+	Senders     []string           `json:"senders,omitempty" bson:"senders,omitempty"` // From addresses emails may use besides the servers' own
+	Disabled    bool               `json:"disabled" bson:"disabled"`                   // Disabled tenants can't authenticate
+	Limits      *TenantLimits      `json:"limits,omitempty" bson:"limits,omitempty"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// TenantSMTPServer is an SMTP server of a tenant, its password is never returned by the API
+type TenantSMTPServer struct {
+	Name      string `json:"name,omitempty" bson:"name,omitempty"`
+	Host      string `json:"host" bson:"host" binding:"required,hostname|ip"`
+	Port      int    `json:"port" bson:"port" binding:"required,min=1,max=65535"`
+	Username  string `json:"username,omitempty" bson:"username,omitempty"`
+	Password  string `json:"password,omitempty" bson:"password,omitempty"`
+	FromEmail string `json:"from_email" bson:"from_email" binding:"required"`
+}
+
+// TenantLimits overrides the configured tenant limits, zero fields keep the configured value
+type TenantLimits struct {
+	Rate         float64 `json:"rate,omitempty" bson:"rate,omitempty" binding:"min=0"`                   // Sends per second
+	Burst        int     `json:"burst,omitempty" bson:"burst,omitempty" binding:"min=0"`                 // Sends at once before the rate applies
+	MonthlyQuota int64   `json:"monthly_quota,omitempty" bson:"monthly_quota,omitempty" binding:"min=0"` // Emails per calendar month in UTC
+}
+
+type CreateTenantRequest struct {
+	ID          string             `json:"id" binding:"required"`
+	Name        string             `json:"name" binding:"required,max=255"`
+	SMTPServers []TenantSMTPServer `json:"smtp_servers" binding:"required,min=1,max=20,dive"`
+	Senders     []string           `json:"senders,omitempty" binding:"max=100,dive,email"`
+	Limits      *TenantLimits      `json:"limits,omitempty"`
+}
+
+// UpdateTenantRequest replaces a tenant's settings. Servers sent without a password keep the
+// password of the server with the same name.
+type UpdateTenantRequest struct {
+	Name        string             `json:"name" binding:"required,max=255"`
+	SMTPServers []TenantSMTPServer `json:"smtp_servers" binding:"required,min=1,max=20,dive"`
+	Senders     []string           `json:"senders,omitempty" binding:"max=100,dive,email"`
+	Disabled    bool               `json:"disabled"`
+	Limits      *TenantLimits      `json:"limits,omitempty"`
+}
+
+type TenantListResponse struct {
+	Tenants []*Tenant `json:"tenants"`
+}
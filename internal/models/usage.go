@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Usage counts what a tenant sent in a calendar month, monthly quotas are checked against it
+type Usage struct {
+	Tenant     string    `json:"tenant" bson:"tenant"`
+	Month      string    `json:"month" bson:"month"`           // YYYY-MM in UTC
+	Emails     int64     `json:"emails" bson:"emails"`         // Accepted send requests
+	Recipients int64     `json:"recipients" bson:"recipients"` // Addresses of the accepted emails, a recipient profile counts as one
+	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+type UsageRequest struct {
+	Month  string `form:"month"`  // YYYY-MM, defaults to the current month
+	Tenant string `form:"tenant"` // Platform principals only, defaults to the caller's tenant
+}
+
+type UsageResponse struct {
+	Tenant     string    `json:"tenant"`
+	Month      string    `json:"month"`
+	Emails     int64     `json:"emails"`
+	Recipients int64     `json:"recipients"`
+	Quota      int64     `json:"quota"`               // Emails allowed in the month, 0 is unlimited
+	Remaining  *int64    `json:"remaining,omitempty"` // Omitted when unlimited
+	ResetsAt   time.Time `json:"resets_at"`           // Start of the next month
+}
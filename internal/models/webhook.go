@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookEmailSent         WebhookEventType = "sent" // Email delivered to all or some of its recipients
+	WebhookEmailFailed       WebhookEventType = "failed"
+	WebhookEmailBounced      WebhookEventType = "bounced"
+	WebhookEmailComplained   WebhookEventType = "complained"
+	WebhookEmailOpened       WebhookEventType = "opened"
+	WebhookEmailClicked      WebhookEventType = "clicked"
+	WebhookEmailUnsubscribed WebhookEventType = "unsubscribed"
+)
+
+// WebhookEndpoint is a subscriber URL that receives signed events of the types it selected
+type WebhookEndpoint struct {
+	ID                  bson.ObjectID      `json:"id" bson:"_id,omitempty"`
+	Tenant              string             `json:"tenant" bson:"tenant"`
+	URL                 string             `json:"url" bson:"url"`
+	Description         string             `json:"description,omitempty" bson:"description,omitempty"`
+	Events              []WebhookEventType `json:"events" bson:"events"`
+	Secret              string             `json:"secret,omitempty" bson:"secret"` // Only returned when the endpoint is created
+	Enabled             bool               `json:"enabled" bson:"enabled"`
+	ConsecutiveFailures int                `json:"consecutive_failures" bson:"consecutive_failures"`
+	DisabledReason      string             `json:"disabled_reason,omitempty" bson:"disabled_reason,omitempty"`
+	DisabledAt          *time.Time         `json:"disabled_at,omitempty" bson:"disabled_at,omitempty"`
+	CreatedAt           time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // Gave up after the last attempt
+)
+
+// WebhookDelivery is one event queued for one endpoint, with the log of its attempts
+type WebhookDelivery struct {
+	ID            bson.ObjectID         `json:"id" bson:"_id,omitempty"`
+	EndpointID    bson.ObjectID         `json:"endpoint_id" bson:"endpoint_id"`
+	EventID       string                `json:"event_id" bson:"event_id"`
+	EventType     WebhookEventType      `json:"event_type" bson:"event_type"`
+	Payload       string                `json:"payload" bson:"payload"` // Exact JSON body, so redeliveries are byte-identical
+	Status        WebhookDeliveryStatus `json:"status" bson:"status"`
+	AttemptCount  int                   `json:"attempt_count" bson:"attempt_count"`
+	Attempts      []WebhookAttempt      `json:"attempts,omitempty" bson:"attempts,omitempty"` // Latest attempts only
+	NextAttemptAt time.Time             `json:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	RedeliveryOf  *bson.ObjectID        `json:"redelivery_of,omitempty" bson:"redelivery_of,omitempty"`
+	CreatedAt     time.Time             `json:"created_at" bson:"created_at"`
+	CompletedAt   time.Time             `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	LockedUntil   time.Time             `json:"-" bson:"locked_until,omitempty"`
+}
+
+type WebhookAttempt struct {
+	AttemptedAt time.Time `json:"attempted_at" bson:"attempted_at"`
+	StatusCode  int       `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms" bson:"duration_ms"`
+}
+
+// WebhookEvent is the JSON body POSTed to subscribers
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	Tenant    string           `json:"tenant,omitempty"` // Tenant of the email
+	EmailID   string           `json:"email_id,omitempty"`
+	Recipient string           `json:"recipient,omitempty"`
+	Status    string           `json:"status,omitempty"` // Email status after the transition
+	Category  string           `json:"category,omitempty"`
+	Detail    string           `json:"detail,omitempty"`
+	URL       string           `json:"url,omitempty"` // Link target of a click
+	Time      time.Time        `json:"time"`
+}
+
+type WebhookEndpointRequest struct {
+	URL         string             `json:"url" binding:"required,url,max=2048"`
+	Description string             `json:"description,omitempty" binding:"max=255"`
+	Events      []WebhookEventType `json:"events" binding:"required,min=1,dive,oneof=sent failed bounced complained opened clicked unsubscribed"`
+	Enabled     *bool              `json:"enabled,omitempty"` // Defaults to true, re-enabling resets the failure count
+}
+
+type WebhookEndpointListResponse struct {
+	Endpoints []*WebhookEndpoint `json:"endpoints"`
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Status WebhookDeliveryStatus `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	Before string                `form:"before"` // Cursor, only deliveries older than this ID are returned
+	Limit  int64                 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
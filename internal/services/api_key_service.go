@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+// Last use is recorded at most this often per key, so busy keys don't cost a write per request
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyService struct {
+	db           *database.Database
+	tenants      *TenantDirectory
+	bootstrapKey string
+	touchedAt    sync.Map // Key ID to the last time its use was recorded
+}
+
+func NewAPIKeyService(db *database.Database, cfg *config.Config, tenants *TenantDirectory) types.APIKeyService {
+	return &APIKeyService{
+		db:           db,
+		tenants:      tenants,
+		bootstrapKey: cfg.Auth.BootstrapKey,
+	}
+}
+
+// CreateAPIKey creates a key for the requested tenant, keys created by tenant principals always
+// belong to their own tenant
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	tenant := request.Tenant
+	if principal := auth.PrincipalFromContext(ctx); !principal.IsPlatform() {
+		if tenant != "" && tenant != principal.Tenant {
+			return nil, fmt.Errorf("%w: can't create API keys for another tenant", types.ErrPermissionDenied)
+		}
+		tenant = principal.Tenant
+	} else if tenant != "" {
+		existing, err := s.tenants.Lookup(ctx, tenant)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("%w: tenant %s", types.ErrNotFound, tenant)
+		}
+	}
+
+	key, prefix, err := auth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := slices.Clone(request.Scopes)
+	slices.Sort(scopes)
+
+	apiKey, err := s.db.CreateAPIKey(ctx, &models.APIKey{
+		Name:   request.Name,
+		Prefix: prefix,
+		Hash:   auth.HashKey(key),
+		Scopes: slices.Compact(scopes),
+		Tenant: tenant,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CreateAPIKeyResponse{APIKey: apiKey, Key: key}, nil
+}
+
+// ListAPIKeys returns every key to platform principals, and the keys of their tenant to others
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return s.db.ListAPIKeys(ctx, managedTenant(ctx))
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) (*models.APIKey, error) {
+	keyID, err := parseObjectID(id, "API key")
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := s.db.RevokeAPIKey(ctx, keyID, managedTenant(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, fmt.Errorf("%w: API key %s", types.ErrNotFound, id)
+	}
+
+	return apiKey, nil
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*models.Principal, error) {
+	if key == "" {
+		return nil, fmt.Errorf("%w: API key required", types.ErrUnauthenticated)
+	}
+
+	if s.bootstrapKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(s.bootstrapKey)) == 1 {
+		return &models.Principal{
+			Kind:   models.PrincipalBootstrap,
+			ID:     "bootstrap",
+			Name:   "bootstrap",
+			Scopes: []models.APIKeyScope{models.ScopeAdmin},
+		}, nil
+	}
+
+	apiKey, err := s.db.GetAPIKeyByHash(ctx, auth.HashKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || apiKey.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: invalid or revoked API key", types.ErrUnauthenticated)
+	}
+
+	s.touch(ctx, apiKey)
+
+	return &models.Principal{
+		Kind:   models.PrincipalAPIKey,
+		ID:     apiKey.ID.Hex(),
+		Name:   apiKey.Name,
+		Tenant: apiKey.Tenant,
+		Scopes: apiKey.Scopes,
+	}, nil
+}
+
+// managedTenant returns the tenant whose keys the caller manages, empty for platform principals who
+// manage every key
+func managedTenant(ctx context.Context) string {
+	if principal := auth.PrincipalFromContext(ctx); principal != nil {
+		return principal.Tenant
+	}
+
+	return ""
+}
+
+// touch records the key's use in the background, at most once per apiKeyTouchInterval
+func (s *APIKeyService) touch(ctx context.Context, apiKey *models.APIKey) {
+	now := time.Now()
+	if last, ok := s.touchedAt.Load(apiKey.ID); ok && now.Sub(last.(time.Time)) < apiKeyTouchInterval {
+		return
+	}
+	if apiKey.LastUsedAt != nil && now.Sub(*apiKey.LastUsedAt) < apiKeyTouchInterval {
+		s.touchedAt.Store(apiKey.ID, *apiKey.LastUsedAt)
+		return
+	}
+	s.touchedAt.Store(apiKey.ID, now)
+
+	go s.db.TouchAPIKey(context.WithoutCancel(ctx), apiKey.ID, now)
+}
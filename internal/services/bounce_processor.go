@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/bounce"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+// BounceProcessor polls the bounce mailbox and hands every message to the inbound service
+type BounceProcessor struct {
+	cfg            *config.Config
+	source         bounce.Source
+	inboundService types.InboundService
+}
+
+func NewBounceProcessor(cfg *config.Config, inboundService types.InboundService) (*BounceProcessor, error) {
+	source, err := bounce.NewSource(cfg.Bounce)
+	if err != nil {
+		slog.Error("Failed to configure bounce source", "error", err)
+		return nil, err
+	}
+
+	return &BounceProcessor{
+		cfg:            cfg,
+		source:         source,
+		inboundService: inboundService,
+	}, nil
+}
+
+func (p *BounceProcessor) Run(ctx context.Context) {
+	if p.source == nil {
+		slog.Info("Bounce processing disabled, no bounce source configured")
+		return
+	}
+
+	interval := time.Duration(p.cfg.Bounce.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = 60 * time.Second
+	}
+
+	slog.Info("Starting bounce processor", "source", p.cfg.Bounce.Source, "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Mailbox messages have no SMTP envelope, correlation relies on their headers
+	handle := func(raw []byte) error {
+		return p.inboundService.ReceiveReport(ctx, raw)
+	}
+
+	for {
+		if err := p.source.Poll(ctx, handle); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to poll bounce mailbox", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+// recordEmailEvents appends events to email timelines. The timeline is for investigating
+// deliveries, failing to write it never fails the delivery itself.
+func recordEmailEvents(ctx context.Context, db *database.Database, events ...*models.EmailEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		event.Detail = truncate(event.Detail, 2000)
+	}
+
+	_ = db.CreateEmailEvents(ctx, events)
+}
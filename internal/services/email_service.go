@@ -1,121 +1,991 @@
 package services
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/aarondever/notiflow/internal/auth"
 	"github.com/aarondever/notiflow/internal/config"
 	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/metrics"
 	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/tracing"
 	"github.com/aarondever/notiflow/internal/types"
-	"gopkg.in/gomail.v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var categoryPattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// Emails of a batch are delivered this many at a time
+const batchSendConcurrency = 10
+
 type EmailService struct {
-	db                   *database.Database
-	cfg                  *config.Config
-	smtpServerCount      int
-	smtpServerUsageCount int
+	db          *database.Database
+	cfg         *config.Config
+	sender      *SMTPSender
+	tenants     *TenantDirectory
+	limiter     *SendLimiter
+	unsubscribe *unsubscribeSigner
+	tracking    *trackingSigner
 }
 
-func NewEmailService(db *database.Database, cfg *config.Config) types.EmailService {
+func NewEmailService(db *database.Database, cfg *config.Config, sender *SMTPSender, tenants *TenantDirectory, limiter *SendLimiter) types.EmailService {
 	return &EmailService{
-		db:                   db,
-		cfg:                  cfg,
-		smtpServerCount:      len(cfg.SMTPServers),
-		smtpServerUsageCount: 0,
+		db:          db,
+		cfg:         cfg,
+		sender:      sender,
+		tenants:     tenants,
+		limiter:     limiter,
+		unsubscribe: newUnsubscribeSigner(cfg),
+		tracking:    newTrackingSigner(cfg),
 	}
 }
 
 func (s *EmailService) SendEmail(ctx context.Context, email *models.Email) (*models.Email, error) {
-	if s.smtpServerCount == 0 {
-		return nil, fmt.Errorf("no SMTP servers configured")
+	principal := auth.PrincipalFromContext(ctx)
+	tenant, err := s.sendingTenant(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prepareEmail(ctx, principal, tenant, email); err != nil {
+		return nil, err
+	}
+
+	// Fail fast on unknown recipients, the address itself is resolved at dispatch time
+	if email.UserID != "" {
+		recipient, err := s.db.GetRecipientByUserID(ctx, email.Tenant, email.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if recipient == nil {
+			return nil, fmt.Errorf("%w: recipient %s", types.ErrNotFound, email.UserID)
+		}
+	}
+
+	// Only valid emails take a rate limit token, like in a batch
+	if err := s.limiter.Throttle(principal, tenant, 1); err != nil {
+		return nil, err
+	}
+
+	release, err := s.limiter.ReserveQuota(ctx, tenant, 1, recipientCount(email))
+	if err != nil {
+		return nil, err
 	}
 
 	// Save to database
 	dbEmail, err := s.db.CreateEmail(ctx, email)
 	if err != nil {
+		release(1, recipientCount(email))
 		slog.Error("Failed to create email", "error", err)
 		return nil, err
 	}
 
+	recordEmailEvents(ctx, s.db, queuedEvent(dbEmail))
+
 	// Send email asynchronously
 	go s.sendEmailAsync(dbEmail)
 
-	s.smtpServerUsageCount++
-
 	return dbEmail, nil
 }
 
-func (s *EmailService) sendEmailAsync(email *models.Email) {
-	ctx := context.Background()
+// SendEmailBatch queues many emails with one quota reservation and one insert. Every valid email takes
+// a rate limit token and the quota must fit all of them, the rest is checked per email.
+func (s *EmailService) SendEmailBatch(ctx context.Context, emails []*models.Email) ([]error, error) {
+	if len(emails) > models.MaxEmailBatch {
+		return nil, fmt.Errorf("%w: a batch holds at most %d emails", types.ErrInvalidArgument, models.MaxEmailBatch)
+	}
 
-	smtpServer := s.cfg.SMTPServers[s.smtpServerUsageCount%s.smtpServerCount]
-	slog.Info("Using SMTP server sending email", "username", smtpServer.Username, "host")
+	principal := auth.PrincipalFromContext(ctx)
+	tenant, err := s.sendingTenant(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create message
-	message := gomail.NewMessage()
-	message.SetHeader("From", smtpServer.FromEmail)
-	message.SetHeader("To", email.To...)
+	errs := make([]error, len(emails))
+	var userIDs []string
+	for i, email := range emails {
+		errs[i] = s.prepareEmail(ctx, principal, tenant, email)
+		if errs[i] == nil && email.UserID != "" {
+			userIDs = append(userIDs, email.UserID)
+		}
+	}
 
-	if len(email.CC) > 0 {
-		message.SetHeader("Cc", email.CC...)
+	// One query for every recipient profile instead of one per email
+	if len(userIDs) > 0 {
+		existing, err := s.db.ExistingRecipientUserIDs(ctx, tenant.ID, userIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i, email := range emails {
+			if errs[i] == nil && email.UserID != "" && !existing[email.UserID] {
+				errs[i] = fmt.Errorf("%w: recipient %s", types.ErrNotFound, email.UserID)
+			}
+		}
 	}
-	if len(email.BCC) > 0 {
-		message.SetHeader("Bcc", email.BCC...)
+
+	var accepted []*models.Email
+	var acceptedIndexes []int
+	var recipients int64
+	for i, email := range emails {
+		if errs[i] == nil {
+			accepted = append(accepted, email)
+			acceptedIndexes = append(acceptedIndexes, i)
+			recipients += recipientCount(email)
+		}
+	}
+	if len(accepted) == 0 {
+		return errs, nil
 	}
 
-	message.SetHeader("Subject", email.Subject)
+	if err := s.limiter.Throttle(principal, tenant, len(accepted)); err != nil {
+		return nil, err
+	}
 
-	if email.IsHTML {
-		message.SetBody("text/html", email.Body)
-	} else {
-		message.SetBody("text/plain", email.Body)
+	release, err := s.limiter.ReserveQuota(ctx, tenant, int64(len(accepted)), recipients)
+	if err != nil {
+		return nil, err
 	}
 
-	// Add attachments
-	for _, attachment := range email.Attachments {
-		reader := bytes.NewReader(attachment.Content)
-		message.Attach(attachment.Filename, gomail.SetCopyFunc(func(w io.Writer) error {
-			_, err := io.Copy(w, reader)
+	insertErrs, err := s.db.CreateEmails(ctx, accepted)
+	if err != nil {
+		release(int64(len(accepted)), recipients)
+		return nil, err
+	}
+
+	queued := make([]*models.Email, 0, len(accepted))
+	events := make([]*models.EmailEvent, 0, len(accepted))
+	var failedEmails, failedRecipients int64
+	for j, email := range accepted {
+		if insertErr := insertErrs[j]; insertErr != nil {
+			if errors.Is(insertErr, database.ErrInvalidEmail) {
+				insertErr = fmt.Errorf("%w: %w", types.ErrInvalidArgument, insertErr)
+			}
+			errs[acceptedIndexes[j]] = insertErr
+			failedEmails++
+			failedRecipients += recipientCount(email)
+			continue
+		}
+
+		queued = append(queued, email)
+		events = append(events, queuedEvent(email))
+	}
+	if failedEmails > 0 {
+		release(failedEmails, failedRecipients)
+	}
+
+	recordEmailEvents(ctx, s.db, events...)
+
+	go s.sendEmailBatchAsync(queued)
+
+	return errs, nil
+}
+
+// sendingTenant returns the tenant the principal sends for, which needs SMTP servers
+func (s *EmailService) sendingTenant(ctx context.Context, principal *models.Principal) (*models.Tenant, error) {
+	tenant, err := s.tenants.Lookup(ctx, principal.TenantID())
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil || len(tenant.SMTPServers) == 0 {
+		return nil, fmt.Errorf("no SMTP servers configured")
+	}
+
+	return tenant, nil
+}
+
+// prepareEmail validates a new email of the tenant and fills in what the service sets. Recipient
+// profiles are checked by the caller.
+func (s *EmailService) prepareEmail(ctx context.Context, principal *models.Principal, tenant *models.Tenant, email *models.Email) error {
+	email.Tenant = tenant.ID
+
+	switch {
+	case email.UserID != "" && len(email.To) > 0:
+		return fmt.Errorf("%w: to and user_id are mutually exclusive", types.ErrInvalidArgument)
+	case email.UserID == "" && len(email.To) == 0:
+		return fmt.Errorf("%w: to or user_id is required", types.ErrInvalidArgument)
+	}
+
+	if email.From != "" && !senderAllowed(tenant, email.From) {
+		return fmt.Errorf("%w: from %q is not a sender identity of tenant %s", types.ErrInvalidArgument, email.From, email.Tenant)
+	}
+
+	if email.Category != "" && !categoryPattern.MatchString(email.Category) {
+		return fmt.Errorf("%w: category must be 1-64 lower-case letters, digits, '_' or '-'", types.ErrInvalidArgument)
+	}
+
+	// Plain-text emails can't carry a tracking pixel or rewritten links
+	email.TrackOpens = email.TrackOpens && email.IsHTML
+	email.TrackClicks = email.TrackClicks && email.IsHTML
+	if (email.TrackOpens || email.TrackClicks) && !s.tracking.enabled() {
+		return fmt.Errorf("%w: tracking is not configured", types.ErrInvalidArgument)
+	}
+
+	if email.Fallback != nil {
+		if err := s.initFallbackPlan(email.Fallback); err != nil {
 			return err
-		}))
+		}
+	}
+
+	if principal != nil {
+		email.CreatedBy = &models.Principal{Kind: principal.Kind, ID: principal.ID, Name: principal.Name, Tenant: principal.Tenant}
+	}
+
+	// Delivery happens in the background, the stored trace context lets it join the request's trace
+	email.TraceContext = tracing.Inject(ctx)
+
+	return nil
+}
+
+// recipientCount returns the addresses an email counts for in the usage, a recipient profile is one
+func recipientCount(email *models.Email) int64 {
+	if email.UserID != "" {
+		return 1
+	}
+
+	return int64(len(email.To) + len(email.CC) + len(email.BCC))
+}
+
+func queuedEvent(email *models.Email) *models.EmailEvent {
+	queued := &models.EmailEvent{EmailID: email.ID, Type: models.EventQueued}
+	if email.UserID != "" {
+		queued.Detail = "for recipient profile " + email.UserID
+	} else {
+		queued.Detail = fmt.Sprintf("for %d recipients", len(email.To)+len(email.CC)+len(email.BCC))
 	}
 
-	// Configure SMTP
-	dialer := gomail.NewDialer(
-		smtpServer.Host,
-		smtpServer.Port,
-		smtpServer.Username,
-		smtpServer.Password,
+	return queued
+}
+
+func (s *EmailService) GetEmail(ctx context.Context, id string) (*models.Email, error) {
+	return s.db.GetTenantEmail(ctx, auth.PrincipalFromContext(ctx).TenantID(), id)
+}
+
+// ListEmailEvents returns the delivery timeline of an email, oldest first
+func (s *EmailService) ListEmailEvents(ctx context.Context, id string) ([]*models.EmailEvent, error) {
+	emailID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid email ID", types.ErrInvalidArgument)
+	}
+
+	email, err := s.db.GetTenantEmail(ctx, auth.PrincipalFromContext(ctx).TenantID(), id)
+	if err != nil {
+		return nil, err
+	}
+	if email == nil {
+		return nil, fmt.Errorf("%w: email %s", types.ErrNotFound, id)
+	}
+
+	return s.db.ListEmailEvents(ctx, emailID)
+}
+
+func (s *EmailService) WatchEmail(ctx context.Context, id string) (<-chan *models.Email, error) {
+	emailID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid email ID", types.ErrInvalidArgument)
+	}
+
+	// Start watching before reading the current state so no change in between is missed
+	tenant := auth.PrincipalFromContext(ctx).TenantID()
+	ctx, cancel := context.WithCancel(ctx)
+	changes := s.db.WatchEmails(ctx, &models.EmailWatchFilter{Tenant: tenant, ID: emailID})
+
+	email, err := s.db.GetTenantEmail(ctx, tenant, id)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if email == nil {
+		cancel()
+		return nil, fmt.Errorf("%w: email %s", types.ErrNotFound, id)
+	}
+	for i := range email.Attachments {
+		email.Attachments[i].Content = nil
+	}
+
+	updates := make(chan *models.Email)
+	go func() {
+		defer close(updates)
+		defer cancel()
+
+		select {
+		case updates <- email:
+		case <-ctx.Done():
+			return
+		}
+
+		last := email.UpdatedAt
+		for change := range changes {
+			// The watch may repeat states at or before the one already delivered
+			if !change.UpdatedAt.After(last) {
+				continue
+			}
+			last = change.UpdatedAt
+
+			select {
+			case updates <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+func (s *EmailService) WatchEmails(ctx context.Context, filter *models.EmailWatchFilter) (<-chan *models.Email, error) {
+	for _, status := range filter.Statuses {
+		switch status {
+		case models.StatusPending, models.StatusSent, models.StatusFailed, models.StatusSuppressed, models.StatusBounced, models.StatusPartial:
+		default:
+			return nil, fmt.Errorf("%w: unknown email status: %s", types.ErrInvalidArgument, status)
+		}
+	}
+
+	scoped := *filter
+	scoped.Tenant = auth.PrincipalFromContext(ctx).TenantID()
+
+	return s.db.WatchEmails(ctx, &scoped), nil
+}
+
+// initFallbackPlan validates the requested steps and puts the plan in its initial state
+func (s *EmailService) initFallbackPlan(plan *models.FallbackPlan) error {
+	if len(plan.Steps) == 0 {
+		return fmt.Errorf("%w: fallback plan requires at least one step", types.ErrInvalidArgument)
+	}
+
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+
+		switch step.Action {
+		case models.FallbackActionResend:
+			if len(step.To) == 0 {
+				return fmt.Errorf("%w: fallback step %d: resend requires at least one address", types.ErrInvalidArgument, i)
+			}
+			for _, address := range step.To {
+				if parsed, err := mail.ParseAddress(address); err != nil || parsed.Address != address {
+					return fmt.Errorf("%w: fallback step %d: invalid address %q", types.ErrInvalidArgument, i, address)
+				}
+			}
+		case models.FallbackActionWebhook:
+			if !s.hasEscalationWebhook(step.Webhook) {
+				return fmt.Errorf("%w: fallback step %d: escalation webhook not configured: %s", types.ErrInvalidArgument, i, step.Webhook)
+			}
+		default:
+			return fmt.Errorf("%w: fallback step %d: unknown action: %s", types.ErrInvalidArgument, i, step.Action)
+		}
+
+		step.Status = models.FallbackStepPending
+	}
+
+	plan.State = models.FallbackStateWaiting
+	plan.CurrentStep = 0
+	if plan.EscalateAfterMinutes > 0 {
+		plan.EscalateAt = time.Now().Add(time.Duration(plan.EscalateAfterMinutes) * time.Minute)
+	}
+
+	return nil
+}
+
+func (s *EmailService) hasEscalationWebhook(name string) bool {
+	for _, webhook := range s.cfg.Escalation.Webhooks {
+		if name == "" || webhook.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sendEmailBatchAsync delivers the emails of a batch a few at a time, so a large batch doesn't open a
+// connection per email at once
+func (s *EmailService) sendEmailBatchAsync(emails []*models.Email) {
+	semaphore := make(chan struct{}, batchSendConcurrency)
+	var wg sync.WaitGroup
+	for _, email := range emails {
+		semaphore <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-semaphore }()
+			s.sendEmailAsync(email)
+		})
+	}
+	wg.Wait()
+}
+
+func (s *EmailService) sendEmailAsync(email *models.Email) {
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), email.TraceContext), "deliver email",
+		attribute.String("email.id", email.ID.Hex()),
+		attribute.String("email.category", email.Category),
 	)
+	defer span.End()
+
+	// Resolve the recipient profile now so updates made after queueing still apply
+	if err := s.resolveRecipient(ctx, email); err != nil {
+		slog.Error("Failed to resolve email recipient", "error", err, "user_id", email.UserID)
+		s.markFailed(ctx, email, err)
+		return
+	}
+
+	dispatch, err := s.filterRecipients(ctx, email)
+	if err != nil {
+		slog.Error("Failed to check recipient preferences", "error", err)
+		s.markFailed(ctx, email, err)
+		return
+	}
+
+	email.Recipients = initRecipients(email)
+	if err = s.db.UpdateEmailRecipients(ctx, email); err != nil {
+		slog.Error("Failed to update email", "error", err)
+	}
+
+	var events []*models.EmailEvent
+	for _, suppressed := range email.Suppressed {
+		events = append(events, &models.EmailEvent{
+			EmailID:   email.ID,
+			Type:      models.EventSuppressed,
+			Recipient: suppressed.Address,
+			Detail:    suppressed.Reason,
+		})
+	}
+	recordEmailEvents(ctx, s.db, events...)
+
+	if dispatch == nil {
+		s.markUndeliverable(ctx, email)
+		return
+	}
 
 	// Send email
-	if err := dialer.DialAndSend(message); err != nil {
-		slog.Error("Failed to send email", "error", err)
+	outcome := s.send(ctx, dispatch)
+
+	now := time.Now()
+	errs := applyOutcome(email.Recipients, outcome, now)
+
+	update := &models.Email{
+		ID:         email.ID,
+		Status:     models.AggregateStatus(email.Recipients),
+		Recipients: email.Recipients,
+	}
+	if len(errs) > 0 {
+		slog.Error("Failed to send email", "email_id", email.ID.Hex(), "error", errors.Join(errs...))
+		update.ErrorMsg = truncate(errors.Join(errs...).Error(), 1000)
+	}
+	if update.Status != models.StatusFailed {
+		update.SentAt = now
+	}
+	span.SetAttributes(attribute.String("email.status", string(update.Status)))
+
+	if _, err = s.db.UpdateEmailDelivery(ctx, update); err != nil {
+		slog.Error("Failed to update email", "error", err)
+	}
+
+	event := &models.WebhookEvent{
+		Type:     models.WebhookEmailSent,
+		Tenant:   email.Tenant,
+		EmailID:  email.ID.Hex(),
+		Status:   string(update.Status),
+		Category: email.Category,
+		Detail:   update.ErrorMsg,
+	}
+	if update.Status == models.StatusFailed {
+		event.Type = models.WebhookEmailFailed
+		s.recordRetry(ctx, email)
+	}
+	publishWebhookEvent(ctx, s.db, event)
+}
+
+// ResendEmail delivers a copy of the email to the alternate addresses of a fallback step. They go
+// through the tenant's suppressions, preferences and quota like the email's own recipients, and are
+// added to its recipients with kind "fallback".
+func (s *EmailService) ResendEmail(ctx context.Context, email *models.Email, to []string) error {
+	resend := *email
+	resend.UserID, resend.To, resend.CC, resend.BCC = "", to, nil, nil
+	resend.Suppressed, resend.Recipients = nil, nil
+
+	dispatch, err := s.filterRecipients(ctx, &resend)
+	if err != nil {
+		return err
+	}
+
+	recipients := initRecipients(&resend)
+	for i := range recipients {
+		recipients[i].Kind = models.RecipientFallback
+	}
 
-		// Update status to failed
-		_, err = s.db.UpdateEmailFail(ctx, &models.Email{
-			ID:       email.ID,
-			ErrorMsg: err.Error(),
+	var events []*models.EmailEvent
+	for _, suppressed := range resend.Suppressed {
+		events = append(events, &models.EmailEvent{
+			EmailID:   email.ID,
+			Type:      models.EventSuppressed,
+			Recipient: suppressed.Address,
+			Detail:    suppressed.Reason,
 		})
-		if err != nil {
-			slog.Error("Failed to update email", "error", err)
+	}
+	recordEmailEvents(ctx, s.db, events...)
+
+	outcome := &sendOutcome{failures: make(map[string]error), results: make(map[string]*SendResult)}
+	switch {
+	case dispatch == nil:
+		err = fmt.Errorf("every fallback address is suppressed or opted out")
+	default:
+		if err = s.reserveResend(ctx, email.Tenant, dispatch); err != nil {
+			for _, address := range dispatch.To {
+				outcome.failures[address] = err
+			}
+		} else {
+			outcome = s.send(ctx, dispatch)
+		}
+	}
+
+	errs := applyOutcome(recipients, outcome, time.Now())
+	if dbErr := s.db.AddEmailRecipients(ctx, email.ID, recipients); dbErr != nil {
+		slog.Error("Failed to update email", "error", dbErr)
+	}
+
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(recipients, func(recipient models.EmailRecipient) bool { return recipient.Status == models.RecipientSent }) {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// reserveResend counts a fallback resend against the tenant's monthly quota
+func (s *EmailService) reserveResend(ctx context.Context, tenantID string, dispatch *models.Email) error {
+	tenant, err := s.tenants.Lookup(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if tenant == nil {
+		return fmt.Errorf("unknown tenant %s", tenantID)
+	}
+
+	_, err = s.limiter.ReserveQuota(ctx, tenant, 1, int64(len(dispatch.To)))
+	return err
+}
+
+// applyOutcome records the outcome of a send on the pending recipients and returns the errors of
+// the ones that failed
+func applyOutcome(recipients []models.EmailRecipient, outcome *sendOutcome, now time.Time) []error {
+	var errs []error
+	for i := range recipients {
+		recipient := &recipients[i]
+		if recipient.Status != models.RecipientPending {
+			continue
 		}
 
+		recipient.UpdatedAt = now
+		if result := outcome.results[recipient.Address]; result != nil {
+			recipient.Server, recipient.Sender = result.Server, result.Sender
+		}
+		if err := outcome.failures[recipient.Address]; err != nil {
+			recipient.Status = models.RecipientFailed
+			recipient.SMTPCode, recipient.SMTPResponse = smtpReply(err)
+			errs = append(errs, fmt.Errorf("%s: %w", recipient.Address, err))
+			continue
+		}
+
+		recipient.Status = models.RecipientSent
+		recipient.SMTPCode = 250
+		recipient.SentAt = now
+	}
+
+	return errs
+}
+
+// recordRetry notes on the timeline that the email's fallback plan will take over, right away after
+// a permanent failure and at the escalation deadline after a transient one
+func (s *EmailService) recordRetry(ctx context.Context, email *models.Email) {
+	plan := email.Fallback
+	if plan == nil || plan.State != models.FallbackStateWaiting {
 		return
 	}
 
-	// Update status to sent
-	_, err := s.db.UpdateEmailSent(ctx, &models.Email{
-		ID:     email.ID,
-		SentAt: time.Now(),
+	detail := fmt.Sprintf("fallback plan escalates to step 1 of %d (%s)", len(plan.Steps), plan.Steps[0].Action)
+	if !failedPermanently(email.Recipients) {
+		if plan.EscalateAt.IsZero() {
+			return
+		}
+		detail += " at " + plan.EscalateAt.UTC().Format(time.RFC3339)
+	}
+
+	metrics.CountRetry(metrics.RetryEmailFallback)
+	recordEmailEvents(ctx, s.db, &models.EmailEvent{
+		EmailID: email.ID,
+		Type:    models.EventRetryScheduled,
+		Detail:  detail,
+	})
+}
+
+// failedPermanently reports whether every failed recipient was refused with a 5xx reply. Deferrals
+// and connection errors may succeed later.
+func failedPermanently(recipients []models.EmailRecipient) bool {
+	for _, recipient := range recipients {
+		if recipient.Status == models.RecipientFailed && recipient.SMTPCode < 500 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sendOutcome is what happened to each recipient of a send
+type sendOutcome struct {
+	failures map[string]error       // Recipients the email wasn't delivered to
+	results  map[string]*SendResult // SMTP transaction of each recipient that reached a server
+}
+
+// send delivers the email and returns the outcome for each recipient. Bulk and tracked emails get a
+// separate message per recipient so each one carries its own signed List-Unsubscribe link, tracking
+// pixel and tracked links.
+func (s *EmailService) send(ctx context.Context, email *models.Email) *sendOutcome {
+	outcome := &sendOutcome{failures: make(map[string]error), results: make(map[string]*SendResult)}
+	recipients := slices.Concat(email.To, email.CC, email.BCC)
+	bulk := s.unsubscribe.enabled() && s.cfg.Unsubscribe.IsBulk(email.Category)
+
+	var personalized []string
+	if bulk {
+		personalized = append(personalized, "unsubscribe headers")
+	}
+	if email.TrackOpens {
+		personalized = append(personalized, "an open tracking pixel")
+	}
+	if email.TrackClicks {
+		personalized = append(personalized, "tracked links")
+	}
+
+	rendering := &models.EmailEvent{EmailID: email.ID, Type: models.EventRendering, Detail: fmt.Sprintf("%d recipients", len(recipients))}
+	if len(personalized) > 0 {
+		rendering.Detail += ", one message per recipient with " + strings.Join(personalized, ", ")
+	}
+	recordEmailEvents(ctx, s.db, rendering)
+
+	if len(personalized) == 0 {
+		result, err := s.sender.Send(ctx, email, nil)
+		s.recordSend(ctx, email, outcome, result, err)
+		return outcome
+	}
+
+	for _, address := range recipients {
+		single := *email
+		single.To, single.CC, single.BCC = []string{address}, nil, nil
+		if email.TrackClicks {
+			single.Body = s.tracking.rewriteLinks(single.Body, email.ID, address)
+		}
+		if email.TrackOpens {
+			single.Body = injectOpenPixel(single.Body, s.tracking.openURL(email.ID, address))
+		}
+
+		result, err := s.sender.Send(ctx, &single, s.unsubscribe.headers(email, address))
+		s.recordSend(ctx, &single, outcome, result, err)
+	}
+
+	return outcome
+}
+
+// recordSend adds the recipients of one SMTP transaction to the outcome, suppresses the ones refused
+// permanently and records the transaction on the timeline
+func (s *EmailService) recordSend(ctx context.Context, email *models.Email, outcome *sendOutcome, result *SendResult, sendErr error) {
+	recipients := slices.Concat(email.To, email.CC, email.BCC)
+	failures := outcome.failures
+	if result != nil {
+		for _, address := range recipients {
+			outcome.results[address] = result
+		}
+	}
+
+	var events []*models.EmailEvent
+	var refused map[string]error
+	if result != nil {
+		refused = result.Refused
+
+		dispatched := &models.EmailEvent{EmailID: email.ID, Type: models.EventDispatched, Server: result.Server}
+		if len(recipients) == 1 {
+			dispatched.Recipient = recipients[0]
+		} else {
+			dispatched.Detail = fmt.Sprintf("%d recipients", len(recipients))
+		}
+		events = append(events, dispatched)
+	}
+
+	for address, err := range refused {
+		failures[address] = err
+		s.suppressRefusedRecipient(ctx, email, address, err)
+
+		code, response := smtpReply(err)
+		events = append(events, &models.EmailEvent{
+			EmailID:   email.ID,
+			Type:      models.EventRefused,
+			Recipient: address,
+			Server:    result.Server,
+			SMTPCode:  code,
+			Detail:    response,
+		})
+	}
+
+	if sendErr == nil {
+		events = append(events, &models.EmailEvent{
+			EmailID:  email.ID,
+			Type:     models.EventAccepted,
+			Server:   result.Server,
+			SMTPCode: 250,
+			QueueID:  result.QueueID,
+			Detail:   result.Reply,
+		})
+		recordEmailEvents(ctx, s.db, events...)
+		return
+	}
+
+	failed := &models.EmailEvent{EmailID: email.ID, Type: models.EventFailed, Detail: sendErr.Error()}
+	if result != nil {
+		failed.Server = result.Server
+	}
+	if len(recipients) == 1 {
+		failed.Recipient = recipients[0]
+	}
+	failed.SMTPCode, _ = smtpReply(sendErr)
+	recordEmailEvents(ctx, s.db, append(events, failed)...)
+
+	// The whole message failed, so did every recipient that wasn't refused individually.
+	// With a single recipient the reply is about that address and can be acted on.
+	for _, address := range recipients {
+		if _, ok := refused[address]; !ok {
+			failures[address] = sendErr
+		}
+	}
+	if len(recipients) == 1 && len(refused) == 0 {
+		s.suppressRefusedRecipient(ctx, email, recipients[0], sendErr)
+	}
+}
+
+// initRecipients lists every address of the email with its initial delivery state
+func initRecipients(email *models.Email) []models.EmailRecipient {
+	now := time.Now()
+	suppressed := make(map[string]string, len(email.Suppressed))
+	for _, recipient := range email.Suppressed {
+		suppressed[recipient.Address] = recipient.Reason
+	}
+
+	var recipients []models.EmailRecipient
+	for _, list := range []struct {
+		kind      models.RecipientKind
+		addresses []string
+	}{
+		{models.RecipientTo, email.To},
+		{models.RecipientCC, email.CC},
+		{models.RecipientBCC, email.BCC},
+	} {
+		for _, address := range list.addresses {
+			recipient := models.EmailRecipient{
+				Address:   address,
+				Kind:      list.kind,
+				Status:    models.RecipientPending,
+				UpdatedAt: now,
+			}
+			if reason, ok := suppressed[address]; ok {
+				recipient.Status = models.RecipientSuppressed
+				recipient.Reason = reason
+			}
+
+			recipients = append(recipients, recipient)
+		}
+	}
+
+	return recipients
+}
+
+// smtpReply extracts the reply code and text when err is an SMTP reply
+func smtpReply(err error) (int, string) {
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code, truncate(smtpErr.Msg, 1000)
+	}
+
+	return 0, truncate(err.Error(), 1000)
+}
+
+// markUndeliverable records an email with no recipient left. Opt-outs are a deliberate choice and
+// mark it suppressed; if any address is on the suppression list the email fails instead.
+func (s *EmailService) markUndeliverable(ctx context.Context, email *models.Email) {
+	for _, suppressed := range email.Suppressed {
+		if strings.HasPrefix(suppressed.Reason, "suppressed:") {
+			slog.Info("Email failed, all recipients are suppressed", "email_id", email.ID.Hex())
+			s.markFailed(ctx, email, fmt.Errorf("all recipients are on the suppression list"))
+			return
+		}
+	}
+
+	slog.Info("Email suppressed by recipient preferences", "email_id", email.ID.Hex(), "category", email.Category)
+
+	_, err := s.db.UpdateEmailSuppressed(ctx, &models.Email{
+		ID:         email.ID,
+		ErrorMsg:   "all recipients opted out of category " + email.Category,
+		Suppressed: email.Suppressed,
+		Recipients: email.Recipients,
+	})
+	if err != nil {
+		slog.Error("Failed to update email", "error", err)
+	}
+}
+
+func (s *EmailService) markFailed(ctx context.Context, email *models.Email, cause error) {
+	recordEmailEvents(ctx, s.db, &models.EmailEvent{EmailID: email.ID, Type: models.EventFailed, Detail: cause.Error()})
+
+	// Update status to failed
+	_, err := s.db.UpdateEmailFail(ctx, &models.Email{
+		ID:       email.ID,
+		ErrorMsg: cause.Error(),
 	})
 	if err != nil {
 		slog.Error("Failed to update email", "error", err)
 	}
+
+	publishWebhookEvent(ctx, s.db, &models.WebhookEvent{
+		Type:     models.WebhookEmailFailed,
+		Tenant:   email.Tenant,
+		EmailID:  email.ID.Hex(),
+		Status:   string(models.StatusFailed),
+		Category: email.Category,
+		Detail:   cause.Error(),
+	})
+}
+
+// resolveRecipient fills in To from the email's recipient profile in its tenant, if it targets one
+func (s *EmailService) resolveRecipient(ctx context.Context, email *models.Email) error {
+	if email.UserID == "" {
+		return nil
+	}
+
+	recipient, err := s.db.GetRecipientByUserID(ctx, email.Tenant, email.UserID)
+	if err != nil {
+		return err
+	}
+	if recipient == nil || recipient.PrimaryEmail() == "" {
+		return fmt.Errorf("recipient %s has no email address", email.UserID)
+	}
+
+	email.To = []string{recipient.PrimaryEmail()}
+
+	return nil
+}
+
+// filterRecipients returns a copy of the email without suppressed addresses and addresses that opted
+// out of its category, recording them in email.Suppressed. Returns nil when no recipient is left.
+func (s *EmailService) filterRecipients(ctx context.Context, email *models.Email) (*models.Email, error) {
+	addresses := make([]string, 0, len(email.To)+len(email.CC)+len(email.BCC))
+	for _, list := range [][]string{email.To, email.CC, email.BCC} {
+		for _, address := range list {
+			addresses = append(addresses, normalizeAddress(address))
+		}
+	}
+
+	suppressions, err := s.db.GetActiveSuppressions(ctx, email.Tenant, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	var preferences map[string]*models.Preference
+	if email.Category != "" && !s.cfg.Preferences.IsMandatory(email.Category) {
+		if preferences, err = s.db.GetPreferences(ctx, email.Tenant, addresses); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(suppressions) == 0 && len(preferences) == 0 {
+		return email, nil
+	}
+
+	keep := func(list []string) []string {
+		var kept []string
+		for _, address := range list {
+			normalized := normalizeAddress(address)
+
+			if suppression := suppressions[normalized]; suppression != nil {
+				email.Suppressed = append(email.Suppressed, models.SuppressedRecipient{
+					Address: address,
+					Reason:  "suppressed: " + string(suppression.Reason),
+				})
+				continue
+			}
+
+			if preference := preferences[normalized]; preference != nil && !preference.Allows(email.Category, models.ChannelEmail) {
+				email.Suppressed = append(email.Suppressed, models.SuppressedRecipient{
+					Address: address,
+					Reason:  "opted out of " + email.Category,
+				})
+				continue
+			}
+
+			kept = append(kept, address)
+		}
+		return kept
+	}
+
+	dispatch := *email
+	dispatch.To = keep(email.To)
+	dispatch.CC = keep(email.CC)
+	dispatch.BCC = keep(email.BCC)
+
+	if len(dispatch.To)+len(dispatch.CC)+len(dispatch.BCC) == 0 {
+		return nil, nil
+	}
+
+	return &dispatch, nil
+}
+
+// suppressRefusedRecipient adds a recipient to the suppression list when the SMTP server refused
+// it permanently
+func (s *EmailService) suppressRefusedRecipient(ctx context.Context, email *models.Email, address string, sendErr error) {
+	var smtpErr *textproto.Error
+	if !errors.As(sendErr, &smtpErr) || !isHardBounceCode(smtpErr.Code) {
+		return
+	}
+
+	err := s.db.UpsertSuppressions(ctx, []*models.Suppression{{
+		Tenant:  email.Tenant,
+		Address: normalizeAddress(address),
+		Reason:  models.SuppressionHardBounce,
+		Source:  models.SuppressionSourceSMTP,
+		Detail:  truncate(smtpErr.Error(), 1000),
+	}})
+	if err != nil {
+		slog.Error("Failed to suppress refused recipient", "error", err)
+		return
+	}
+
+	slog.Info("Suppressed hard-bounced recipient", "email_id", email.ID.Hex(), "code", smtpErr.Code)
+}
+
+// isHardBounceCode reports whether an SMTP reply code means the mailbox doesn't exist or won't accept mail
+func isHardBounceCode(code int) bool {
+	switch code {
+	case 550, 551, 553:
+		return true
+	default:
+		return false
+	}
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+
+	return value[:limit]
+}
+
+// normalizeAddress is the form addresses are stored in for preference lookups
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
 }
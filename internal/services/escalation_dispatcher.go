@@ -0,0 +1,241 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/metrics"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/tracing"
+	"github.com/aarondever/notiflow/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// How long a claimed email stays locked to one dispatcher before another instance may pick it up
+const escalationLease = 5 * time.Minute
+
+// EscalationDispatcher drives the fallback plans persisted on emails
+type EscalationDispatcher struct {
+	db         *database.Database
+	cfg        *config.Config
+	emails     types.EmailService
+	httpClient *http.Client
+}
+
+func NewEscalationDispatcher(db *database.Database, cfg *config.Config, emails types.EmailService) *EscalationDispatcher {
+	return &EscalationDispatcher{
+		db:         db,
+		cfg:        cfg,
+		emails:     emails,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type escalationAlert struct {
+	Event   string    `json:"event"`
+	EmailID string    `json:"email_id"`
+	Subject string    `json:"subject"`
+	To      []string  `json:"to"`
+	Status  string    `json:"status"`
+	Reason  string    `json:"reason"`
+	Step    int       `json:"step"`
+	Time    time.Time `json:"time"`
+}
+
+func (d *EscalationDispatcher) Run(ctx context.Context) {
+	interval := time.Duration(d.cfg.Escalation.CheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	slog.Info("Starting escalation dispatcher", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchDue processes every email whose fallback plan is due
+func (d *EscalationDispatcher) dispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		email, err := d.db.ClaimEmailForEscalation(ctx, time.Now(), escalationLease)
+		if err != nil || email == nil {
+			return
+		}
+
+		d.escalate(ctx, email)
+	}
+}
+
+func (d *EscalationDispatcher) escalate(ctx context.Context, email *models.Email) {
+	ctx, span := tracing.Start(tracing.Extract(ctx, email.TraceContext), "escalate email",
+		attribute.String("email.id", email.ID.Hex()),
+	)
+	defer span.End()
+
+	plan := email.Fallback
+
+	if plan.State == models.FallbackStateWaiting {
+		plan.State = models.FallbackStateEscalating
+		plan.EscalatedAt = time.Now()
+		switch email.Status {
+		case models.StatusFailed:
+			plan.Reason = fmt.Sprintf("primary delivery failed: %s", email.ErrorMsg)
+		case models.StatusBounced:
+			plan.Reason = "primary delivery bounced"
+		default:
+			plan.Reason = fmt.Sprintf("not sent after %d minutes", plan.EscalateAfterMinutes)
+		}
+
+		slog.Info("Escalating email", "email_id", email.ID.Hex(), "reason", plan.Reason)
+	}
+
+	// Persist after every step so a restart resumes from the step that was interrupted
+	for plan.CurrentStep < len(plan.Steps) && plan.State == models.FallbackStateEscalating {
+		step := &plan.Steps[plan.CurrentStep]
+		metrics.CountRetry(metrics.RetryEscalationStep)
+
+		var err error
+		switch step.Action {
+		case models.FallbackActionResend:
+			err = d.resend(ctx, email, step.To)
+		case models.FallbackActionWebhook:
+			err = d.alert(ctx, email, plan.CurrentStep)
+		default:
+			err = fmt.Errorf("unknown fallback action: %s", step.Action)
+		}
+
+		step.CompletedAt = time.Now()
+
+		escalated := &models.EmailEvent{
+			EmailID: email.ID,
+			Type:    models.EventEscalated,
+			Detail:  fmt.Sprintf("step %d of %d (%s) succeeded", plan.CurrentStep+1, len(plan.Steps), step.Action),
+		}
+		if err != nil {
+			escalated.Detail = fmt.Sprintf("step %d of %d (%s) failed: %s", plan.CurrentStep+1, len(plan.Steps), step.Action, err)
+		}
+		recordEmailEvents(ctx, d.db, escalated)
+
+		if err != nil {
+			slog.Error("Fallback step failed", "email_id", email.ID.Hex(), "step", plan.CurrentStep, "action", step.Action, "error", err)
+			step.Status = models.FallbackStepFailed
+			step.Error = err.Error()
+		} else {
+			step.Status = models.FallbackStepSucceeded
+			if step.Action == models.FallbackActionResend {
+				plan.State = models.FallbackStateResolved
+			}
+		}
+
+		plan.CurrentStep++
+
+		if plan.State == models.FallbackStateEscalating && plan.CurrentStep == len(plan.Steps) {
+			plan.State = models.FallbackStateExhausted
+		}
+
+		if err = d.db.UpdateEmailFallback(ctx, email.ID, plan); err != nil {
+			return
+		}
+	}
+
+	// Once resolved, the remaining steps will never run
+	if plan.State == models.FallbackStateResolved {
+		for i := plan.CurrentStep; i < len(plan.Steps); i++ {
+			plan.Steps[i].Status = models.FallbackStepSkipped
+		}
+
+		_ = d.db.UpdateEmailFallback(ctx, email.ID, plan)
+	}
+}
+
+// resend delivers a copy of the email to the alternate recipients only
+func (d *EscalationDispatcher) resend(ctx context.Context, email *models.Email, to []string) error {
+	return d.emails.ResendEmail(ctx, email, to)
+}
+
+func (d *EscalationDispatcher) alert(ctx context.Context, email *models.Email, step int) error {
+	webhook, ok := d.webhook(email.Fallback.Steps[step].Webhook)
+	if !ok {
+		return fmt.Errorf("escalation webhook not configured: %s", email.Fallback.Steps[step].Webhook)
+	}
+
+	return postSignedJSON(ctx, d.httpClient, webhook, escalationAlert{
+		Event:   "email.escalated",
+		EmailID: email.ID.Hex(),
+		Subject: email.Subject,
+		To:      email.To,
+		Status:  string(email.Status),
+		Reason:  email.Fallback.Reason,
+		Step:    step,
+		Time:    time.Now(),
+	})
+}
+
+// webhook looks up a configured escalation webhook by name, an empty name selects the first one
+func (d *EscalationDispatcher) webhook(name string) (config.WebhookConfig, bool) {
+	for _, webhook := range d.cfg.Escalation.Webhooks {
+		if name == "" || webhook.Name == name {
+			return webhook, true
+		}
+	}
+
+	return config.WebhookConfig{}, false
+}
+
+// postSignedJSON POSTs payload to the webhook, signing the body with the webhook secret when set
+func postSignedJSON(ctx context.Context, client *http.Client, webhook config.WebhookConfig, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "notiflow")
+
+	if webhook.Secret != "" {
+		request.Header.Set("X-Notiflow-Signature", signPayload(webhook.Secret, body))
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with %d", webhook.Name, response.StatusCode)
+	}
+
+	return nil
+}
+
+// signPayload returns the X-Notiflow-Signature value for a request body
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+// Longest a readiness check waits for MongoDB
+const healthCheckTimeout = 2 * time.Second
+
+type HealthService struct {
+	db     *database.Database
+	cfg    *config.Config
+	sender *SMTPSender
+
+	mu           sync.RWMutex
+	smtpServers  []models.SMTPServerHealth // Latest probe of each server
+	shuttingDown atomic.Bool
+}
+
+func NewHealthService(db *database.Database, cfg *config.Config, sender *SMTPSender) types.HealthService {
+	return &HealthService{
+		db:     db,
+		cfg:    cfg,
+		sender: sender,
+	}
+}
+
+func (s *HealthService) Run(ctx context.Context) {
+	interval := time.Duration(s.cfg.Health.SMTPProbeInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.probeSMTP()
+
+		select {
+		case <-ctx.Done():
+			s.shuttingDown.Store(true)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *HealthService) probeSMTP() {
+	if s.sender.ServerCount() == 0 {
+		return
+	}
+
+	timeout := time.Duration(s.cfg.Health.SMTPProbeTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	now := time.Now()
+	var servers []models.SMTPServerHealth
+	for name, err := range s.sender.Probe(timeout) {
+		server := models.SMTPServerHealth{Name: name, Status: models.HealthUp, CheckedAt: now}
+		if err != nil {
+			slog.Warn("SMTP server probe failed", "server", name, "error", err)
+			server.Status, server.Error = models.HealthDown, err.Error()
+		}
+		servers = append(servers, server)
+	}
+	slices.SortFunc(servers, func(a, b models.SMTPServerHealth) int { return strings.Compare(a.Name, b.Name) })
+
+	s.mu.Lock()
+	s.smtpServers = servers
+	s.mu.Unlock()
+}
+
+func (s *HealthService) Readiness(ctx context.Context) *models.HealthReport {
+	report := &models.HealthReport{
+		Status: "ready",
+		Checks: map[string]*models.HealthCheck{
+			"mongodb": s.checkMongo(ctx),
+			"smtp":    s.checkSMTP(),
+			"backlog": s.checkBacklog(ctx),
+		},
+	}
+
+	for _, check := range report.Checks {
+		if check.Status == models.HealthDown {
+			report.Status = "not_ready"
+		}
+	}
+	if s.shuttingDown.Load() {
+		report.Status = "not_ready"
+	}
+
+	return report
+}
+
+func (s *HealthService) checkMongo(ctx context.Context) *models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	check := &models.HealthCheck{Status: models.HealthUp, CheckedAt: start}
+	if err := s.db.Ping(ctx); err != nil {
+		check.Status, check.Error = models.HealthDown, err.Error()
+	}
+	check.LatencyMs = time.Since(start).Milliseconds()
+
+	return check
+}
+
+// checkSMTP reports the latest probes: down when no server is reachable, degraded when some aren't
+func (s *HealthService) checkSMTP() *models.HealthCheck {
+	s.mu.RLock()
+	servers := s.smtpServers
+	s.mu.RUnlock()
+
+	check := &models.HealthCheck{Status: models.HealthUnknown, Servers: servers}
+	if s.sender.ServerCount() == 0 {
+		check.Error = "no SMTP servers configured"
+		return check
+	}
+
+	var down int
+	for _, server := range servers {
+		check.CheckedAt = server.CheckedAt
+		if server.Status == models.HealthDown {
+			down++
+		}
+	}
+
+	switch {
+	case len(servers) == 0:
+	case down == len(servers):
+		check.Status, check.Error = models.HealthDown, "no SMTP server is reachable"
+	case down > 0:
+		check.Status = models.HealthDegraded
+	default:
+		check.Status = models.HealthUp
+	}
+
+	return check
+}
+
+// checkBacklog counts emails and webhook deliveries waiting longer than the backlog age. It only
+// fails readiness when a maximum backlog is configured.
+func (s *HealthService) checkBacklog(ctx context.Context) *models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	now := time.Now()
+	before := now.Add(-time.Duration(s.cfg.Health.BacklogAge) * time.Second)
+	check := &models.HealthCheck{Status: models.HealthUp, Backlog: &models.Backlog{}, CheckedAt: now}
+
+	var err error
+	if check.Backlog.StalePendingEmails, err = s.db.CountStalePendingEmails(ctx, before); err == nil {
+		check.Backlog.OverdueWebhookDeliveries, err = s.db.CountOverdueWebhookDeliveries(ctx, before)
+	}
+	if err != nil {
+		// MongoDB being unavailable already fails its own check
+		check.Status, check.Error, check.Backlog = models.HealthUnknown, err.Error(), nil
+		return check
+	}
+
+	backlog := check.Backlog.StalePendingEmails + check.Backlog.OverdueWebhookDeliveries
+	switch {
+	case s.cfg.Health.MaxBacklog > 0 && backlog > int64(s.cfg.Health.MaxBacklog):
+		check.Status, check.Error = models.HealthDown, "dispatcher backlog exceeds the maximum"
+	case backlog > 0:
+		check.Status = models.HealthDegraded
+	}
+
+	return check
+}
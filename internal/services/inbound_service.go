@@ -0,0 +1,402 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/bounce"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/inbound"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// InboundService handles mail sent back to notiflow, from the inbound SMTP listener or the
+// bounce mailbox
+type InboundService struct {
+	db         *database.Database
+	cfg        *config.Config
+	httpClient *http.Client
+}
+
+func NewInboundService(db *database.Database, cfg *config.Config) types.InboundService {
+	return &InboundService{
+		db:         db,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type replyEvent struct {
+	Event      string    `json:"event"`
+	Tenant     string    `json:"tenant,omitempty"`   // Tenant of the email replied to, empty with the email ID
+	EmailID    string    `json:"email_id,omitempty"` // Empty when the reply couldn't be matched to an email
+	MessageID  string    `json:"message_id"`
+	InReplyTo  string    `json:"in_reply_to,omitempty"`
+	References []string  `json:"references,omitempty"`
+	From       string    `json:"from"`
+	To         []string  `json:"to"`
+	Subject    string    `json:"subject"`
+	Text       string    `json:"text,omitempty"`
+	HTML       string    `json:"html,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// Receive routes a message to bounce, complaint or reply handling. Messages that can't be matched
+// to an email are logged and dropped, only database and webhook errors are returned.
+func (s *InboundService) Receive(ctx context.Context, from string, to []string, raw []byte) error {
+	if handled, err := s.receiveReport(ctx, to, raw); handled {
+		return err
+	}
+
+	// Bounces from MTAs that don't send RFC 3464 reports come back as plain messages with an empty
+	// envelope sender, and nobody replies to a VERP bounce address. Don't mistake them for replies.
+	if from == "" || slices.ContainsFunc(to, isVERPAddress) {
+		slog.Warn("Dropping non-standard bounce message", "from", from, "to", to)
+		return nil
+	}
+
+	reply, err := inbound.ParseReply(bytes.NewReader(raw))
+	if err != nil {
+		slog.Warn("Failed to parse inbound message", "error", err)
+		return nil
+	}
+
+	return s.routeReply(ctx, to, reply)
+}
+
+func (s *InboundService) recordBounces(ctx context.Context, report *bounce.Report) error {
+	emailID, verpRecipient, ok := correlate(report.EnvelopeTo, report.OriginalMessageID)
+	if !ok {
+		slog.Warn("Bounce does not reference a notiflow email", "message_id", report.OriginalMessageID)
+		return nil
+	}
+
+	email, err := s.db.GetEmailByID(ctx, emailID.Hex())
+	if err != nil {
+		return err
+	}
+	if email == nil {
+		slog.Warn("Bounce references an unknown email", "email_id", emailID.Hex())
+		return nil
+	}
+
+	recipients := make([]string, 0, len(email.To)+len(email.CC)+len(email.BCC))
+	for _, address := range slices.Concat(email.To, email.CC, email.BCC) {
+		recipients = append(recipients, normalizeAddress(address))
+	}
+
+	now := time.Now()
+	var bounces []models.Bounce
+	var suppressions []*models.Suppression
+	for _, status := range report.Recipients {
+		if status.Action != "failed" && status.Action != "delayed" {
+			continue
+		}
+
+		// The reported address differs from the one sent to when the recipient forwards their mail
+		address := status.FinalRecipient
+		if !slices.Contains(recipients, address) && slices.Contains(recipients, status.OriginalRecipient) {
+			address = status.OriginalRecipient
+		}
+		if !slices.Contains(recipients, address) && slices.Contains(recipients, verpRecipient) {
+			address = verpRecipient
+		}
+		// Long recipients are left out of the VERP address, a sole recipient is still known
+		if !slices.Contains(recipients, address) && len(recipients) == 1 {
+			address = recipients[0]
+		}
+		// Anyone can send a report naming an email, only its own recipients may be bounced
+		if !slices.Contains(recipients, address) {
+			slog.Warn("Dropping bounce for an address the email wasn't sent to", "email_id", email.ID.Hex(), "address", address)
+			continue
+		}
+
+		if hasBounce(email.Bounces, address, status) {
+			continue
+		}
+
+		bounces = append(bounces, models.Bounce{
+			Address:        address,
+			Action:         status.Action,
+			Status:         status.Status,
+			DiagnosticCode: truncate(status.DiagnosticCode, 1000),
+			Permanent:      status.IsPermanent(),
+			ReportedAt:     now,
+		})
+
+		if status.IsPermanent() {
+			suppressions = append(suppressions, &models.Suppression{
+				Tenant:  email.Tenant,
+				Address: address,
+				Reason:  models.SuppressionHardBounce,
+				Source:  models.SuppressionSourceBounce,
+				Detail:  truncate(status.Status+" "+status.DiagnosticCode, 1000),
+			})
+		}
+	}
+
+	if len(bounces) == 0 {
+		return nil
+	}
+
+	if len(suppressions) > 0 {
+		if err = s.db.UpsertSuppressions(ctx, suppressions); err != nil {
+			return err
+		}
+	}
+
+	// Attach the latest report to each recipient, by the address it was stored with
+	latest := make(map[string]models.Bounce)
+	for _, reported := range bounces {
+		for _, recipient := range email.Recipients {
+			if normalizeAddress(recipient.Address) == reported.Address {
+				latest[recipient.Address] = reported
+			}
+		}
+	}
+
+	updated, err := s.db.AddEmailBounces(ctx, email.ID, bounces, latest)
+	if err != nil {
+		return err
+	}
+	if updated != nil {
+		email = updated
+	}
+
+	events := make([]*models.EmailEvent, len(bounces))
+	for i, reported := range bounces {
+		events[i] = &models.EmailEvent{
+			EmailID:   email.ID,
+			Type:      models.EventBounced,
+			Recipient: reported.Address,
+			Detail:    strings.TrimSpace(reported.Action + " " + reported.Status + " " + reported.DiagnosticCode),
+		}
+	}
+	recordEmailEvents(ctx, s.db, events...)
+
+	for _, reported := range bounces {
+		publishWebhookEvent(ctx, s.db, &models.WebhookEvent{
+			Type:      models.WebhookEmailBounced,
+			Tenant:    email.Tenant,
+			EmailID:   email.ID.Hex(),
+			Recipient: reported.Address,
+			Status:    string(email.Status),
+			Category:  email.Category,
+			Detail:    strings.TrimSpace(reported.Status + " " + reported.DiagnosticCode),
+		})
+	}
+
+	slog.Info("Recorded email bounces", "email_id", email.ID.Hex(), "bounces", len(bounces), "suppressed", len(suppressions))
+	return nil
+}
+
+// recordComplaint suppresses the complaining recipient, mailbox providers penalise senders who
+// keep mailing people that marked them as spam
+func (s *InboundService) recordComplaint(ctx context.Context, report *bounce.FeedbackReport) error {
+	emailID, verpRecipient, ok := correlate(slices.Concat(report.EnvelopeTo, []string{report.OriginalMailFrom}), report.OriginalMessageID)
+	if !ok {
+		slog.Warn("Feedback report does not reference a notiflow email", "message_id", report.OriginalMessageID)
+		return nil
+	}
+
+	email, err := s.db.GetEmailByID(ctx, emailID.Hex())
+	if err != nil {
+		return err
+	}
+	if email == nil {
+		slog.Warn("Feedback report references an unknown email", "email_id", emailID.Hex())
+		return nil
+	}
+
+	recipients := make([]string, 0, len(email.To)+len(email.CC)+len(email.BCC))
+	for _, address := range slices.Concat(email.To, email.CC, email.BCC) {
+		recipients = append(recipients, normalizeAddress(address))
+	}
+
+	// Providers usually redact the recipient, fall back to the VERP address or a sole recipient
+	address := normalizeAddress(report.OriginalRcptTo)
+	if address == "" {
+		address = verpRecipient
+	}
+	if address == "" && len(recipients) == 1 {
+		address = recipients[0]
+	}
+	// Anyone can send a report naming an email, only its own recipients may be suppressed
+	if address != "" && !slices.Contains(recipients, address) {
+		slog.Warn("Recording complaint without the address the email wasn't sent to", "email_id", email.ID.Hex(), "address", address)
+		address = ""
+	}
+
+	if address != "" && report.FeedbackType != "not-spam" {
+		err = s.db.UpsertSuppressions(ctx, []*models.Suppression{{
+			Tenant:  email.Tenant,
+			Address: address,
+			Reason:  models.SuppressionComplaint,
+			Source:  models.SuppressionSourceFBL,
+			Detail:  truncate(strings.TrimSpace(report.FeedbackType+" "+report.UserAgent), 1000),
+		}})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.db.AddEmailComplaint(ctx, email.ID, models.Complaint{
+		Address:      address,
+		FeedbackType: report.FeedbackType,
+		UserAgent:    report.UserAgent,
+		ReportedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	recordEmailEvents(ctx, s.db, &models.EmailEvent{
+		EmailID:   email.ID,
+		Type:      models.EventComplained,
+		Recipient: address,
+		Detail:    strings.TrimSpace(report.FeedbackType + " " + report.UserAgent),
+	})
+	publishWebhookEvent(ctx, s.db, &models.WebhookEvent{
+		Type:      models.WebhookEmailComplained,
+		Tenant:    email.Tenant,
+		EmailID:   email.ID.Hex(),
+		Recipient: address,
+		Status:    string(email.Status),
+		Category:  email.Category,
+		Detail:    report.FeedbackType,
+	})
+
+	slog.Info("Recorded email complaint", "email_id", email.ID.Hex(), "feedback_type", report.FeedbackType)
+	return nil
+}
+
+// ReceiveReport handles a message read from the bounce mailbox. Only bounce and feedback loop
+// reports are recorded, anything else is logged and dropped since the mailbox gets no replies.
+func (s *InboundService) ReceiveReport(ctx context.Context, raw []byte) error {
+	if handled, err := s.receiveReport(ctx, nil, raw); handled {
+		return err
+	}
+
+	slog.Warn("Dropping bounce mailbox message that is not a bounce or feedback report", "bytes", len(raw))
+	return nil
+}
+
+// receiveReport records the message if it is a bounce or feedback loop report, and reports whether it was
+// one. Reports that fail to parse count as handled and are dropped.
+func (s *InboundService) receiveReport(ctx context.Context, to []string, raw []byte) (bool, error) {
+	if report, err := bounce.ParseDSN(bytes.NewReader(raw)); err == nil {
+		report.EnvelopeTo = slices.Concat(to, report.EnvelopeTo)
+		return true, s.recordBounces(ctx, report)
+	} else if !errors.Is(err, bounce.ErrNotReport) {
+		slog.Warn("Failed to parse bounce message", "error", err)
+		return true, nil
+	}
+
+	if report, err := bounce.ParseARF(bytes.NewReader(raw)); err == nil {
+		report.EnvelopeTo = slices.Concat(to, report.EnvelopeTo)
+		return true, s.recordComplaint(ctx, report)
+	} else if !errors.Is(err, bounce.ErrNotReport) {
+		slog.Warn("Failed to parse feedback report", "error", err)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// routeReply forwards a reply to the reply webhook, matched to the email it answers through the
+// reply+<id> address or the threading headers
+func (s *InboundService) routeReply(ctx context.Context, to []string, reply *inbound.Reply) error {
+	if reply.AutoSubmitted {
+		slog.Info("Dropping automatic reply", "from", reply.From, "message_id", reply.MessageID)
+		return nil
+	}
+
+	webhook := s.cfg.Inbound.ReplyWebhook
+	if webhook.URL == "" {
+		slog.Info("Dropping reply, no reply webhook configured", "from", reply.From)
+		return nil
+	}
+
+	event := replyEvent{
+		Event:      "email.reply",
+		MessageID:  reply.MessageID,
+		InReplyTo:  reply.InReplyTo,
+		References: reply.References,
+		From:       reply.From,
+		To:         reply.To,
+		Subject:    reply.Subject,
+		Text:       reply.Text,
+		HTML:       reply.HTML,
+		Time:       time.Now(),
+	}
+	if emailID, ok := correlateReply(slices.Concat(to, reply.To), slices.Concat([]string{reply.InReplyTo}, reply.References)); ok {
+		email, err := s.db.GetEmailByID(ctx, emailID.Hex())
+		if err != nil {
+			return err
+		}
+		if email != nil {
+			event.EmailID, event.Tenant = email.ID.Hex(), email.Tenant
+		}
+	}
+
+	if err := postSignedJSON(ctx, s.httpClient, webhook, event); err != nil {
+		slog.Error("Failed to deliver reply to webhook", "error", err, "from", reply.From)
+		return fmt.Errorf("reply webhook: %w", err)
+	}
+
+	slog.Info("Routed email reply", "email_id", event.EmailID, "from", reply.From)
+	return nil
+}
+
+// correlate finds the email a report is about from a VERP address among the recipients of the
+// report, falling back to the Message-ID of the returned message
+func correlate(addresses []string, messageID string) (bson.ObjectID, string, bool) {
+	for _, address := range addresses {
+		if emailID, recipient, ok := bounce.DecodeVERP(address); ok {
+			return emailID, recipient, true
+		}
+	}
+
+	emailID, ok := bounce.EmailIDFromMessageID(messageID)
+	return emailID, "", ok
+}
+
+func isVERPAddress(address string) bool {
+	_, _, ok := bounce.DecodeVERP(address)
+	return ok
+}
+
+// correlateReply prefers the reply address, which survives clients that drop threading headers
+func correlateReply(addresses []string, messageIDs []string) (bson.ObjectID, bool) {
+	for _, address := range addresses {
+		if emailID, ok := inbound.DecodeReplyAddress(address); ok {
+			return emailID, true
+		}
+	}
+
+	// The closest ancestor is last in References
+	for i := len(messageIDs) - 1; i >= 0; i-- {
+		if emailID, ok := bounce.EmailIDFromMessageID(messageIDs[i]); ok {
+			return emailID, true
+		}
+	}
+
+	return bson.NilObjectID, false
+}
+
+// hasBounce reports whether the same status was already recorded, mailboxes may be polled twice
+func hasBounce(bounces []models.Bounce, address string, status bounce.RecipientStatus) bool {
+	return slices.ContainsFunc(bounces, func(recorded models.Bounce) bool {
+		return recorded.Address == address && recorded.Action == status.Action && recorded.Status == status.Status
+	})
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultInboxPageSize = 20
+	// Items are dropped for subscribers that fall this far behind rather than blocking CreateItem
+	inboxSubscriberBuffer = 16
+)
+
+type InboxService struct {
+	db *database.Database
+
+	mu          sync.RWMutex
+	subscribers map[string]map[chan *models.InboxItem]struct{}
+	stopWatch   context.CancelFunc // Stops watching for new items, nil without subscribers
+}
+
+func NewInboxService(db *database.Database) types.InboxService {
+	return &InboxService{
+		db:          db,
+		subscribers: make(map[string]map[chan *models.InboxItem]struct{}),
+	}
+}
+
+func (s *InboxService) CreateItem(ctx context.Context, item *models.InboxItem) (*models.InboxItem, error) {
+	dbItem, err := s.db.CreateInboxItem(ctx, item)
+	if err != nil {
+		slog.Error("Failed to create inbox item", "error", err)
+		return nil, err
+	}
+
+	return dbItem, nil
+}
+
+func (s *InboxService) ListItems(ctx context.Context, filter models.InboxFilter) ([]*models.InboxItem, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultInboxPageSize
+	}
+
+	return s.db.ListInboxItems(ctx, filter)
+}
+
+func (s *InboxService) MarkRead(ctx context.Context, userID, id string, read bool) (*models.InboxItem, error) {
+	itemID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inbox item ID: %s", id)
+	}
+
+	return s.db.UpdateInboxItemRead(ctx, userID, itemID, read)
+}
+
+func (s *InboxService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return s.db.MarkAllInboxItemsRead(ctx, userID)
+}
+
+func (s *InboxService) Archive(ctx context.Context, userID, id string) (*models.InboxItem, error) {
+	itemID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inbox item ID: %s", id)
+	}
+
+	return s.db.UpdateInboxItemArchived(ctx, userID, itemID)
+}
+
+func (s *InboxService) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return s.db.CountUnreadInboxItems(ctx, userID)
+}
+
+// Subscribe registers a listener for a user's new items, including those created through other
+// instances. Each instance watches the collection once while it has subscribers and fans the items
+// out to them.
+func (s *InboxService) Subscribe(ctx context.Context, userID string) <-chan *models.InboxItem {
+	ch := make(chan *models.InboxItem, inboxSubscriberBuffer)
+
+	s.mu.Lock()
+	if s.stopWatch == nil {
+		var watchCtx context.Context
+		watchCtx, s.stopWatch = context.WithCancel(context.Background())
+		go s.watch(watchCtx)
+	}
+	if s.subscribers[userID] == nil {
+		s.subscribers[userID] = make(map[chan *models.InboxItem]struct{})
+	}
+	s.subscribers[userID][ch] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.mu.Lock()
+		delete(s.subscribers[userID], ch)
+		if len(s.subscribers[userID]) == 0 {
+			delete(s.subscribers, userID)
+		}
+		if len(s.subscribers) == 0 {
+			s.stopWatch()
+			s.stopWatch = nil
+		}
+		s.mu.Unlock()
+
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (s *InboxService) watch(ctx context.Context) {
+	for item := range s.db.WatchInboxItems(ctx) {
+		// Stopped when the last subscriber left, a newer watch serves later ones
+		if ctx.Err() != nil {
+			return
+		}
+		s.publish(item)
+	}
+}
+
+func (s *InboxService) publish(item *models.InboxItem) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for ch := range s.subscribers[item.UserID] {
+		select {
+		case ch <- item:
+		default:
+			slog.Warn("Inbox subscriber is falling behind, dropping item", "user_id", item.UserID, "item_id", item.ID.Hex())
+		}
+	}
+}
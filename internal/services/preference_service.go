@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+type PreferenceService struct {
+	db  *database.Database
+	cfg *config.Config
+}
+
+func NewPreferenceService(db *database.Database, cfg *config.Config) types.PreferenceService {
+	return &PreferenceService{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// GetPreference returns the stored rules for an address of the caller's tenant, or an empty
+// preference if it has none
+func (s *PreferenceService) GetPreference(ctx context.Context, address string) (*models.Preference, error) {
+	address = normalizeAddress(address)
+	tenant := auth.PrincipalFromContext(ctx).TenantID()
+
+	preference, err := s.db.GetPreference(ctx, tenant, address)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		preference = &models.Preference{Tenant: tenant, Address: address, Rules: []models.PreferenceRule{}}
+	}
+
+	return preference, nil
+}
+
+func (s *PreferenceService) UpdatePreference(ctx context.Context, address string, rules []models.PreferenceRule) (*models.Preference, error) {
+	address = normalizeAddress(address)
+	if address == "" {
+		return nil, fmt.Errorf("%w: address is required", types.ErrInvalidArgument)
+	}
+
+	for _, rule := range rules {
+		if rule.Category != models.PreferenceWildcard && !categoryPattern.MatchString(rule.Category) {
+			return nil, fmt.Errorf("%w: invalid category %q", types.ErrInvalidArgument, rule.Category)
+		}
+		if !rule.OptedIn && s.cfg.Preferences.IsMandatory(rule.Category) {
+			return nil, fmt.Errorf("%w: category %s is mandatory and cannot be opted out of", types.ErrInvalidArgument, rule.Category)
+		}
+	}
+
+	if rules == nil {
+		rules = []models.PreferenceRule{}
+	}
+
+	return s.db.UpsertPreference(ctx, auth.PrincipalFromContext(ctx).TenantID(), address, rules)
+}
+
+func (s *PreferenceService) DeletePreference(ctx context.Context, address string) error {
+	deleted, err := s.db.DeletePreference(ctx, auth.PrincipalFromContext(ctx).TenantID(), normalizeAddress(address))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: no preferences stored for %s", types.ErrNotFound, address)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+const defaultRecipientPageSize = 50
+
+type RecipientService struct {
+	db *database.Database
+}
+
+func NewRecipientService(db *database.Database) types.RecipientService {
+	return &RecipientService{
+		db: db,
+	}
+}
+
+func (s *RecipientService) CreateRecipient(ctx context.Context, recipient *models.Recipient) (*models.Recipient, error) {
+	if err := validateRecipient(recipient); err != nil {
+		return nil, err
+	}
+	recipient.Tenant = auth.PrincipalFromContext(ctx).TenantID()
+
+	dbRecipient, err := s.db.CreateRecipient(ctx, recipient)
+	if errors.Is(err, database.ErrDuplicateRecipient) {
+		return nil, fmt.Errorf("%w: recipient %s", types.ErrAlreadyExists, recipient.UserID)
+	}
+
+	return dbRecipient, err
+}
+
+func (s *RecipientService) GetRecipient(ctx context.Context, userID string) (*models.Recipient, error) {
+	recipient, err := s.db.GetRecipientByUserID(ctx, auth.PrincipalFromContext(ctx).TenantID(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		return nil, fmt.Errorf("%w: recipient %s", types.ErrNotFound, userID)
+	}
+
+	return recipient, nil
+}
+
+func (s *RecipientService) ListRecipients(ctx context.Context, after string, limit int64) ([]*models.Recipient, error) {
+	if limit <= 0 {
+		limit = defaultRecipientPageSize
+	}
+
+	return s.db.ListRecipients(ctx, auth.PrincipalFromContext(ctx).TenantID(), after, limit)
+}
+
+func (s *RecipientService) UpdateRecipient(ctx context.Context, recipient *models.Recipient) (*models.Recipient, error) {
+	if err := validateRecipient(recipient); err != nil {
+		return nil, err
+	}
+	recipient.Tenant = auth.PrincipalFromContext(ctx).TenantID()
+
+	dbRecipient, err := s.db.UpdateRecipient(ctx, recipient)
+	if err != nil {
+		return nil, err
+	}
+	if dbRecipient == nil {
+		return nil, fmt.Errorf("%w: recipient %s", types.ErrNotFound, recipient.UserID)
+	}
+
+	return dbRecipient, nil
+}
+
+func (s *RecipientService) DeleteRecipient(ctx context.Context, userID string) error {
+	deleted, err := s.db.DeleteRecipient(ctx, auth.PrincipalFromContext(ctx).TenantID(), userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: recipient %s", types.ErrNotFound, userID)
+	}
+
+	return nil
+}
+
+func validateRecipient(recipient *models.Recipient) error {
+	if recipient.UserID == "" {
+		return fmt.Errorf("%w: user_id is required", types.ErrInvalidArgument)
+	}
+	if len(recipient.Emails) == 0 {
+		return fmt.Errorf("%w: at least one email address is required", types.ErrInvalidArgument)
+	}
+	if recipient.Timezone != "" {
+		if _, err := time.LoadLocation(recipient.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %s", types.ErrInvalidArgument, recipient.Timezone)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/metrics"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	ratelimit "golang.org/x/time/rate"
+)
+
+// Buckets that refilled are dropped, an idle caller is checked for at most this often
+const limiterSweepInterval = time.Minute
+
+// Usage counters are kept per calendar month in UTC
+const usageMonthLayout = "2006-01"
+
+// SendLimiter throttles sends with a token bucket per API key or token subject and one per tenant,
+// and counts them against the tenants' monthly quotas. Buckets live in memory, so each instance
+// allows the full rate, quotas are shared through the database.
+type SendLimiter struct {
+	db  *database.Database
+	cfg *config.Config
+
+	mu        sync.Mutex
+	buckets   map[string]*ratelimit.Limiter
+	lastSweep time.Time
+}
+
+func NewSendLimiter(db *database.Database, cfg *config.Config) *SendLimiter {
+	return &SendLimiter{
+		db:        db,
+		cfg:       cfg,
+		buckets:   make(map[string]*ratelimit.Limiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Throttle takes n sends from the caller's buckets, either both or none. Callers without a principal
+// only have the tenant bucket.
+func (l *SendLimiter) Throttle(principal *models.Principal, tenant *models.Tenant, n int) error {
+	type bucket struct {
+		key   string
+		limit string
+		rate  float64
+		burst int
+	}
+
+	var buckets []bucket
+	if principal != nil && l.cfg.Limits.KeyRate > 0 {
+		buckets = append(buckets, bucket{
+			key:   "key/" + string(principal.Kind) + "/" + principal.ID,
+			limit: metrics.LimitKeyRate,
+			rate:  l.cfg.Limits.KeyRate,
+			burst: l.cfg.Limits.KeyBurst,
+		})
+	}
+	if tenantRate, tenantBurst := l.tenantRate(tenant); tenantRate > 0 {
+		buckets = append(buckets, bucket{
+			key:   "tenant/" + tenant.ID,
+			limit: metrics.LimitTenantRate,
+			rate:  tenantRate,
+			burst: tenantBurst,
+		})
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	reservations := make([]*ratelimit.Reservation, 0, len(buckets))
+	defer func() {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}()
+
+	for _, b := range buckets {
+		limiter := l.bucket(b.key, b.rate, b.burst)
+		if n > limiter.Burst() {
+			return fmt.Errorf("%w: %d emails exceed the %s burst of %d", types.ErrInvalidArgument, n, b.limit, limiter.Burst())
+		}
+
+		reservation := limiter.ReserveN(now, n)
+		reservations = append(reservations, reservation)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			metrics.CountThrottledSend(b.limit)
+			return &types.RateLimitError{
+				Message:    fmt.Sprintf("%s limit of %g emails per second exceeded", b.limit, b.rate),
+				RetryAfter: delay,
+			}
+		}
+	}
+
+	// Every bucket had room, keep the tokens
+	reservations = reservations[:0]
+
+	return nil
+}
+
+// ReserveQuota counts emails and recipients against the tenant's usage of the current month. The
+// returned release takes back those of the emails that aren't stored after all.
+func (l *SendLimiter) ReserveQuota(ctx context.Context, tenant *models.Tenant, emails, recipients int64) (func(emails, recipients int64), error) {
+	now := time.Now().UTC()
+	month := now.Format(usageMonthLayout)
+	quota := l.MonthlyQuota(tenant)
+
+	ok, err := l.db.ReserveUsage(ctx, tenant.ID, month, emails, recipients, quota)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		metrics.CountThrottledSend(metrics.LimitMonthlyQuota)
+		return nil, &types.RateLimitError{
+			Message:    fmt.Sprintf("tenant %s has used its monthly quota of %d emails", tenant.ID, quota),
+			RetryAfter: nextMonth(now).Sub(now),
+		}
+	}
+
+	release := func(emails, recipients int64) {
+		// Also release when the send failed because the request was canceled
+		if err := l.db.ReleaseUsage(context.WithoutCancel(ctx), tenant.ID, month, emails, recipients); err != nil {
+			slog.Error("Failed to release reserved usage", "tenant", tenant.ID, "error", err)
+		}
+	}
+
+	return release, nil
+}
+
+// MonthlyQuota returns the emails the tenant may send per month, 0 is unlimited
+func (l *SendLimiter) MonthlyQuota(tenant *models.Tenant) int64 {
+	if tenant.Limits != nil && tenant.Limits.MonthlyQuota > 0 {
+		return tenant.Limits.MonthlyQuota
+	}
+
+	return int64(l.cfg.Limits.MonthlyQuota)
+}
+
+// tenantRate returns the tenant's send rate and burst, its own where set
+func (l *SendLimiter) tenantRate(tenant *models.Tenant) (float64, int) {
+	tenantRate, tenantBurst := l.cfg.Limits.TenantRate, l.cfg.Limits.TenantBurst
+	if tenant.Limits != nil {
+		if tenant.Limits.Rate > 0 {
+			tenantRate = tenant.Limits.Rate
+		}
+		if tenant.Limits.Burst > 0 {
+			tenantBurst = tenant.Limits.Burst
+		}
+	}
+
+	return tenantRate, tenantBurst
+}
+
+// bucket returns the limiter of key, brought up to date with the current rate and burst. Must be
+// called with mu held.
+func (l *SendLimiter) bucket(key string, r float64, burst int) *ratelimit.Limiter {
+	burst = max(burst, 1)
+
+	limiter, ok := l.buckets[key]
+	if !ok {
+		limiter = ratelimit.NewLimiter(ratelimit.Limit(r), burst)
+		l.buckets[key] = limiter
+		return limiter
+	}
+
+	if limiter.Limit() != ratelimit.Limit(r) {
+		limiter.SetLimit(ratelimit.Limit(r))
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+
+	return limiter
+}
+
+// sweep drops the buckets that are full again, they are recreated full. Must be called with mu held.
+func (l *SendLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, limiter := range l.buckets {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// nextMonth returns the start of the month after t's, in t's location
+func nextMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+}
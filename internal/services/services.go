@@ -3,5 +3,26 @@ package services
 import "github.com/google/wire"
 
 var ProviderSet = wire.NewSet(
+	NewTenantDirectory,
+	NewSMTPSender,
 	NewEmailService,
+	NewEscalationDispatcher,
+	NewBounceProcessor,
+	NewTelegramService,
+	NewInboxService,
+	NewRecipientService,
+	NewPreferenceService,
+	NewSuppressionService,
+	NewUnsubscribeService,
+	NewInboundService,
+	NewWebhookService,
+	NewWebhookDispatcher,
+	NewTrackingService,
+	NewStatsService,
+	NewStatsRollup,
+	NewHealthService,
+	NewAPIKeyService,
+	NewTenantService,
+	NewSendLimiter,
+	NewUsageService,
 )
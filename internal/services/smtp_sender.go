@@ -0,0 +1,397 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/bounce"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/inbound"
+	"github.com/aarondever/notiflow/internal/metrics"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/gomail.v2"
+)
+
+// SMTPSender delivers emails through the SMTP servers of their tenant in round-robin order
+type SMTPSender struct {
+	servers         []config.SMTPServerConfig // The default tenant's
+	tenants         *TenantDirectory
+	verpDomain      string
+	messageIDDomain string
+	replyDomain     string
+	usageCounts     sync.Map // Tenant ID to its *atomic.Uint64 count of sends
+}
+
+func NewSMTPSender(cfg *config.Config, tenants *TenantDirectory) *SMTPSender {
+	return &SMTPSender{
+		servers:         cfg.SMTPServers,
+		tenants:         tenants,
+		verpDomain:      cfg.Bounce.VERPDomain,
+		messageIDDomain: cfg.Bounce.MessageIDDomain,
+		replyDomain:     cfg.Inbound.ReplyDomain,
+	}
+}
+
+// Probe connects and authenticates to every configured SMTP server without sending, returning each server's
+// error keyed by name (or host when unnamed), nil when it is reachable. Servers that don't finish
+// within timeout get a timeout error.
+func (s *SMTPSender) Probe(timeout time.Duration) map[string]error {
+	type probe struct {
+		name string
+		err  error
+	}
+
+	probes := make(chan probe, len(s.servers))
+	results := make(map[string]error, len(s.servers))
+	for _, server := range s.servers {
+		name := server.Name
+		if name == "" {
+			name = server.Host
+		}
+		results[name] = fmt.Errorf("probe timed out after %s", timeout)
+
+		go func() {
+			client, err := dialSMTP(server)
+			if err == nil {
+				err = client.Quit()
+				client.Close()
+			}
+			probes <- probe{name: name, err: err}
+		}()
+	}
+
+	deadline := time.After(timeout)
+	for range s.servers {
+		select {
+		case result := <-probes:
+			results[result.name] = result.err
+		case <-deadline:
+			return results
+		}
+	}
+
+	return results
+}
+
+// ServerCount returns the number of configured SMTP servers, those of the default tenant
+func (s *SMTPSender) ServerCount() int {
+	return len(s.servers)
+}
+
+// Matches the queue ID in replies like "250 2.0.0 Ok: queued as 4XyZ1" (Postfix) or
+// "250 OK id=1abcDE-000123-AB" (Exim)
+var queueIDPattern = regexp.MustCompile(`(?i)(?:queued as|id=)\s*([A-Za-z0-9._-]+)`)
+
+// SendResult describes one SMTP transaction
+type SendResult struct {
+	Server  string           // Name of the SMTP server used, or its host when unnamed
+	Sender  string           // From address the message was sent with
+	Refused map[string]error // Recipients the server refused, keyed by address
+	Reply   string           // Final reply to the message data
+	QueueID string           // Server's queue ID for the message, when the reply includes one
+}
+
+// Send delivers the email to its To, CC and BCC recipients using the next SMTP server of its
+// tenant, from email.From when set. headers are added to the message as is, e.g. List-Unsubscribe.
+//
+// Recipients missing from result.Refused were accepted unless err is set, which means the message
+// wasn't sent at all. result is returned with err when a server was picked. SMTP replies are
+// returned as *textproto.Error so callers can inspect their code.
+func (s *SMTPSender) Send(ctx context.Context, email *models.Email, headers map[string]string) (*SendResult, error) {
+	ctx, span := tracing.StartClient(ctx, "smtp send",
+		attribute.String("email.id", email.ID.Hex()),
+		attribute.Int("email.recipients", len(email.To)+len(email.CC)+len(email.BCC)),
+	)
+
+	start := time.Now()
+	result, err := s.send(ctx, email, headers)
+	if result != nil {
+		metrics.ObserveSMTPSend(result.Server, time.Since(start), len(result.Refused), err)
+		span.SetAttributes(
+			attribute.String("smtp.server", result.Server),
+			attribute.Int("smtp.refused_recipients", len(result.Refused)),
+			attribute.String("smtp.queue_id", result.QueueID),
+		)
+	}
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (s *SMTPSender) send(ctx context.Context, email *models.Email, headers map[string]string) (*SendResult, error) {
+	smtpServer, err := s.nextServer(ctx, email.Tenant)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Using SMTP server sending email", "tenant", email.Tenant, "username", smtpServer.Username, "host", smtpServer.Host)
+
+	sender := smtpServer.FromEmail
+	if email.From != "" {
+		sender = email.From
+	}
+
+	result := &SendResult{
+		Server:  smtpServer.Name,
+		Refused: make(map[string]error),
+	}
+	if result.Server == "" {
+		result.Server = smtpServer.Host
+	}
+
+	// Create message
+	message := gomail.NewMessage()
+	message.SetHeader("From", sender)
+	if len(email.To) > 0 {
+		message.SetHeader("To", email.To...)
+	}
+
+	if len(email.CC) > 0 {
+		message.SetHeader("Cc", email.CC...)
+	}
+	if len(email.BCC) > 0 {
+		message.SetHeader("Bcc", email.BCC...)
+	}
+
+	message.SetHeader("Subject", email.Subject)
+
+	// Bounce reports quote the Message-ID, which carries the email ID back to the bounce processor
+	if s.messageIDDomain != "" {
+		message.SetHeader("Message-ID", bounce.MessageID(email.ID, strconv.FormatInt(time.Now().UnixNano(), 36), s.messageIDDomain))
+	}
+
+	// Replies go to the inbound listener, which forwards them to the reply webhook
+	if s.replyDomain != "" {
+		message.SetHeader("Reply-To", inbound.ReplyAddress(email.ID, s.replyDomain))
+	}
+
+	for name, value := range headers {
+		message.SetHeader(name, value)
+	}
+
+	if email.IsHTML {
+		message.SetBody("text/html", email.Body)
+	} else {
+		message.SetBody("text/plain", email.Body)
+	}
+
+	// Add attachments
+	for _, attachment := range email.Attachments {
+		reader := bytes.NewReader(attachment.Content)
+		message.Attach(attachment.Filename, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := io.Copy(w, reader)
+			return err
+		}))
+	}
+
+	// The envelope sender is the bare address, the sender may include a display name
+	from, err := mail.ParseAddress(sender)
+	if err != nil {
+		return result, fmt.Errorf("invalid from address %q: %w", sender, err)
+	}
+	result.Sender = strings.ToLower(from.Address)
+
+	recipients := slices.Concat(email.To, email.CC, email.BCC)
+
+	// With VERP the return path identifies the email, and the recipient when there is only one
+	envelopeFrom := from.Address
+	if s.verpDomain != "" {
+		var recipient string
+		if len(recipients) == 1 {
+			recipient = recipients[0]
+		}
+		envelopeFrom = bounce.EncodeVERP(email.ID, recipient, s.verpDomain)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(semconv.ServerAddress(smtpServer.Host), semconv.ServerPort(smtpServer.Port))
+
+	client, err := dialSMTP(smtpServer)
+	if err != nil {
+		return result, err
+	}
+	defer client.Close()
+	span.AddEvent("connected")
+
+	if err = client.Mail(envelopeFrom); err != nil {
+		return result, err
+	}
+
+	// The server may refuse some recipients and accept the others, the message still goes to those
+	for _, recipient := range recipients {
+		if err = client.Rcpt(recipient); err != nil {
+			result.Refused[recipient] = err
+		}
+	}
+	if len(result.Refused) == len(recipients) {
+		return result, fmt.Errorf("all recipients refused: %w", result.Refused[recipients[0]])
+	}
+	span.AddEvent("recipients accepted", trace.WithAttributes(attribute.Int("smtp.accepted", len(recipients)-len(result.Refused))))
+
+	if result.Reply, err = sendData(client, message); err != nil {
+		return result, err
+	}
+	span.AddEvent("data accepted")
+	if match := queueIDPattern.FindStringSubmatch(result.Reply); match != nil {
+		result.QueueID = match[1]
+	}
+
+	// The message is delivered once DATA is accepted, a failed QUIT doesn't change that
+	if err = client.Quit(); err != nil {
+		slog.Warn("SMTP QUIT failed after the message was accepted", "error", err, "server", smtpServer.Name)
+	}
+
+	return result, nil
+}
+
+// nextServer picks the tenant's SMTP server in round-robin order
+func (s *SMTPSender) nextServer(ctx context.Context, tenantID string) (config.SMTPServerConfig, error) {
+	tenant, err := s.tenants.Lookup(ctx, tenantID)
+	if err != nil {
+		return config.SMTPServerConfig{}, err
+	}
+	if tenant == nil || len(tenant.SMTPServers) == 0 {
+		return config.SMTPServerConfig{}, fmt.Errorf("no SMTP servers configured for tenant %s", tenantID)
+	}
+
+	counter, _ := s.usageCounts.LoadOrStore(tenant.ID, new(atomic.Uint64))
+	server := tenant.SMTPServers[(counter.(*atomic.Uint64).Add(1)-1)%uint64(len(tenant.SMTPServers))]
+
+	return config.SMTPServerConfig{
+		Name:      server.Name,
+		Host:      server.Host,
+		Port:      server.Port,
+		Username:  server.Username,
+		Password:  server.Password,
+		FromEmail: server.FromEmail,
+	}, nil
+}
+
+// sendData sends the message and returns the server's final reply, which net/smtp's Data discards
+func sendData(client *smtp.Client, message *gomail.Message) (string, error) {
+	id, err := client.Text.Cmd("DATA")
+	if err != nil {
+		return "", err
+	}
+
+	client.Text.StartResponse(id)
+	_, _, err = client.Text.ReadResponse(354)
+	client.Text.EndResponse(id)
+	if err != nil {
+		return "", err
+	}
+
+	writer := client.Text.DotWriter()
+	if _, err = message.WriteTo(writer); err != nil {
+		writer.Close()
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+
+	_, reply, err := client.Text.ReadResponse(250)
+	return reply, err
+}
+
+// dialSMTP connects and authenticates the same way gomail's Dialer does: implicit TLS on port 465,
+// STARTTLS when offered, and the strongest advertised auth mechanism.
+func dialSMTP(server config.SMTPServerConfig) (*smtp.Client, error) {
+	tlsConfig := &tls.Config{ServerName: server.Host}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(server.Host, strconv.Itoa(server.Port)), 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if server.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, server.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err = client.Hello("localhost"); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	if server.Port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err = client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	if server.Username != "" {
+		if ok, mechanisms := client.Extension("AUTH"); ok {
+			var auth smtp.Auth
+			switch {
+			case strings.Contains(mechanisms, "CRAM-MD5"):
+				auth = smtp.CRAMMD5Auth(server.Username, server.Password)
+			case strings.Contains(mechanisms, "LOGIN") && !strings.Contains(mechanisms, "PLAIN"):
+				auth = &loginAuth{username: server.Username, password: server.Password}
+			default:
+				auth = smtp.PlainAuth("", server.Username, server.Password, server.Host)
+			}
+
+			if err = client.Auth(auth); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	return client, nil
+}
+
+// loginAuth implements the LOGIN mechanism, which net/smtp doesn't provide
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !slices.Contains(server.Auth, "LOGIN") {
+		return "", nil, errors.New("unencrypted connection")
+	}
+
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch string(fromServer) {
+	case "Username:":
+		return []byte(a.username), nil
+	case "Password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+	}
+}
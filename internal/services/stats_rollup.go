@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+)
+
+const (
+	// Emails are updated with the app's clock, changes made this long before a refresh started are
+	// picked up again by the next one
+	statsRollupSkew = 5 * time.Second
+	// Emails expire after 90 days, older hours keep their last rollup instead of being recomputed
+	// from a partly expired hour
+	statsRollupHorizon = 89 * 24 * time.Hour
+)
+
+// StatsRollup keeps the hourly email stats rollups up to date, recomputing every hour whose
+// emails changed since the previous refresh
+type StatsRollup struct {
+	db  *database.Database
+	cfg *config.Config
+}
+
+func NewStatsRollup(db *database.Database, cfg *config.Config) *StatsRollup {
+	return &StatsRollup{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func (r *StatsRollup) Run(ctx context.Context) {
+	interval := time.Duration(r.cfg.Stats.RollupInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	slog.Info("Starting stats rollup", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Resume from the last refresh, the first run on an empty collection backfills every email
+	since, err := r.db.LatestEmailStatsRollup(ctx)
+	for err != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		since, err = r.db.LatestEmailStatsRollup(ctx)
+	}
+
+	for {
+		started := time.Now()
+		if r.refresh(ctx, since) {
+			since = started.Add(-statsRollupSkew)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh recomputes the hours of the emails updated since the given time, returns false when it
+// didn't get through them all so the next run retries
+func (r *StatsRollup) refresh(ctx context.Context, since time.Time) bool {
+	hours, err := r.db.EmailStatsHours(ctx, since)
+	if err != nil {
+		return false
+	}
+
+	horizon := time.Now().Add(-statsRollupHorizon)
+	for _, hour := range hours {
+		if ctx.Err() != nil {
+			return false
+		}
+		if hour.Before(horizon) {
+			continue
+		}
+
+		if err = r.db.RollupEmailStats(ctx, hour); err != nil {
+			return false
+		}
+	}
+
+	if len(hours) > 0 {
+		slog.Debug("Refreshed email stats", "hours", len(hours))
+	}
+
+	return true
+}
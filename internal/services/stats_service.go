@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+const (
+	// Period reported when the request has no since
+	defaultStatsPeriod = 7 * 24 * time.Hour
+	// Longest periods a request may cover, hourly groups are limited further to keep responses small
+	maxStatsPeriod       = 366 * 24 * time.Hour
+	maxHourlyStatsPeriod = 31 * 24 * time.Hour
+)
+
+type StatsService struct {
+	db  *database.Database
+	cfg *config.Config
+}
+
+func NewStatsService(db *database.Database, cfg *config.Config) types.StatsService {
+	return &StatsService{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func (s *StatsService) EmailStats(ctx context.Context, request *models.StatsRequest) (*models.StatsResponse, error) {
+	query := *request
+	query.Tenant = auth.PrincipalFromContext(ctx).TenantID()
+	if query.GroupBy == "" {
+		query.GroupBy = models.StatsByDay
+	}
+
+	switch query.GroupBy {
+	case models.StatsByDay, models.StatsByHour, models.StatsByServer, models.StatsBySender, models.StatsByCategory, models.StatsByDomain:
+	default:
+		return nil, fmt.Errorf("%w: group_by must be one of day, hour, server, sender, category, domain", types.ErrInvalidArgument)
+	}
+
+	// Rollups are hourly, so the period is widened to whole hours
+	if query.Until.IsZero() {
+		query.Until = time.Now()
+	}
+	query.Until = query.Until.UTC().Add(time.Hour - time.Nanosecond).Truncate(time.Hour)
+	if query.Since.IsZero() {
+		query.Since = query.Until.Add(-defaultStatsPeriod)
+	}
+	query.Since = query.Since.UTC().Truncate(time.Hour)
+
+	period := query.Until.Sub(query.Since)
+	switch {
+	case period <= 0:
+		return nil, fmt.Errorf("%w: until must be after since", types.ErrInvalidArgument)
+	case period > maxStatsPeriod:
+		return nil, fmt.Errorf("%w: period must not exceed 366 days", types.ErrInvalidArgument)
+	case query.GroupBy == models.StatsByHour && period > maxHourlyStatsPeriod:
+		return nil, fmt.Errorf("%w: period must not exceed 31 days when grouping by hour", types.ErrInvalidArgument)
+	}
+
+	groups, totals, err := s.db.AggregateEmailStats(ctx, &query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		setStatsRates(group)
+	}
+	setStatsRates(totals)
+	totals.Key = ""
+
+	return &models.StatsResponse{
+		GroupBy: query.GroupBy,
+		Since:   query.Since,
+		Until:   query.Until,
+		Totals:  totals,
+		Groups:  groups,
+	}, nil
+}
+
+func (s *StatsService) CountEmailsByStatus(ctx context.Context) (map[string]int64, error) {
+	return s.db.CountEmailsByStatus(ctx)
+}
+
+func setStatsRates(group *models.StatsGroup) {
+	group.DeliveryRate = rate(group.Sent, group.Sent+group.Failed+group.Bounced)
+	group.BounceRate = rate(group.Bounced, group.Sent+group.Bounced)
+	group.OpenRate = rate(group.Opened, group.OpenTracked)
+	group.ClickRate = rate(group.Clicked, group.ClickTracked)
+}
+
+// rate is count / base rounded to 4 decimals, or 0 when base is 0
+func rate(count, base int) float64 {
+	if base == 0 {
+		return 0
+	}
+
+	return math.Round(float64(count)/float64(base)*10000) / 10000
+}
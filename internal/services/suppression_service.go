@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+const (
+	defaultSuppressionPageSize = 100
+	// Rows are written in batches so huge imports don't hold everything in one bulk write
+	suppressionImportBatchSize = 1000
+)
+
+type SuppressionService struct {
+	db *database.Database
+}
+
+func NewSuppressionService(db *database.Database) types.SuppressionService {
+	return &SuppressionService{
+		db: db,
+	}
+}
+
+func (s *SuppressionService) ListSuppressions(ctx context.Context, reason models.SuppressionReason, after string, limit int64) ([]*models.Suppression, error) {
+	if limit <= 0 {
+		limit = defaultSuppressionPageSize
+	}
+
+	return s.db.ListSuppressions(ctx, auth.PrincipalFromContext(ctx).TenantID(), reason, normalizeAddress(after), limit)
+}
+
+func (s *SuppressionService) GetSuppression(ctx context.Context, address string) (*models.Suppression, error) {
+	suppression, err := s.db.GetSuppression(ctx, auth.PrincipalFromContext(ctx).TenantID(), normalizeAddress(address))
+	if err != nil {
+		return nil, err
+	}
+	if suppression == nil {
+		return nil, fmt.Errorf("%w: %s is not suppressed", types.ErrNotFound, address)
+	}
+
+	return suppression, nil
+}
+
+func (s *SuppressionService) AddSuppression(ctx context.Context, suppression *models.Suppression) (*models.Suppression, error) {
+	suppression.Tenant = auth.PrincipalFromContext(ctx).TenantID()
+	suppression.Address = normalizeAddress(suppression.Address)
+	if suppression.Source == "" {
+		suppression.Source = models.SuppressionSourceAPI
+	}
+	if suppression.ExpiresAt != nil && !suppression.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", types.ErrInvalidArgument)
+	}
+
+	if err := s.db.UpsertSuppressions(ctx, []*models.Suppression{suppression}); err != nil {
+		return nil, err
+	}
+
+	return s.db.GetSuppression(ctx, suppression.Tenant, suppression.Address)
+}
+
+func (s *SuppressionService) RemoveSuppression(ctx context.Context, address string) error {
+	deleted, err := s.db.DeleteSuppression(ctx, auth.PrincipalFromContext(ctx).TenantID(), normalizeAddress(address))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s is not suppressed", types.ErrNotFound, address)
+	}
+
+	return nil
+}
+
+func (s *SuppressionService) ImportCSV(ctx context.Context, reader io.Reader) (*models.SuppressionImportResult, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	result := &models.SuppressionImportResult{}
+	batch := make([]*models.Suppression, 0, suppressionImportBatchSize)
+	tenant := auth.PrincipalFromContext(ctx).TenantID()
+	now := time.Now()
+
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", types.ErrInvalidArgument, line, err)
+		}
+
+		// Skip the header row, if present
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+			continue
+		}
+
+		suppression, err := parseSuppressionRecord(record, now)
+		if err != nil {
+			result.Skipped++
+			result.Errors = append(result.Errors, models.SuppressionImportError{Line: line, Error: err.Error()})
+			continue
+		}
+
+		suppression.Tenant = tenant
+		batch = append(batch, suppression)
+		if len(batch) == suppressionImportBatchSize {
+			if err = s.db.UpsertSuppressions(ctx, batch); err != nil {
+				return nil, err
+			}
+			result.Imported += len(batch)
+			batch = batch[:0]
+		}
+	}
+
+	if err := s.db.UpsertSuppressions(ctx, batch); err != nil {
+		return nil, err
+	}
+	result.Imported += len(batch)
+
+	return result, nil
+}
+
+func parseSuppressionRecord(record []string, now time.Time) (*models.Suppression, error) {
+	address := normalizeAddress(record[0])
+	if parsed, err := mail.ParseAddress(address); err != nil || parsed.Address != address {
+		return nil, fmt.Errorf("invalid address %q", record[0])
+	}
+
+	suppression := &models.Suppression{
+		Address:   address,
+		Reason:    models.SuppressionManual,
+		Source:    models.SuppressionSourceImport,
+		CreatedAt: now,
+	}
+
+	if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+		suppression.Reason = models.SuppressionReason(strings.TrimSpace(record[1]))
+		switch suppression.Reason {
+		case models.SuppressionHardBounce, models.SuppressionComplaint, models.SuppressionUnsubscribe, models.SuppressionManual:
+		default:
+			return nil, fmt.Errorf("invalid reason %q", record[1])
+		}
+	}
+
+	if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+		expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(record[2]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires_at %q, expected RFC 3339", record[2])
+		}
+		if !expiresAt.After(now) {
+			return nil, fmt.Errorf("expires_at %q is in the past", record[2])
+		}
+		suppression.ExpiresAt = &expiresAt
+	}
+
+	return suppression, nil
+}
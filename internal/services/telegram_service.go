@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/telegram"
+	"github.com/aarondever/notiflow/internal/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type TelegramService struct {
+	db         *database.Database
+	cfg        *config.Config
+	clients    map[string]*telegram.Client
+	defaultBot string
+}
+
+func NewTelegramService(db *database.Database, cfg *config.Config) types.TelegramService {
+	service := &TelegramService{
+		db:      db,
+		cfg:     cfg,
+		clients: make(map[string]*telegram.Client, len(cfg.Telegram.Bots)),
+	}
+
+	for _, bot := range cfg.Telegram.Bots {
+		service.clients[bot.Name] = telegram.NewClient(cfg.Telegram.APIBaseURL, bot.Token)
+		if service.defaultBot == "" {
+			service.defaultBot = bot.Name
+		}
+	}
+
+	return service
+}
+
+func (s *TelegramService) SendMessage(ctx context.Context, message *models.TelegramMessage) (*models.TelegramMessage, error) {
+	if len(s.clients) == 0 {
+		return nil, fmt.Errorf("%w: no Telegram bots configured", types.ErrInvalidArgument)
+	}
+
+	if message.Bot == "" {
+		message.Bot = s.defaultBot
+	}
+	if _, ok := s.clients[message.Bot]; !ok {
+		return nil, fmt.Errorf("%w: unknown Telegram bot: %s", types.ErrInvalidArgument, message.Bot)
+	}
+
+	switch message.ParseMode {
+	case models.ParseModeText, models.ParseModeMarkdown, models.ParseModeMarkdownV2, models.ParseModeHTML:
+	default:
+		return nil, fmt.Errorf("%w: unsupported parse mode: %s", types.ErrInvalidArgument, message.ParseMode)
+	}
+
+	if message.Text == "" && message.Document == nil {
+		return nil, fmt.Errorf("%w: text or document is required", types.ErrInvalidArgument)
+	}
+
+	// Save to database
+	dbMessage, err := s.db.CreateTelegramMessage(ctx, message)
+	if err != nil {
+		slog.Error("Failed to create telegram message", "error", err)
+		return nil, err
+	}
+
+	// Send message asynchronously
+	go s.sendMessageAsync(dbMessage)
+
+	return dbMessage, nil
+}
+
+// GetMessage returns a Telegram message with the delivery state of each chat
+func (s *TelegramService) GetMessage(ctx context.Context, id string) (*models.TelegramMessage, error) {
+	if _, err := bson.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("%w: invalid Telegram message ID", types.ErrInvalidArgument)
+	}
+
+	message, err := s.db.GetTelegramMessageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, fmt.Errorf("%w: Telegram message %s", types.ErrNotFound, id)
+	}
+
+	// Document content can be large, only return its metadata
+	if message.Document != nil {
+		message.Document.Content = nil
+	}
+
+	return message, nil
+}
+
+// RetryMessage sends a failed or partially sent message again to the chats that didn't get it
+func (s *TelegramService) RetryMessage(ctx context.Context, id string) (*models.TelegramMessage, error) {
+	messageID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid Telegram message ID", types.ErrInvalidArgument)
+	}
+
+	message, err := s.db.ClaimTelegramMessageRetry(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		existing, err := s.db.GetTelegramMessageByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("%w: Telegram message %s", types.ErrNotFound, id)
+		}
+
+		return nil, fmt.Errorf("%w: Telegram message %s is %s, only failed and partial messages are retried", types.ErrInvalidArgument, id, existing.Status)
+	}
+	if _, ok := s.clients[message.Bot]; !ok {
+		return nil, fmt.Errorf("%w: unknown Telegram bot: %s", types.ErrInvalidArgument, message.Bot)
+	}
+
+	go s.sendMessageAsync(message)
+
+	return message, nil
+}
+
+// sendMessageAsync sends the message to each chat that hasn't got it yet and records the outcome per
+// chat. A failed chat doesn't stop the others.
+func (s *TelegramService) sendMessageAsync(message *models.TelegramMessage) {
+	ctx := context.Background()
+
+	client := s.clients[message.Bot]
+	slog.Info("Using Telegram bot sending message", "bot", message.Bot, "chats", len(message.ChatIDs))
+
+	parseMode := string(message.ParseMode)
+
+	delivered := make(map[string]bool, len(message.ChatIDs))
+	for _, chat := range message.Chats {
+		if chat.Status == models.StatusSent {
+			delivered[chat.ChatID] = true
+		}
+	}
+
+	var failures []string
+	for _, chatID := range message.ChatIDs {
+		if delivered[chatID] {
+			continue
+		}
+
+		var err error
+		if message.Document != nil {
+			err = client.SendDocument(ctx, chatID, message.Document.Filename, message.Document.Content, message.Text, parseMode)
+		} else {
+			err = client.SendMessage(ctx, chatID, message.Text, parseMode)
+		}
+
+		chat := &models.TelegramChat{ChatID: chatID, Status: models.StatusSent, SentAt: time.Now()}
+		if err != nil {
+			slog.Error("Failed to send telegram message", "error", err, "chat_id", chatID)
+			failures = append(failures, fmt.Sprintf("chat %s: %s", chatID, err.Error()))
+			chat = &models.TelegramChat{ChatID: chatID, Status: models.StatusFailed, ErrorMsg: err.Error()}
+		} else {
+			delivered[chatID] = true
+		}
+
+		if err = s.db.UpdateTelegramChat(ctx, message.ID, chat); err != nil {
+			slog.Error("Failed to update telegram chat", "error", err, "chat_id", chatID)
+		}
+	}
+
+	if len(failures) > 0 {
+		// Each chat keeps its own error, the message only summarizes them
+		failed := &models.TelegramMessage{ID: message.ID, Status: models.StatusFailed, ErrorMsg: failures[0]}
+		if len(failures) > 1 {
+			failed.ErrorMsg = fmt.Sprintf("%s (and %d more chats)", failures[0], len(failures)-1)
+		}
+		if len(delivered) > 0 {
+			failed.Status = models.StatusPartial
+		}
+
+		if _, err := s.db.UpdateTelegramMessageFail(ctx, failed); err != nil {
+			slog.Error("Failed to update telegram message", "error", err)
+		}
+
+		return
+	}
+
+	// Update status to sent
+	_, err := s.db.UpdateTelegramMessageSent(ctx, &models.TelegramMessage{
+		ID:     message.ID,
+		SentAt: time.Now(),
+	})
+	if err != nil {
+		slog.Error("Failed to update telegram message", "error", err)
+	}
+}
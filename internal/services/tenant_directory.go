@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+// Tenants are looked up on every request and send, changes made through another instance take
+// effect within this long
+const tenantCacheTTL = 30 * time.Second
+
+type cachedTenant struct {
+	tenant    *models.Tenant // nil when the tenant doesn't exist
+	fetchedAt time.Time
+}
+
+// TenantDirectory resolves tenant IDs to their settings. The default tenant isn't stored, it has the
+// configured SMTP servers.
+type TenantDirectory struct {
+	db            *database.Database
+	defaultTenant *models.Tenant
+
+	mu      sync.Mutex
+	tenants map[string]cachedTenant
+}
+
+func NewTenantDirectory(db *database.Database, cfg *config.Config) *TenantDirectory {
+	defaultTenant := &models.Tenant{ID: models.DefaultTenant, Name: "Default"}
+	for _, server := range cfg.SMTPServers {
+		defaultTenant.SMTPServers = append(defaultTenant.SMTPServers, models.TenantSMTPServer{
+			Name:      server.Name,
+			Host:      server.Host,
+			Port:      server.Port,
+			Username:  server.Username,
+			Password:  server.Password,
+			FromEmail: server.FromEmail,
+		})
+	}
+
+	return &TenantDirectory{
+		db:            db,
+		defaultTenant: defaultTenant,
+		tenants:       make(map[string]cachedTenant),
+	}
+}
+
+// Lookup returns the tenant with SMTP passwords, or nil when it doesn't exist. The result is shared
+// and must not be modified.
+func (d *TenantDirectory) Lookup(ctx context.Context, id string) (*models.Tenant, error) {
+	if id == "" || id == models.DefaultTenant {
+		return d.defaultTenant, nil
+	}
+
+	d.mu.Lock()
+	cached, ok := d.tenants[id]
+	d.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < tenantCacheTTL {
+		return cached.tenant, nil
+	}
+
+	tenant, err := d.db.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.tenants[id] = cachedTenant{tenant: tenant, fetchedAt: time.Now()}
+	d.mu.Unlock()
+
+	return tenant, nil
+}
+
+// Invalidate drops the cached tenant so this instance sees a change right away
+func (d *TenantDirectory) Invalidate(id string) {
+	d.mu.Lock()
+	delete(d.tenants, id)
+	d.mu.Unlock()
+}
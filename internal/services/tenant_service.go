@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type TenantService struct {
+	db      *database.Database
+	tenants *TenantDirectory
+}
+
+func NewTenantService(db *database.Database, tenants *TenantDirectory) types.TenantService {
+	return &TenantService{
+		db:      db,
+		tenants: tenants,
+	}
+}
+
+func (s *TenantService) CreateTenant(ctx context.Context, request *models.CreateTenantRequest) (*models.Tenant, error) {
+	if !tenantIDPattern.MatchString(request.ID) {
+		return nil, fmt.Errorf("%w: tenant ID must be 1-63 lower-case letters, digits, '_' or '-'", types.ErrInvalidArgument)
+	}
+	if request.ID == models.DefaultTenant {
+		return nil, fmt.Errorf("%w: tenant %s is built in", types.ErrAlreadyExists, models.DefaultTenant)
+	}
+	if err := validateTenantServers(request.SMTPServers); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.db.CreateTenant(ctx, &models.Tenant{
+		ID:          request.ID,
+		Name:        request.Name,
+		SMTPServers: request.SMTPServers,
+		Senders:     normalizeSenders(request.Senders),
+		Limits:      request.Limits,
+	})
+	if errors.Is(err, database.ErrDuplicateTenant) {
+		return nil, fmt.Errorf("%w: tenant %s", types.ErrAlreadyExists, request.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.tenants.Invalidate(tenant.ID)
+
+	return redactTenant(tenant), nil
+}
+
+func (s *TenantService) ListTenants(ctx context.Context) ([]*models.Tenant, error) {
+	tenants, err := s.db.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tenant := range tenants {
+		tenants[i] = redactTenant(tenant)
+	}
+
+	return tenants, nil
+}
+
+func (s *TenantService) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	tenant, err := s.db.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, fmt.Errorf("%w: tenant %s", types.ErrNotFound, id)
+	}
+
+	return redactTenant(tenant), nil
+}
+
+func (s *TenantService) UpdateTenant(ctx context.Context, id string, request *models.UpdateTenantRequest) (*models.Tenant, error) {
+	if err := validateTenantServers(request.SMTPServers); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.db.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("%w: tenant %s", types.ErrNotFound, id)
+	}
+
+	// Passwords are never returned, so updates leave them out to keep them
+	servers := slices.Clone(request.SMTPServers)
+	for i := range servers {
+		if servers[i].Password != "" {
+			continue
+		}
+		for _, previous := range existing.SMTPServers {
+			if previous.Name == servers[i].Name && previous.Host == servers[i].Host {
+				servers[i].Password = previous.Password
+				break
+			}
+		}
+	}
+
+	tenant, err := s.db.UpdateTenant(ctx, &models.Tenant{
+		ID:          id,
+		Name:        request.Name,
+		SMTPServers: servers,
+		Senders:     normalizeSenders(request.Senders),
+		Disabled:    request.Disabled,
+		Limits:      request.Limits,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, fmt.Errorf("%w: tenant %s", types.ErrNotFound, id)
+	}
+	s.tenants.Invalidate(id)
+
+	return redactTenant(tenant), nil
+}
+
+func (s *TenantService) CheckTenant(ctx context.Context, id string) error {
+	tenant, err := s.tenants.Lookup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tenant == nil {
+		return fmt.Errorf("%w: unknown tenant %s", types.ErrPermissionDenied, id)
+	}
+	if tenant.Disabled {
+		return fmt.Errorf("%w: tenant %s is disabled", types.ErrPermissionDenied, id)
+	}
+
+	return nil
+}
+
+func validateTenantServers(servers []models.TenantSMTPServer) error {
+	for i, server := range servers {
+		if _, err := mail.ParseAddress(server.FromEmail); err != nil {
+			return fmt.Errorf("%w: smtp server %d: invalid from_email %q", types.ErrInvalidArgument, i, server.FromEmail)
+		}
+	}
+
+	return nil
+}
+
+func normalizeSenders(senders []string) []string {
+	normalized := make([]string, 0, len(senders))
+	for _, sender := range senders {
+		normalized = append(normalized, normalizeAddress(sender))
+	}
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
+
+// redactTenant returns a copy of the tenant without SMTP passwords
+func redactTenant(tenant *models.Tenant) *models.Tenant {
+	redacted := *tenant
+	redacted.SMTPServers = slices.Clone(tenant.SMTPServers)
+	for i := range redacted.SMTPServers {
+		redacted.SMTPServers[i].Password = ""
+	}
+
+	return &redacted
+}
+
+// senderAllowed reports whether the tenant may send as from: one of its senders or the from address
+// of one of its SMTP servers
+func senderAllowed(tenant *models.Tenant, from string) bool {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return false
+	}
+	sender := normalizeAddress(address.Address)
+
+	if slices.Contains(tenant.Senders, sender) {
+		return true
+	}
+	for _, server := range tenant.SMTPServers {
+		if serverFrom, err := mail.ParseAddress(server.FromEmail); err == nil && normalizeAddress(serverFrom.Address) == sender {
+			return true
+		}
+	}
+
+	return false
+}
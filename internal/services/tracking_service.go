@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Repeated fetches by the same recipient and address within this window count as one open,
+// clients and proxies often load an image several times in a row
+const openDedupeWindow = time.Minute
+
+// Links listed per category in click reports
+const topCategoryLinks = 20
+
+// Apple's network, which Mail Privacy Protection proxies fetch images from
+var appleNetwork = netip.MustParsePrefix("17.0.0.0/8")
+
+type TrackingService struct {
+	db     *database.Database
+	cfg    *config.Config
+	signer *trackingSigner
+}
+
+func NewTrackingService(db *database.Database, cfg *config.Config) types.TrackingService {
+	return &TrackingService{
+		db:     db,
+		cfg:    cfg,
+		signer: newTrackingSigner(cfg),
+	}
+}
+
+func (s *TrackingService) RecordOpen(ctx context.Context, token, userAgent, ip string) error {
+	fields, err := s.signer.verify(token, 2)
+	if err != nil {
+		return fmt.Errorf("%w: %v", types.ErrInvalidArgument, err)
+	}
+
+	email, recipient, err := s.trackedRecipient(ctx, fields[0], fields[1])
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	event := &models.EmailEvent{
+		EmailID:   email.ID,
+		Type:      models.EventOpened,
+		Recipient: recipient.Address,
+		UserAgent: truncate(userAgent, 512),
+		IPHash:    s.signer.hashIP(ip),
+		Category:  email.Category,
+		Tenant:    email.Tenant,
+	}
+
+	duplicate, err := s.db.HasRecentEmailEvent(ctx, event, now.Add(-openDedupeWindow))
+	if err != nil || duplicate {
+		return err
+	}
+
+	event.Detail = s.prefetchReason(recipient, userAgent, ip, now, "opened")
+	event.Prefetch = event.Detail != ""
+	recordEmailEvents(ctx, s.db, event)
+	if event.Prefetch {
+		return nil
+	}
+
+	if err = s.db.RecordEmailOpen(ctx, email.ID, recipient.Address, now); err != nil {
+		return err
+	}
+
+	publishWebhookEvent(ctx, s.db, &models.WebhookEvent{
+		Type:      models.WebhookEmailOpened,
+		Tenant:    email.Tenant,
+		EmailID:   email.ID.Hex(),
+		Recipient: recipient.Address,
+		Status:    string(email.Status),
+		Category:  email.Category,
+		Time:      now,
+	})
+
+	return nil
+}
+
+func (s *TrackingService) RecordClick(ctx context.Context, token, userAgent, ip string) (string, error) {
+	fields, err := s.signer.verify(token, 3)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", types.ErrInvalidArgument, err)
+	}
+	target := fields[2]
+
+	// The signature proves the link was issued by us, the click itself is recorded best-effort
+	email, recipient, err := s.trackedRecipient(ctx, fields[0], fields[1])
+	if err != nil {
+		return target, err
+	}
+
+	now := time.Now()
+	event := &models.EmailEvent{
+		EmailID:   email.ID,
+		Type:      models.EventClicked,
+		Recipient: recipient.Address,
+		UserAgent: truncate(userAgent, 512),
+		IPHash:    s.signer.hashIP(ip),
+		URL:       target,
+		Category:  email.Category,
+		Tenant:    email.Tenant,
+	}
+
+	duplicate, err := s.db.HasRecentEmailEvent(ctx, event, now.Add(-openDedupeWindow))
+	if err != nil || duplicate {
+		return target, err
+	}
+
+	event.Detail = s.prefetchReason(recipient, userAgent, ip, now, "clicked")
+	event.Prefetch = event.Detail != ""
+	recordEmailEvents(ctx, s.db, event)
+	if event.Prefetch {
+		return target, nil
+	}
+
+	if err = s.db.RecordEmailClick(ctx, email.ID, recipient.Address, now); err != nil {
+		return target, err
+	}
+
+	publishWebhookEvent(ctx, s.db, &models.WebhookEvent{
+		Type:      models.WebhookEmailClicked,
+		Tenant:    email.Tenant,
+		EmailID:   email.ID.Hex(),
+		Recipient: recipient.Address,
+		Status:    string(email.Status),
+		Category:  email.Category,
+		URL:       target,
+		Time:      now,
+	})
+
+	return target, nil
+}
+
+func (s *TrackingService) EmailClicks(ctx context.Context, id string) (*models.EmailClickStats, error) {
+	if _, err := bson.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("%w: invalid email ID", types.ErrInvalidArgument)
+	}
+
+	email, err := s.db.GetTenantEmail(ctx, auth.PrincipalFromContext(ctx).TenantID(), id)
+	if err != nil {
+		return nil, err
+	}
+	if email == nil {
+		return nil, fmt.Errorf("%w: email %s", types.ErrNotFound, id)
+	}
+
+	return s.db.AggregateEmailClicks(ctx, email.ID)
+}
+
+func (s *TrackingService) CategoryClicks(ctx context.Context, request *models.CategoryClicksRequest) (*models.CategoryClicksResponse, error) {
+	if !request.Since.IsZero() && !request.Until.IsZero() && !request.Until.After(request.Since) {
+		return nil, fmt.Errorf("%w: until must be after since", types.ErrInvalidArgument)
+	}
+
+	categories, err := s.db.AggregateCategoryClicks(ctx, auth.PrincipalFromContext(ctx).TenantID(), request.Category, request.Since, request.Until, topCategoryLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CategoryClicksResponse{Categories: categories}, nil
+}
+
+// trackedRecipient looks up the email and recipient a tracking token was issued for
+func (s *TrackingService) trackedRecipient(ctx context.Context, emailID, address string) (*models.Email, *models.EmailRecipient, error) {
+	email, err := s.db.GetEmailByID(ctx, emailID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if email == nil {
+		return nil, nil, fmt.Errorf("%w: email %s", types.ErrNotFound, emailID)
+	}
+
+	for i := range email.Recipients {
+		if normalizeAddress(email.Recipients[i].Address) == address {
+			return email, &email.Recipients[i], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s is not a recipient of email %s", types.ErrNotFound, address, emailID)
+}
+
+// prefetchReason tells why an open or click looks like a privacy proxy or scanner rather than a
+// person, or returns "". Apple Mail Privacy Protection loads every image on delivery through Apple's
+// proxies with a bare user agent, link scanners follow links within seconds of sending.
+func (s *TrackingService) prefetchReason(recipient *models.EmailRecipient, userAgent, ip string, now time.Time, action string) string {
+	if strings.TrimSpace(userAgent) == "Mozilla/5.0" {
+		return "privacy proxy user agent"
+	}
+
+	if addr, err := netip.ParseAddr(ip); err == nil && appleNetwork.Contains(addr.Unmap()) {
+		return "Apple privacy proxy address"
+	}
+
+	if recipient.Status == models.RecipientPending {
+		return action + " before the send completed"
+	}
+
+	window := time.Duration(s.cfg.Tracking.PrefetchWindow) * time.Second
+	if elapsed := now.Sub(recipient.SentAt); !recipient.SentAt.IsZero() && elapsed < window {
+		return fmt.Sprintf("%s %s after sending", action, elapsed.Round(time.Second))
+	}
+
+	return ""
+}
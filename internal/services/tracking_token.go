@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	xhtml "golang.org/x/net/html"
+)
+
+const trackingTokenVersion = "v1"
+
+// trackingSigner issues and verifies the per-recipient tokens used in tracking URLs
+type trackingSigner struct {
+	cfg config.TrackingConfig
+}
+
+func newTrackingSigner(cfg *config.Config) *trackingSigner {
+	return &trackingSigner{cfg: cfg.Tracking}
+}
+
+func (s *trackingSigner) enabled() bool {
+	return s.cfg.BaseURL != "" && s.cfg.Secret != ""
+}
+
+// openURL returns the tracking pixel URL for one recipient of an email
+func (s *trackingSigner) openURL(emailID bson.ObjectID, address string) string {
+	return fmt.Sprintf("%s/t/open/%s", strings.TrimRight(s.cfg.BaseURL, "/"), s.sign(emailID.Hex(), normalizeAddress(address)))
+}
+
+// clickURL returns the redirect URL for one recipient's click on target. The target is part of the
+// signed token, so the redirect can't be pointed anywhere else.
+func (s *trackingSigner) clickURL(emailID bson.ObjectID, address, target string) string {
+	return fmt.Sprintf("%s/t/click/%s", strings.TrimRight(s.cfg.BaseURL, "/"), s.sign(emailID.Hex(), normalizeAddress(address), target))
+}
+
+// sign encodes the fields into a token, only the last field may contain '|'
+func (s *trackingSigner) sign(fields ...string) string {
+	payload := strings.Join(append([]string{trackingTokenVersion}, fields...), "|")
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	return encoded + "." + s.mac(encoded)
+}
+
+// verify checks the token signature and returns its fields, which must number count
+func (s *trackingSigner) verify(token string, count int) ([]string, error) {
+	if s.cfg.Secret == "" {
+		return nil, fmt.Errorf("tracking is not configured")
+	}
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.mac(encoded))) {
+		return nil, fmt.Errorf("invalid tracking token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracking token")
+	}
+
+	fields := strings.SplitN(string(payload), "|", count+1)
+	if len(fields) != count+1 || fields[0] != trackingTokenVersion {
+		return nil, fmt.Errorf("invalid tracking token")
+	}
+
+	return fields[1:], nil
+}
+
+// hashIP keys opens by address without storing it
+func (s *trackingSigner) hashIP(ip string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write([]byte(ip))
+
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func (s *trackingSigner) mac(encoded string) string {
+	mac := hmac.New(sha256.New, []byte("tracking:"+s.cfg.Secret))
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// injectOpenPixel adds a 1x1 image loading url just before </body>, or at the end of
+// bodies without one
+func injectOpenPixel(body, url string) string {
+	pixel := `<img src="` + html.EscapeString(url) + `" width="1" height="1" alt="" style="display:block;width:1px;height:1px;border:0" />`
+
+	const closingTag = "</body>"
+	for i := len(body) - len(closingTag); i >= 0; i-- {
+		if strings.EqualFold(body[i:i+len(closingTag)], closingTag) {
+			return body[:i] + pixel + body[i:]
+		}
+	}
+
+	return body + pixel
+}
+
+// rewriteLinks points the tracked links of an HTML body at the click redirect, other markup is
+// copied byte for byte
+func (s *trackingSigner) rewriteLinks(body string, emailID bson.ObjectID, address string) string {
+	var out bytes.Buffer
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(body))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == xhtml.ErrorToken {
+			if tokenizer.Err() != io.EOF {
+				// Unparseable input, leave the body as it was
+				return body
+			}
+			return out.String()
+		}
+
+		// Raw is only valid until the next call to the tokenizer
+		raw := string(tokenizer.Raw())
+		if tokenType != xhtml.StartTagToken && tokenType != xhtml.SelfClosingTagToken {
+			out.WriteString(raw)
+			continue
+		}
+
+		token := tokenizer.Token()
+		if token.DataAtom.String() != "a" || !s.rewriteHref(&token, emailID, address) {
+			out.WriteString(raw)
+			continue
+		}
+		out.WriteString(token.String())
+	}
+}
+
+// rewriteHref replaces the anchor's href with a click URL, returns false for links that aren't
+// tracked
+func (s *trackingSigner) rewriteHref(token *xhtml.Token, emailID bson.ObjectID, address string) bool {
+	href := -1
+	for i, attr := range token.Attr {
+		switch attr.Key {
+		case "href":
+			href = i
+		case "data-notiflow-notrack":
+			return false
+		}
+	}
+	if href < 0 || !s.trackable(token.Attr[href].Val) {
+		return false
+	}
+
+	token.Attr[href].Val = s.clickURL(emailID, address, strings.TrimSpace(token.Attr[href].Val))
+	return true
+}
+
+// trackable tells whether a link should go through the click redirect. Only absolute http(s) links
+// are tracked, never unsubscribe links, links to this service or to excluded domains.
+func (s *trackingSigner) trackable(href string) bool {
+	target, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return false
+	}
+
+	if strings.Contains(strings.ToLower(href), "unsubscribe") {
+		return false
+	}
+
+	host := strings.ToLower(target.Hostname())
+	if base, err := url.Parse(s.cfg.BaseURL); err == nil && strings.EqualFold(base.Hostname(), host) {
+		return false
+	}
+
+	for _, domain := range s.cfg.ExcludedDomains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+type UnsubscribeService struct {
+	db     *database.Database
+	cfg    *config.Config
+	signer *unsubscribeSigner
+}
+
+func NewUnsubscribeService(db *database.Database, cfg *config.Config) types.UnsubscribeService {
+	return &UnsubscribeService{
+		db:     db,
+		cfg:    cfg,
+		signer: newUnsubscribeSigner(cfg),
+	}
+}
+
+func (s *UnsubscribeService) Verify(token string) (*models.UnsubscribeToken, error) {
+	unsubscribe, err := s.signer.verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrInvalidArgument, err)
+	}
+
+	return unsubscribe, nil
+}
+
+func (s *UnsubscribeService) Unsubscribe(ctx context.Context, token string) (*models.UnsubscribeToken, error) {
+	unsubscribe, err := s.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Links are only issued for bulk categories, but the config may have changed since
+	if s.cfg.Preferences.IsMandatory(unsubscribe.Category) {
+		return nil, fmt.Errorf("%w: category %s is mandatory", types.ErrInvalidArgument, unsubscribe.Category)
+	}
+
+	// Links issued before tokens carried the tenant belong to the tenant of their email
+	if unsubscribe.Tenant == "" {
+		unsubscribe.Tenant = models.DefaultTenant
+		email, err := s.db.GetEmailByID(ctx, unsubscribe.EmailID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if email != nil && email.Tenant != "" {
+			unsubscribe.Tenant = email.Tenant
+		}
+	}
+
+	_, err = s.db.SetPreferenceRule(ctx, unsubscribe.Tenant, unsubscribe.Address, models.PreferenceRule{
+		Category: unsubscribe.Category,
+		Channel:  string(models.ChannelEmail),
+		OptedIn:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishWebhookEvent(ctx, s.db, &models.WebhookEvent{
+		Type:      models.WebhookEmailUnsubscribed,
+		Tenant:    unsubscribe.Tenant,
+		EmailID:   unsubscribe.EmailID.Hex(),
+		Recipient: unsubscribe.Address,
+		Category:  unsubscribe.Category,
+	})
+
+	slog.Info("Recipient unsubscribed", "email_id", unsubscribe.EmailID.Hex(), "category", unsubscribe.Category)
+
+	return unsubscribe, nil
+}
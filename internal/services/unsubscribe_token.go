@@ -0,0 +1,99 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Version 2 tokens add the tenant, version 1 links in emails already sent stay valid
+const (
+	unsubscribeTokenVersion       = "v2"
+	legacyUnsubscribeTokenVersion = "v1"
+)
+
+// unsubscribeSigner issues and verifies the per-recipient tokens used in unsubscribe links
+type unsubscribeSigner struct {
+	cfg config.UnsubscribeConfig
+}
+
+func newUnsubscribeSigner(cfg *config.Config) *unsubscribeSigner {
+	return &unsubscribeSigner{cfg: cfg.Unsubscribe}
+}
+
+func (s *unsubscribeSigner) enabled() bool {
+	return s.cfg.BaseURL != "" && s.cfg.Secret != ""
+}
+
+// headers returns the RFC 2369 / RFC 8058 headers for one recipient of a bulk email,
+// or nil if the email isn't bulk or unsubscribe links aren't configured
+func (s *unsubscribeSigner) headers(email *models.Email, address string) map[string]string {
+	if !s.enabled() || !s.cfg.IsBulk(email.Category) {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/u/%s", strings.TrimRight(s.cfg.BaseURL, "/"), s.sign(email.ID, email.Tenant, address, email.Category))
+
+	return map[string]string{
+		"List-Unsubscribe":      "<" + url + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+func (s *unsubscribeSigner) sign(emailID bson.ObjectID, tenant, address, category string) string {
+	payload := strings.Join([]string{unsubscribeTokenVersion, emailID.Hex(), normalizeAddress(address), category, tenant}, "|")
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	return encoded + "." + s.mac(encoded)
+}
+
+// verify checks the token signature and returns the unsubscribe it encodes
+func (s *unsubscribeSigner) verify(token string) (*models.UnsubscribeToken, error) {
+	if s.cfg.Secret == "" {
+		return nil, fmt.Errorf("unsubscribe links are not configured")
+	}
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.mac(encoded))) {
+		return nil, fmt.Errorf("invalid unsubscribe token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid unsubscribe token")
+	}
+
+	parts := strings.Split(string(payload), "|")
+	switch {
+	case len(parts) == 5 && parts[0] == unsubscribeTokenVersion && parts[4] != "":
+	case len(parts) == 4 && parts[0] == legacyUnsubscribeTokenVersion:
+		parts = append(parts, "")
+	default:
+		return nil, fmt.Errorf("invalid unsubscribe token")
+	}
+
+	emailID, err := bson.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid unsubscribe token")
+	}
+
+	return &models.UnsubscribeToken{
+		EmailID:  emailID,
+		Tenant:   parts[4],
+		Address:  parts[2],
+		Category: parts[3],
+	}, nil
+}
+
+func (s *unsubscribeSigner) mac(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+)
+
+type UsageService struct {
+	db      *database.Database
+	tenants *TenantDirectory
+	limiter *SendLimiter
+}
+
+func NewUsageService(db *database.Database, tenants *TenantDirectory, limiter *SendLimiter) types.UsageService {
+	return &UsageService{
+		db:      db,
+		tenants: tenants,
+		limiter: limiter,
+	}
+}
+
+func (s *UsageService) GetUsage(ctx context.Context, request *models.UsageRequest) (*models.UsageResponse, error) {
+	principal := auth.PrincipalFromContext(ctx)
+	tenantID := principal.TenantID()
+	if request.Tenant != "" && request.Tenant != tenantID {
+		if !principal.IsPlatform() {
+			return nil, fmt.Errorf("%w: usage of another tenant", types.ErrPermissionDenied)
+		}
+		tenantID = request.Tenant
+	}
+
+	tenant, err := s.tenants.Lookup(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, fmt.Errorf("%w: tenant %s", types.ErrNotFound, tenantID)
+	}
+
+	start := time.Now().UTC()
+	if request.Month != "" {
+		if start, err = time.Parse(usageMonthLayout, request.Month); err != nil {
+			return nil, fmt.Errorf("%w: month must be YYYY-MM", types.ErrInvalidArgument)
+		}
+	}
+	month := start.Format(usageMonthLayout)
+
+	usage, err := s.db.GetUsage(ctx, tenant.ID, month)
+	if err != nil {
+		return nil, err
+	}
+	if usage == nil {
+		usage = &models.Usage{}
+	}
+
+	response := &models.UsageResponse{
+		Tenant:     tenant.ID,
+		Month:      month,
+		Emails:     usage.Emails,
+		Recipients: usage.Recipients,
+		Quota:      s.limiter.MonthlyQuota(tenant),
+		ResetsAt:   nextMonth(start),
+	}
+	if response.Quota > 0 {
+		remaining := max(response.Quota-usage.Emails, 0)
+		response.Remaining = &remaining
+	}
+
+	return response, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/metrics"
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+const (
+	// How long a claimed delivery stays locked to one dispatcher before another instance may pick it up
+	webhookLease = time.Minute
+	// Retries back off exponentially from the first delay up to the cap
+	webhookFirstRetryDelay = 30 * time.Second
+	webhookMaxRetryDelay   = 6 * time.Hour
+	// Part of a failed response's body kept in the attempt log
+	webhookResponseSnippet = 500
+)
+
+// WebhookDispatcher POSTs queued events to subscriber endpoints, retrying failures with backoff
+type WebhookDispatcher struct {
+	db         *database.Database
+	cfg        *config.Config
+	httpClient *http.Client
+}
+
+func NewWebhookDispatcher(db *database.Database, cfg *config.Config) *WebhookDispatcher {
+	timeout := time.Duration(cfg.Webhooks.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &WebhookDispatcher{
+		db:         db,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	interval := time.Duration(d.cfg.Webhooks.CheckInterval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	slog.Info("Starting webhook dispatcher", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchDue attempts every delivery that is due
+func (d *WebhookDispatcher) dispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := d.db.ClaimWebhookDelivery(ctx, time.Now(), webhookLease)
+		if err != nil || delivery == nil {
+			return
+		}
+
+		d.deliver(ctx, delivery)
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	endpoint, err := d.db.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		return
+	}
+
+	attempt := models.WebhookAttempt{AttemptedAt: time.Now()}
+	if endpoint == nil || !endpoint.Enabled {
+		// Nothing to retry against, the event can be redelivered once the endpoint is enabled again
+		attempt.Error = "endpoint disabled"
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.CompletedAt = attempt.AttemptedAt
+		_ = d.db.RecordWebhookAttempt(ctx, delivery, attempt)
+		return
+	}
+
+	attempt.StatusCode, err = d.post(ctx, endpoint, delivery)
+	attempt.DurationMs = time.Since(attempt.AttemptedAt).Milliseconds()
+	delivery.AttemptCount++
+
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.CompletedAt = time.Now()
+	case delivery.AttemptCount >= d.cfg.Webhooks.MaxAttempts:
+		attempt.Error = err.Error()
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.CompletedAt = time.Now()
+		slog.Warn("Giving up webhook delivery", "delivery_id", delivery.ID.Hex(), "endpoint_id", endpoint.ID.Hex(), "attempts", delivery.AttemptCount, "error", err)
+	default:
+		attempt.Error = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(webhookRetryDelay(delivery.AttemptCount))
+		metrics.CountRetry(metrics.RetryWebhook)
+	}
+
+	if err = d.db.RecordWebhookAttempt(ctx, delivery, attempt); err != nil {
+		return
+	}
+
+	disabled, err := d.db.RecordWebhookEndpointResult(ctx, endpoint.ID, attempt.Error == "", d.cfg.Webhooks.DisableAfterFailures)
+	if err == nil && disabled {
+		slog.Warn("Disabled failing webhook endpoint", "endpoint_id", endpoint.ID.Hex(), "url", endpoint.URL)
+	}
+}
+
+// post sends the stored payload and returns the response status code
+func (d *WebhookDispatcher) post(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "notiflow")
+	request.Header.Set("X-Notiflow-Event", string(delivery.EventType))
+	request.Header.Set("X-Notiflow-Delivery", delivery.ID.Hex())
+	request.Header.Set("X-Notiflow-Signature", signPayload(endpoint.Secret, body))
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(response.Body, webhookResponseSnippet))
+		return response.StatusCode, fmt.Errorf("responded with %d: %s", response.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	return response.StatusCode, nil
+}
+
+// webhookRetryDelay is the wait before the next attempt after the given number of failed attempts
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookFirstRetryDelay
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, webhookMaxRetryDelay)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aarondever/notiflow/internal/auth"
+	"github.com/aarondever/notiflow/internal/database"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const defaultWebhookDeliveryPageSize = 20
+
+type WebhookService struct {
+	db *database.Database
+}
+
+func NewWebhookService(db *database.Database) types.WebhookService {
+	return &WebhookService{
+		db: db,
+	}
+}
+
+func (s *WebhookService) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	endpoint.Secret = "whsec_" + hex.EncodeToString(secret)
+	endpoint.Tenant = auth.PrincipalFromContext(ctx).TenantID()
+
+	return s.db.CreateWebhookEndpoint(ctx, endpoint)
+}
+
+func (s *WebhookService) ListEndpoints(ctx context.Context) ([]*models.WebhookEndpoint, error) {
+	return s.db.ListWebhookEndpoints(ctx, auth.PrincipalFromContext(ctx).TenantID())
+}
+
+func (s *WebhookService) GetEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.getEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, id string, endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	endpointID, err := parseObjectID(id, "webhook endpoint")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.ID = endpointID
+	endpoint.Tenant = auth.PrincipalFromContext(ctx).TenantID()
+	updated, err := s.db.UpdateWebhookEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: webhook endpoint %s", types.ErrNotFound, id)
+	}
+
+	return s.GetEndpoint(ctx, id)
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id string) error {
+	endpointID, err := parseObjectID(id, "webhook endpoint")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.db.DeleteWebhookEndpoint(ctx, auth.PrincipalFromContext(ctx).TenantID(), endpointID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("%w: webhook endpoint %s", types.ErrNotFound, id)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, endpointID string, status models.WebhookDeliveryStatus, before string, limit int64) ([]*models.WebhookDelivery, error) {
+	endpoint, err := s.getEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	var beforeID bson.ObjectID
+	if before != "" {
+		if beforeID, err = parseObjectID(before, "cursor"); err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryPageSize
+	}
+
+	return s.db.ListWebhookDeliveries(ctx, endpoint.ID, status, beforeID, limit)
+}
+
+func (s *WebhookService) GetDelivery(ctx context.Context, endpointID, id string) (*models.WebhookDelivery, error) {
+	endpoint, err := s.getEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryID, err := parseObjectID(id, "webhook delivery")
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.db.GetWebhookDelivery(ctx, endpoint.ID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, fmt.Errorf("%w: webhook delivery %s", types.ErrNotFound, id)
+	}
+
+	return delivery, nil
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, endpointID, id string) (*models.WebhookDelivery, error) {
+	delivery, err := s.GetDelivery(ctx, endpointID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	redelivery := &models.WebhookDelivery{
+		EndpointID:   delivery.EndpointID,
+		EventID:      delivery.EventID,
+		EventType:    delivery.EventType,
+		Payload:      delivery.Payload,
+		Status:       models.WebhookDeliveryPending,
+		RedeliveryOf: &delivery.ID,
+	}
+	if err = s.db.CreateWebhookDeliveries(ctx, []*models.WebhookDelivery{redelivery}); err != nil {
+		return nil, err
+	}
+
+	return redelivery, nil
+}
+
+// getEndpoint returns the caller's tenant's endpoint, other tenants' endpoints are reported as not found
+func (s *WebhookService) getEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	endpointID, err := parseObjectID(id, "webhook endpoint")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.db.GetWebhookEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil || endpoint.Tenant != auth.PrincipalFromContext(ctx).TenantID() {
+		return nil, fmt.Errorf("%w: webhook endpoint %s", types.ErrNotFound, id)
+	}
+
+	return endpoint, nil
+}
+
+func parseObjectID(id, name string) (bson.ObjectID, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return bson.NilObjectID, fmt.Errorf("%w: invalid %s ID", types.ErrInvalidArgument, name)
+	}
+
+	return objectID, nil
+}
+
+// publishWebhookEvent queues the event for every enabled endpoint of its tenant subscribed to its type. Subscribers
+// are notified on a best-effort basis, failing to queue never fails the state transition itself.
+func publishWebhookEvent(ctx context.Context, db *database.Database, event *models.WebhookEvent) {
+	endpoints, err := db.ListWebhookEndpointsForEvent(ctx, event.Tenant, event.Type)
+	if err != nil || len(endpoints) == 0 {
+		return
+	}
+
+	event.ID = bson.NewObjectID().Hex()
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to encode webhook event", "error", err)
+		return
+	}
+
+	deliveries := make([]*models.WebhookDelivery, len(endpoints))
+	for i, endpoint := range endpoints {
+		deliveries[i] = &models.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			EventID:    event.ID,
+			EventType:  event.Type,
+			Payload:    string(payload),
+			Status:     models.WebhookDeliveryPending,
+		}
+	}
+
+	_ = db.CreateWebhookDeliveries(ctx, deliveries)
+}
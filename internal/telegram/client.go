@@ -0,0 +1,191 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxMessageLength is the Bot API limit for the text of a single message
+	MaxMessageLength = 4096
+	// MaxCaptionLength is the Bot API limit for a document caption
+	MaxCaptionLength = 1024
+
+	defaultMaxAttempts = 3
+)
+
+// APIError is returned when the Bot API answers with "ok": false
+type APIError struct {
+	Code        int
+	Description string
+	RetryAfter  time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram: %d %s", e.Code, e.Description)
+}
+
+type Client struct {
+	baseURL     string
+	token       string
+	httpClient  *http.Client
+	maxAttempts int
+}
+
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		token:       token,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		maxAttempts: defaultMaxAttempts,
+	}
+}
+
+type apiResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// SendMessage sends text to a chat, splitting it into several messages when it exceeds MaxMessageLength
+func (c *Client) SendMessage(ctx context.Context, chatID, text, parseMode string) error {
+	for _, chunk := range SplitText(text, MaxMessageLength, parseMode) {
+		fields := map[string]string{
+			"chat_id": chatID,
+			"text":    chunk,
+		}
+		if parseMode != "" {
+			fields["parse_mode"] = parseMode
+		}
+
+		err := c.call(ctx, "sendMessage", func() (io.Reader, string, error) {
+			body, err := json.Marshal(fields)
+			return bytes.NewReader(body), "application/json", err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendDocument uploads a file to a chat. Captions longer than MaxCaptionLength are sent as follow-up messages.
+func (c *Client) SendDocument(ctx context.Context, chatID, filename string, content []byte, caption, parseMode string) error {
+	var followUp string
+	if len([]rune(caption)) > MaxCaptionLength {
+		followUp, caption = caption, ""
+	}
+
+	err := c.call(ctx, "sendDocument", func() (io.Reader, string, error) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+
+		_ = writer.WriteField("chat_id", chatID)
+		if caption != "" {
+			_ = writer.WriteField("caption", caption)
+			if parseMode != "" {
+				_ = writer.WriteField("parse_mode", parseMode)
+			}
+		}
+
+		part, err := writer.CreateFormFile("document", filename)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err = part.Write(content); err != nil {
+			return nil, "", err
+		}
+		if err = writer.Close(); err != nil {
+			return nil, "", err
+		}
+
+		return &body, writer.FormDataContentType(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if followUp != "" {
+		return c.SendMessage(ctx, chatID, followUp, parseMode)
+	}
+
+	return nil
+}
+
+// call invokes a Bot API method, waiting and retrying when the API answers 429 with retry_after
+func (c *Client) call(ctx context.Context, method string, newBody func() (io.Reader, string, error)) error {
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+
+	var lastErr error
+	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
+		body, contentType, err := newBody()
+		if err != nil {
+			return err
+		}
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Content-Type", contentType)
+
+		response, err := c.httpClient.Do(request)
+		if err != nil {
+			// Never log the URL, it contains the bot token
+			return fmt.Errorf("telegram: %s request failed", method)
+		}
+
+		var result apiResponse
+		err = json.NewDecoder(response.Body).Decode(&result)
+		response.Body.Close()
+		if err != nil {
+			return fmt.Errorf("telegram: failed to decode %s response: %w", method, err)
+		}
+
+		if result.OK {
+			return nil
+		}
+
+		apiErr := &APIError{
+			Code:        result.ErrorCode,
+			Description: result.Description,
+			RetryAfter:  time.Duration(result.Parameters.RetryAfter) * time.Second,
+		}
+		if apiErr.Code == 0 {
+			apiErr.Code = response.StatusCode
+		}
+		if apiErr.RetryAfter == 0 {
+			if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+				apiErr.RetryAfter = time.Duration(seconds) * time.Second
+			}
+		}
+
+		if apiErr.Code != http.StatusTooManyRequests || attempt == c.maxAttempts {
+			return apiErr
+		}
+
+		lastErr = apiErr
+		slog.Warn("Telegram rate limit hit, retrying", "method", method, "retry_after", apiErr.RetryAfter, "attempt", attempt)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(apiErr.RetryAfter):
+		}
+	}
+
+	return lastErr
+}
@@ -0,0 +1,408 @@
+package telegram
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Markers that open and close formatting, longest first so "__" isn't read as two "_"
+var (
+	markdownMarkers   = []string{"*", "_"}
+	markdownV2Markers = []string{"||", "__", "*", "_", "~"}
+)
+
+// entity is formatting open at some point of the text, with the markup that opens and closes it
+type entity struct {
+	open, close string
+	code        bool // Whitespace is part of the content
+}
+
+// markup is a span of the text that can't be split, such as an HTML tag, a character reference,
+// a Markdown marker, escape or link, with the formatting open after it
+type markup struct {
+	start, end int // Rune offsets
+	open       []entity
+	visible    bool // Shown as text, tags and markers aren't
+}
+
+// cut is where a chunk ends
+type cut struct {
+	end  int
+	open []entity
+	span int // Index of the first markup span at or after end
+}
+
+// SplitText splits text into chunks of at most limit characters, preferring line and word boundaries.
+// With an HTML, Markdown or MarkdownV2 parse mode it never splits inside a tag, character reference,
+// escape or link, and closes the formatting open at the end of a chunk and reopens it in the next one,
+// so every chunk parses on its own.
+func SplitText(text string, limit int, parseMode string) []string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return []string{text}
+	}
+
+	var spans []markup
+	switch parseMode {
+	case "HTML":
+		spans = scanHTML(runes)
+	case "Markdown":
+		spans = scanMarkdown(runes, markdownMarkers, false)
+	case "MarkdownV2":
+		spans = scanMarkdown(runes, markdownV2Markers, true)
+	}
+
+	var chunks []string
+	var open []entity
+	start, span := 0, 0
+	for {
+		prefix := opening(open)
+		length := len([]rune(prefix))
+
+		var newline, space, last *cut
+		current := open
+		visible := false
+		pos, next := start, span
+		for pos < len(runes) {
+			end, after, shown, inSpan := pos+1, current, true, false
+			if next < len(spans) && spans[next].start == pos {
+				end, after, shown, inSpan = spans[next].end, spans[next].open, spans[next].visible, true
+			} else if visible && (runes[pos] == '\n' || runes[pos] == ' ') {
+				separator := &cut{end: pos, open: current, span: next}
+				if runes[pos] == '\n' {
+					newline = separator
+				} else {
+					space = separator
+				}
+			}
+
+			if length+end-pos+closingLength(after) > limit {
+				break
+			}
+
+			length += end - pos
+			current = after
+			visible = visible || shown
+			if inSpan {
+				next++
+			}
+			pos = end
+
+			if visible {
+				last = &cut{end: pos, open: current, span: next}
+			}
+		}
+
+		if pos == len(runes) {
+			return append(chunks, prefix+string(runes[start:]))
+		}
+
+		split := newline
+		if split == nil {
+			split = space
+		}
+		if split == nil {
+			split = last
+		}
+		if split == nil {
+			// Not even one character fits next to the formatting, send the first piece on its own
+			split = forcedCut(runes, spans, start, span, open)
+		}
+		chunks = append(chunks, prefix+string(runes[start:split.end])+closing(split.open))
+		start, span, open = split.end, split.span, split.open
+
+		// Drop the separator we split on so the next chunk doesn't start with it, spaces in code are content
+		if start < len(runes) && (runes[start] == '\n' || runes[start] == ' ' && !inCode(open)) {
+			start++
+		}
+		if start == len(runes) {
+			return chunks
+		}
+	}
+}
+
+func inCode(open []entity) bool {
+	return len(open) > 0 && open[len(open)-1].code
+}
+
+// forcedCut ends a chunk after the first character or markup span at start
+func forcedCut(runes []rune, spans []markup, start, span int, open []entity) *cut {
+	if span < len(spans) && spans[span].start == start {
+		return &cut{end: spans[span].end, open: spans[span].open, span: span + 1}
+	}
+
+	return &cut{end: start + 1, open: open, span: span}
+}
+
+// opening returns the markup that reopens the formatting in order
+func opening(open []entity) string {
+	var markup strings.Builder
+	for _, e := range open {
+		markup.WriteString(e.open)
+	}
+
+	return markup.String()
+}
+
+// closing returns the markup that closes the formatting, innermost first
+func closing(open []entity) string {
+	var markup strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		markup.WriteString(open[i].close)
+	}
+
+	return markup.String()
+}
+
+func closingLength(open []entity) int {
+	length := 0
+	for _, e := range open {
+		length += utf8.RuneCountInString(e.close)
+	}
+
+	return length
+}
+
+// push returns open with e added, never sharing the array of an earlier span's formatting
+func push(open []entity, e entity) []entity {
+	return append(open[:len(open):len(open)], e)
+}
+
+// remove returns open without the innermost entity opened by marker, and whether there was one
+func remove(open []entity, marker string) ([]entity, bool) {
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i].open == marker {
+			return append(open[:i:i], open[i+1:]...), true
+		}
+	}
+
+	return open, false
+}
+
+// scanHTML finds the tags and character references of Telegram's HTML style
+func scanHTML(runes []rune) []markup {
+	var spans []markup
+	var open []entity
+	for i := 0; i < len(runes); {
+		switch runes[i] {
+		case '<':
+			end := indexFrom(runes, i, '>')
+			if end < 0 {
+				i++
+				continue
+			}
+			end++
+
+			tag := string(runes[i:end])
+			name := strings.FieldsFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || strings.ContainsRune("</>", r) })
+			if len(name) == 0 {
+				i++
+				continue
+			}
+
+			if strings.HasPrefix(tag, "</") {
+				if len(open) > 0 {
+					open = open[:len(open)-1]
+				}
+			} else {
+				open = push(open, entity{open: tag, close: "</" + name[0] + ">", code: name[0] == "pre" || name[0] == "code"})
+			}
+
+			spans = append(spans, markup{start: i, end: end, open: open})
+			i = end
+		case '&':
+			end := referenceEnd(runes, i)
+			if end < 0 {
+				i++
+				continue
+			}
+
+			spans = append(spans, markup{start: i, end: end, open: open, visible: true})
+			i = end
+		default:
+			i++
+		}
+	}
+
+	return spans
+}
+
+// referenceEnd returns the end of the character reference such as "&lt;" or "&#128512;" at i, or -1
+func referenceEnd(runes []rune, i int) int {
+	for j := i + 1; j < len(runes) && j <= i+32; j++ {
+		switch r := runes[j]; {
+		case r == ';':
+			if j == i+1 {
+				return -1
+			}
+			return j + 1
+		case r == '#' && j == i+1, r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+		default:
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// scanMarkdown finds the markers, code blocks, escapes and links of Telegram's Markdown styles. The legacy
+// style only escapes the characters that start formatting, MarkdownV2 escapes any character.
+func scanMarkdown(runes []rune, markers []string, v2 bool) []markup {
+	var spans []markup
+	var open []entity
+	for i := 0; i < len(runes); {
+		if end := escapeEnd(runes, i, v2); end > 0 {
+			spans = append(spans, markup{start: i, end: end, open: open, visible: true})
+			i = end
+			continue
+		}
+
+		switch {
+		case hasPrefix(runes, i, "```"), runes[i] == '`':
+			delimiter := "`"
+			if hasPrefix(runes, i, "```") {
+				delimiter = "```"
+			}
+
+			// Code is literal up to the closing delimiter, a block keeps its language line with the delimiter
+			openEnd := i + len(delimiter)
+			if delimiter == "```" {
+				if newline := indexFrom(runes, openEnd, '\n'); newline > 0 && !strings.ContainsFunc(string(runes[openEnd:newline]), isNotLanguage) {
+					openEnd = newline + 1
+				}
+			}
+
+			closeStart := -1
+			var escapes []markup
+			for j := openEnd; j < len(runes); j++ {
+				// Legacy Markdown has no escapes inside entities
+				if end := escapeEnd(runes, j, v2); end > 0 && v2 {
+					escapes = append(escapes, markup{start: j, end: end, visible: true})
+					j = end - 1
+					continue
+				}
+				if hasPrefix(runes, j, delimiter) {
+					closeStart = j
+					break
+				}
+			}
+			if closeStart < 0 {
+				i++
+				continue
+			}
+
+			code := push(open, entity{open: string(runes[i:openEnd]), close: delimiter, code: true})
+			spans = append(spans, markup{start: i, end: openEnd, open: code})
+			for _, escape := range escapes {
+				escape.open = code
+				spans = append(spans, escape)
+			}
+			spans = append(spans, markup{start: closeStart, end: closeStart + len(delimiter), open: open})
+			i = closeStart + len(delimiter)
+		case runes[i] == '[', v2 && hasPrefix(runes, i, "!["):
+			end := linkEnd(runes, i, v2)
+			if end < 0 {
+				i++
+				continue
+			}
+
+			spans = append(spans, markup{start: i, end: end, open: open, visible: true})
+			i = end
+		default:
+			marker := ""
+			for _, m := range markers {
+				if hasPrefix(runes, i, m) {
+					marker = m
+					break
+				}
+			}
+			if marker == "" {
+				i++
+				continue
+			}
+
+			var closed bool
+			if open, closed = remove(open, marker); !closed {
+				open = push(open, entity{open: marker, close: marker})
+			}
+
+			spans = append(spans, markup{start: i, end: i + len(marker), open: open})
+			i += len(marker)
+		}
+	}
+
+	return spans
+}
+
+// escapeEnd returns the end of the backslash escape at i, or -1
+func escapeEnd(runes []rune, i int, v2 bool) int {
+	if runes[i] != '\\' || i+1 == len(runes) {
+		return -1
+	}
+	if !v2 && !strings.ContainsRune("_*`[", runes[i+1]) {
+		return -1
+	}
+
+	return i + 2
+}
+
+// linkEnd returns the end of the "[text](url)" link at i, or -1
+func linkEnd(runes []rune, i int, v2 bool) int {
+	j := i
+	if runes[j] == '!' {
+		j++
+	}
+
+	text := -1
+	for j++; j < len(runes); j++ {
+		if end := escapeEnd(runes, j, v2); end > 0 && v2 {
+			j = end - 1
+			continue
+		}
+		if runes[j] == ']' {
+			text = j
+			break
+		}
+	}
+	if text < 0 || !hasPrefix(runes, text, "](") {
+		return -1
+	}
+
+	for j = text + 2; j < len(runes); j++ {
+		if end := escapeEnd(runes, j, v2); end > 0 && v2 {
+			j = end - 1
+			continue
+		}
+		if runes[j] == ')' {
+			return j + 1
+		}
+	}
+
+	return -1
+}
+
+func isNotLanguage(r rune) bool {
+	return unicode.IsSpace(r) || r == '`'
+}
+
+func hasPrefix(runes []rune, i int, prefix string) bool {
+	for _, r := range prefix {
+		if i == len(runes) || runes[i] != r {
+			return false
+		}
+		i++
+	}
+
+	return true
+}
+
+func indexFrom(runes []rune, i int, r rune) int {
+	for j := i; j < len(runes); j++ {
+		if runes[j] == r {
+			return j
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,117 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		limit     int
+		parseMode string
+		want      []string
+	}{
+		{
+			name:  "fits",
+			text:  "short <b>message</b>",
+			limit: 100,
+			want:  []string{"short <b>message</b>"},
+		},
+		{
+			name:  "plain text on line and word boundaries",
+			text:  "hello world this is plain text\nwith lines",
+			limit: 12,
+			want:  []string{"hello world", "this is", "plain text", "with lines"},
+		},
+		{
+			name:  "plain text without boundaries",
+			text:  "abcdefghij",
+			limit: 4,
+			want:  []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:      "HTML tags are closed and reopened",
+			text:      "<b>bold words that go on and on</b> and more",
+			limit:     20,
+			parseMode: "HTML",
+			want:      []string{"<b>bold words</b>", "<b>that go on</b>", "<b>and on</b> and", "more"},
+		},
+		{
+			name:      "HTML nested tags and links",
+			text:      `<b>bold <a href="https://x.io">link text</a></b>`,
+			limit:     38,
+			parseMode: "HTML",
+			want:      []string{`<b>bold</b>`, `<b><a href="https://x.io">link</a></b>`, `<b><a href="https://x.io">text</a></b>`},
+		},
+		{
+			name:      "HTML character references stay whole",
+			text:      "a&amp;b&lt;c&gt;d",
+			limit:     9,
+			parseMode: "HTML",
+			want:      []string{"a&amp;b", "&lt;c&gt;", "d"},
+		},
+		{
+			name:      "HTML code keeps its whitespace",
+			text:      "<pre>one two three</pre>",
+			limit:     17,
+			parseMode: "HTML",
+			want:      []string{"<pre>one</pre>", "<pre> two</pre>", "<pre> three</pre>"},
+		},
+		{
+			name:      "MarkdownV2 markers are closed and reopened",
+			text:      "*bold words that go _on and_ on* end",
+			limit:     20,
+			parseMode: "MarkdownV2",
+			want:      []string{"*bold words that go*", "*_on and_ on* end"},
+		},
+		{
+			name:      "MarkdownV2 escapes and links stay whole",
+			text:      `ab\.cd [link](http://x.y/z) ~strike~`,
+			limit:     5,
+			parseMode: "MarkdownV2",
+			want:      []string{`ab\.c`, "d", "[link](http://x.y/z)", "~str~", "~ike~"},
+		},
+		{
+			name:      "MarkdownV2 code block keeps its language",
+			text:      "```go\nfmt.Println(1)\nfmt.Println(2)\n```",
+			limit:     26,
+			parseMode: "MarkdownV2",
+			want:      []string{"```go\nfmt.Println(1)```", "```go\nfmt.Println(2)\n```"},
+		},
+		{
+			name:      "Markdown markers are closed and reopened",
+			text:      "*bold words that go on* `some code here`",
+			limit:     16,
+			parseMode: "Markdown",
+			want:      []string{"*bold words*", "*that go on*", "`some code here`"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitText(tt.text, tt.limit, tt.parseMode)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTextLimit(t *testing.T) {
+	text := strings.Repeat("<b>bold</b> <i>italic <u>underlined</u></i> &amp; <code>x := 1</code>\n", 500)
+
+	chunks := SplitText(text, MaxMessageLength, "HTML")
+	if len(chunks) < 2 {
+		t.Fatalf("SplitText() returned %d chunks", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if length := len([]rune(chunk)); length > MaxMessageLength {
+			t.Errorf("chunk %d has %d characters", i, length)
+		}
+		if opened, closed := strings.Count(chunk, "<b>"), strings.Count(chunk, "</b>"); opened != closed {
+			t.Errorf("chunk %d opens <b> %d times and closes it %d times", i, opened, closed)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"google.golang.org/grpc/stats"
+)
+
+// GRPCServerHandler starts a server span for every call, continuing the trace of the caller's
+// metadata. Health checks are polled by orchestrators and aren't traced.
+func GRPCServerHandler() stats.Handler {
+	return otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))
+}
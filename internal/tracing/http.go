@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Routes polled by orchestrators and scrapers, tracing them would bury the traces of real requests
+var untraced = map[string]bool{
+	"/metrics":          true,
+	"/api/health":       true,
+	"/api/health/live":  true,
+	"/api/health/ready": true,
+}
+
+// GinMiddleware starts a server span for every request, continuing the trace of the caller's
+// traceparent header. Spans are named after the route template, e.g. GET /api/v1/email/:id, and the
+// span context is set on the request so handlers and services pass it on.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if untraced[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		ctx, span := tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+			),
+		)
+		defer span.End()
+
+		if route != "" {
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("%d %s", status, http.StatusText(status)))
+		}
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MongoMonitor records a client span for every command run within a trace. Commands without a
+// parent span, e.g. the polling of background workers, aren't traced to keep traces meaningful.
+// Command documents aren't recorded since they hold recipient addresses and email bodies.
+func MongoMonitor() *event.CommandMonitor {
+	var spans sync.Map // Request ID to the span of the command in flight
+
+	end := func(requestID int64, err error) {
+		value, ok := spans.LoadAndDelete(requestID)
+		if !ok {
+			return
+		}
+
+		span := value.(trace.Span)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			if !trace.SpanContextFromContext(ctx).IsValid() {
+				return
+			}
+
+			name := e.CommandName
+			collection, _ := e.Command.Lookup(e.CommandName).StringValueOK()
+			if collection != "" {
+				name += " " + collection
+			}
+
+			_, span := tracer().Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemNameMongoDB,
+					semconv.DBNamespace(e.DatabaseName),
+					semconv.DBOperationName(e.CommandName),
+					semconv.DBCollectionName(collection),
+				),
+			)
+			spans.Store(e.RequestID, span)
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			end(e.RequestID, nil)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			end(e.RequestID, e.Failure)
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/aarondever/notiflow"
+
+// tracer resolves the global provider on every call, so spans started before Setup are no-ops
+// rather than bound to the default provider forever
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the global tracer provider and the W3C trace context propagator. Spans are exported
+// over OTLP/gRPC to cfg.Tracing.Endpoint, without an endpoint nothing is exported but trace context
+// is still propagated. The returned function flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Tracing.Endpoint == "" {
+		slog.Info("Tracing export disabled, no OTLP endpoint configured")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var options []otlptracegrpc.Option
+	if strings.Contains(cfg.Tracing.Endpoint, "://") {
+		options = append(options, otlptracegrpc.WithEndpointURL(cfg.Tracing.Endpoint))
+	} else {
+		options = append(options, otlptracegrpc.WithEndpoint(cfg.Tracing.Endpoint))
+	}
+	if cfg.Tracing.Insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		slog.Error("Failed to create OTLP trace exporter", "error", err, "endpoint", cfg.Tracing.Endpoint)
+		return nil, err
+	}
+
+	provider := NewTracerProvider(exporter, cfg.Tracing)
+	otel.SetTracerProvider(provider)
+	slog.Info("Tracing enabled", "endpoint", cfg.Tracing.Endpoint, "sample_ratio", cfg.Tracing.SampleRatio)
+
+	return provider.Shutdown, nil
+}
+
+// NewTracerProvider batches spans to exporter. Tests pass a tracetest.InMemoryExporter and install
+// the provider with otel.SetTracerProvider.
+func NewTracerProvider(exporter sdktrace.SpanExporter, cfg config.TracingConfig) *sdktrace.TracerProvider {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		slog.Warn("Failed to merge tracing resource, using defaults", "error", err)
+		res = resource.Default()
+	}
+
+	// Requests that arrive with a sampling decision keep it, new traces are sampled by ratio
+	sampler := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+}
+
+// Inject returns the trace context of ctx as a carrier that can be stored with a document, nil when
+// ctx isn't traced
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+
+	return carrier
+}
+
+// Extract returns ctx continuing the trace stored in carrier by Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// Start starts an internal span, e.g. for work done in the background
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// StartClient starts a span for a call to another system, e.g. an SMTP server
+func StartClient(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
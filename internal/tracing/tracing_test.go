@@ -0,0 +1,214 @@
+package tracing_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aarondever/notiflow/internal/config"
+	"github.com/aarondever/notiflow/internal/models"
+	"github.com/aarondever/notiflow/internal/tracing"
+	emailpb "github.com/aarondever/notiflow/proto/email"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupTracing installs a provider that records every span in memory
+func setupTracing(t *testing.T) (*tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	t.Helper()
+
+	if _, err := tracing.Setup(context.Background(), &config.Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewTracerProvider(exporter, config.TracingConfig{ServiceName: "notiflow-test", SampleRatio: 1})
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	return exporter, provider
+}
+
+// persist stores the request's trace context on an email and reads it back, as the database does
+// between the request and the background delivery
+func persist(t *testing.T, ctx context.Context) *models.Email {
+	t.Helper()
+
+	data, err := bson.Marshal(&models.Email{ID: bson.NewObjectID(), TraceContext: tracing.Inject(ctx)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var email models.Email
+	if err = bson.Unmarshal(data, &email); err != nil {
+		t.Fatal(err)
+	}
+	return &email
+}
+
+// deliver starts the delivery span the way the email service does, after the request has ended
+func deliver(email *models.Email) {
+	_, span := tracing.Start(tracing.Extract(context.Background(), email.TraceContext), "deliver email")
+	tracing.End(span, nil)
+}
+
+// spans flushes the provider and returns the recorded spans by name
+func spans(t *testing.T, exporter *tracetest.InMemoryExporter, provider *sdktrace.TracerProvider) map[string]tracetest.SpanStub {
+	t.Helper()
+
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		byName[span.Name] = span
+	}
+	return byName
+}
+
+func TestHTTPRequestTraceContinuesInDelivery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		traceparent string
+	}{
+		{"new trace", ""},
+		{"caller's trace", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, provider := setupTracing(t)
+
+			var email *models.Email
+			router := gin.New()
+			router.Use(tracing.GinMiddleware())
+			router.POST("/api/v1/email", func(c *gin.Context) {
+				email = persist(t, c.Request.Context())
+				c.Status(http.StatusAccepted)
+			})
+
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/email", nil)
+			if tt.traceparent != "" {
+				request.Header.Set("traceparent", tt.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), request)
+
+			if email == nil || email.TraceContext["traceparent"] == "" {
+				t.Fatalf("email trace context = %v, want a traceparent", email)
+			}
+			deliver(email)
+
+			recorded := spans(t, exporter, provider)
+			server, ok := recorded["POST /api/v1/email"]
+			if !ok {
+				t.Fatalf("no request span among %d spans", len(recorded))
+			}
+			delivery, ok := recorded["deliver email"]
+			if !ok {
+				t.Fatalf("no delivery span among %d spans", len(recorded))
+			}
+
+			if server.SpanKind != trace.SpanKindServer {
+				t.Errorf("request span kind = %s, want server", server.SpanKind)
+			}
+			if tt.traceparent != "" && server.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("request trace ID = %s, want the caller's", server.SpanContext.TraceID())
+			}
+			if delivery.SpanContext.TraceID() != server.SpanContext.TraceID() {
+				t.Errorf("delivery trace ID = %s, request trace ID = %s", delivery.SpanContext.TraceID(), server.SpanContext.TraceID())
+			}
+			if delivery.Parent.SpanID() != server.SpanContext.SpanID() {
+				t.Errorf("delivery parent = %s, want the request span %s", delivery.Parent.SpanID(), server.SpanContext.SpanID())
+			}
+		})
+	}
+}
+
+// emailServer persists the trace context of SendEmail calls like the gRPC email handler does
+type emailServer struct {
+	emailpb.UnimplementedEmailServiceServer
+
+	t     *testing.T
+	email *models.Email
+}
+
+func (s *emailServer) SendEmail(ctx context.Context, _ *emailpb.SendEmailRequest) (*emailpb.SendEmailResponse, error) {
+	s.email = persist(s.t, ctx)
+	return &emailpb.SendEmailResponse{}, nil
+}
+
+func TestGRPCCallTraceContinuesInDelivery(t *testing.T) {
+	exporter, provider := setupTracing(t)
+
+	listener := bufconn.Listen(1 << 20)
+	handler := &emailServer{t: t}
+	server := grpc.NewServer(grpc.StatsHandler(tracing.GRPCServerHandler()))
+	emailpb.RegisterEmailServiceServer(server, handler)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if _, err = emailpb.NewEmailServiceClient(conn).SendEmail(context.Background(), &emailpb.SendEmailRequest{}); err != nil {
+		t.Fatalf("SendEmail() error = %v", err)
+	}
+	if handler.email == nil || handler.email.TraceContext["traceparent"] == "" {
+		t.Fatalf("email trace context = %v, want a traceparent", handler.email)
+	}
+	deliver(handler.email)
+
+	// The client and server spans share the method's name
+	var client, call, delivery tracetest.SpanStub
+	if err = provider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	recorded := exporter.GetSpans()
+	for _, span := range recorded {
+		switch {
+		case span.Name == "deliver email":
+			delivery = span
+		case span.Name == "email.EmailService/SendEmail" && span.SpanKind == trace.SpanKindClient:
+			client = span
+		case span.Name == "email.EmailService/SendEmail" && span.SpanKind == trace.SpanKindServer:
+			call = span
+		}
+	}
+	if !client.SpanContext.IsValid() || !call.SpanContext.IsValid() || !delivery.SpanContext.IsValid() {
+		t.Fatalf("missing client, server or delivery span among %d spans", len(recorded))
+	}
+
+	if call.SpanContext.TraceID() != client.SpanContext.TraceID() {
+		t.Errorf("server trace ID = %s, client trace ID = %s", call.SpanContext.TraceID(), client.SpanContext.TraceID())
+	}
+	if delivery.SpanContext.TraceID() != call.SpanContext.TraceID() {
+		t.Errorf("delivery trace ID = %s, call trace ID = %s", delivery.SpanContext.TraceID(), call.SpanContext.TraceID())
+	}
+	if delivery.Parent.SpanID() != call.SpanContext.SpanID() {
+		t.Errorf("delivery parent = %s, want the call span %s", delivery.Parent.SpanID(), call.SpanContext.SpanID())
+	}
+}
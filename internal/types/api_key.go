@@ -0,0 +1,16 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type APIKeyService interface {
+	// CreateAPIKey stores a new key, the response is the only place the key itself appears
+	CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) (*models.APIKey, error)
+	// Authenticate returns the principal of a key, ErrUnauthenticated when it is unknown or revoked
+	Authenticate(ctx context.Context, key string) (*models.Principal, error)
+}
@@ -8,4 +8,16 @@ import (
 
 type EmailService interface {
 	SendEmail(ctx context.Context, email *models.Email) (*models.Email, error)
+	// SendEmailBatch queues the valid emails, setting their IDs, and returns the error of each email
+	// that wasn't queued by its index. An error fails the whole batch.
+	SendEmailBatch(ctx context.Context, emails []*models.Email) ([]error, error)
+	GetEmail(ctx context.Context, id string) (*models.Email, error)
+	ListEmailEvents(ctx context.Context, id string) ([]*models.EmailEvent, error)
+	// WatchEmail streams the email's current state and then every change until ctx is done
+	WatchEmail(ctx context.Context, id string) (<-chan *models.Email, error)
+	// WatchEmails streams the changes of every email matching filter until ctx is done
+	WatchEmails(ctx context.Context, filter *models.EmailWatchFilter) (<-chan *models.Email, error)
+	// ResendEmail delivers a copy of a stored email to the alternate addresses of a fallback step. It
+	// fails unless at least one of them got it.
+	ResendEmail(ctx context.Context, email *models.Email, to []string) error
 }
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+// Services wrap these so handlers can map failures to HTTP and gRPC status codes
+var (
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrNotFound          = errors.New("not found")
+	ErrAlreadyExists     = errors.New("already exists")
+	ErrUnauthenticated   = errors.New("unauthenticated")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrResourceExhausted = errors.New("resource exhausted")
+)
+
+// RateLimitError is an ErrResourceExhausted that tells the client when to try again
+type RateLimitError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return ErrResourceExhausted.Error() + ": " + e.Message
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrResourceExhausted
+}
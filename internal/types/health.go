@@ -0,0 +1,15 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type HealthService interface {
+	// Run probes the SMTP servers in the background so readiness checks stay fast, and marks the
+	// service not ready once ctx is cancelled
+	Worker
+	// Readiness checks MongoDB, the cached SMTP probes and the dispatcher backlog
+	Readiness(ctx context.Context) *models.HealthReport
+}
@@ -0,0 +1,13 @@
+package types
+
+import "context"
+
+type InboundService interface {
+	// Receive handles one inbound message: bounce reports, feedback loop reports or replies.
+	// from and to are the SMTP envelope, empty when the message was read from a mailbox.
+	// Errors are temporary, the message should be retried.
+	Receive(ctx context.Context, from string, to []string, raw []byte) error
+	// ReceiveReport handles a message read from the bounce mailbox, which only holds bounce and
+	// feedback loop reports. Other messages are dropped.
+	ReceiveReport(ctx context.Context, raw []byte) error
+}
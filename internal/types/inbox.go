@@ -0,0 +1,18 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type InboxService interface {
+	CreateItem(ctx context.Context, item *models.InboxItem) (*models.InboxItem, error)
+	ListItems(ctx context.Context, filter models.InboxFilter) ([]*models.InboxItem, error)
+	MarkRead(ctx context.Context, userID, id string, read bool) (*models.InboxItem, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	Archive(ctx context.Context, userID, id string) (*models.InboxItem, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// Subscribe streams new items for a user until ctx is done
+	Subscribe(ctx context.Context, userID string) <-chan *models.InboxItem
+}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type PreferenceService interface {
+	GetPreference(ctx context.Context, address string) (*models.Preference, error)
+	UpdatePreference(ctx context.Context, address string, rules []models.PreferenceRule) (*models.Preference, error)
+	DeletePreference(ctx context.Context, address string) error
+}
@@ -0,0 +1,15 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type RecipientService interface {
+	CreateRecipient(ctx context.Context, recipient *models.Recipient) (*models.Recipient, error)
+	GetRecipient(ctx context.Context, userID string) (*models.Recipient, error)
+	ListRecipients(ctx context.Context, after string, limit int64) ([]*models.Recipient, error)
+	UpdateRecipient(ctx context.Context, recipient *models.Recipient) (*models.Recipient, error)
+	DeleteRecipient(ctx context.Context, userID string) error
+}
@@ -0,0 +1,15 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type StatsService interface {
+	// EmailStats returns delivery and engagement counts and rates of the emails created in the
+	// requested period, read from the hourly rollups
+	EmailStats(ctx context.Context, request *models.StatsRequest) (*models.StatsResponse, error)
+	// CountEmailsByStatus returns the number of stored emails per status, pending is the send queue
+	CountEmailsByStatus(ctx context.Context) (map[string]int64, error)
+}
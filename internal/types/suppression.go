@@ -0,0 +1,17 @@
+package types
+
+import (
+	"context"
+	"io"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type SuppressionService interface {
+	ListSuppressions(ctx context.Context, reason models.SuppressionReason, after string, limit int64) ([]*models.Suppression, error)
+	GetSuppression(ctx context.Context, address string) (*models.Suppression, error)
+	AddSuppression(ctx context.Context, suppression *models.Suppression) (*models.Suppression, error)
+	RemoveSuppression(ctx context.Context, address string) error
+	// ImportCSV reads address[,reason[,expires_at]] rows, an optional header row is skipped
+	ImportCSV(ctx context.Context, reader io.Reader) (*models.SuppressionImportResult, error)
+}
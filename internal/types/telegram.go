@@ -0,0 +1,13 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type TelegramService interface {
+	SendMessage(ctx context.Context, message *models.TelegramMessage) (*models.TelegramMessage, error)
+	GetMessage(ctx context.Context, id string) (*models.TelegramMessage, error)
+	RetryMessage(ctx context.Context, id string) (*models.TelegramMessage, error)
+}
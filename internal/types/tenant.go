@@ -0,0 +1,16 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type TenantService interface {
+	CreateTenant(ctx context.Context, request *models.CreateTenantRequest) (*models.Tenant, error)
+	ListTenants(ctx context.Context) ([]*models.Tenant, error)
+	GetTenant(ctx context.Context, id string) (*models.Tenant, error)
+	UpdateTenant(ctx context.Context, id string, request *models.UpdateTenantRequest) (*models.Tenant, error)
+	// CheckTenant returns ErrPermissionDenied unless the tenant exists and is enabled
+	CheckTenant(ctx context.Context, id string) error
+}
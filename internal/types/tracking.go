@@ -0,0 +1,17 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type TrackingService interface {
+	// RecordOpen records a tracking pixel fetch against the email and recipient of the token
+	RecordOpen(ctx context.Context, token, userAgent, ip string) error
+	// RecordClick records a tracked link click and returns the link target, which is returned
+	// whenever the token is valid, even if recording the click failed
+	RecordClick(ctx context.Context, token, userAgent, ip string) (string, error)
+	EmailClicks(ctx context.Context, id string) (*models.EmailClickStats, error)
+	CategoryClicks(ctx context.Context, request *models.CategoryClicksRequest) (*models.CategoryClicksResponse, error)
+}
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type UnsubscribeService interface {
+	// Verify decodes a signed unsubscribe token without acting on it
+	Verify(token string) (*models.UnsubscribeToken, error)
+	// Unsubscribe opts the token's address out of its category on the email channel
+	Unsubscribe(ctx context.Context, token string) (*models.UnsubscribeToken, error)
+}
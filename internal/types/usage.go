@@ -0,0 +1,12 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type UsageService interface {
+	// GetUsage returns a tenant's emails and recipients of a month against its monthly quota
+	GetUsage(ctx context.Context, request *models.UsageRequest) (*models.UsageResponse, error)
+}
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"context"
+
+	"github.com/aarondever/notiflow/internal/models"
+)
+
+type WebhookService interface {
+	// CreateEndpoint registers a subscriber, the returned endpoint is the only one carrying its secret
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context) ([]*models.WebhookEndpoint, error)
+	GetEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, id string, endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, endpointID string, status models.WebhookDeliveryStatus, before string, limit int64) ([]*models.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, endpointID, id string) (*models.WebhookDelivery, error)
+	// Redeliver queues a copy of a delivery's event with a fresh attempt count
+	Redeliver(ctx context.Context, endpointID, id string) (*models.WebhookDelivery, error)
+}
@@ -0,0 +1,8 @@
+package types
+
+import "context"
+
+// Worker is a background process started alongside the HTTP and gRPC servers
+type Worker interface {
+	Run(ctx context.Context)
+}
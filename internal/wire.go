@@ -4,40 +4,123 @@
 package internal
 
 import (
+	"crypto/tls"
+
+	"github.com/aarondever/notiflow/internal/certs"
 	"github.com/aarondever/notiflow/internal/config"
 	"github.com/aarondever/notiflow/internal/database"
 	"github.com/aarondever/notiflow/internal/handlers"
+	"github.com/aarondever/notiflow/internal/metrics"
 	"github.com/aarondever/notiflow/internal/services"
+	"github.com/aarondever/notiflow/internal/tracing"
+	"github.com/aarondever/notiflow/internal/types"
 	"github.com/aarondever/notiflow/proto/email"
+	"github.com/aarondever/notiflow/proto/inbox"
+	"github.com/aarondever/notiflow/proto/stats"
+	"github.com/aarondever/notiflow/proto/telegram"
+	"github.com/emersion/go-smtp"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type App struct {
 	DB         *database.Database
 	Router     *gin.Engine
 	GRPCServer *grpc.Server
+	SMTPServer *smtp.Server // Inbound listener, nil when disabled
+	TLSConfig  *tls.Config  // TLS of the HTTP server, nil when it serves plaintext
+	Workers    []types.Worker
 }
 
 func NewApp(
+	cfg *config.Config,
 	db *database.Database,
 	emailHandler *handlers.EmailHandler,
 	emailGRPCHandler *handlers.EmailGRPCHandler,
+	telegramHandler *handlers.TelegramHandler,
+	telegramGRPCHandler *handlers.TelegramGRPCHandler,
+	inboxHandler *handlers.InboxHandler,
+	inboxGRPCHandler *handlers.InboxGRPCHandler,
+	recipientHandler *handlers.RecipientHandler,
+	preferenceHandler *handlers.PreferenceHandler,
+	suppressionHandler *handlers.SuppressionHandler,
+	unsubscribeHandler *handlers.UnsubscribeHandler,
+	inboundSMTPHandler *handlers.InboundSMTPHandler,
+	webhookHandler *handlers.WebhookHandler,
+	trackingHandler *handlers.TrackingHandler,
+	statsHandler *handlers.StatsHandler,
+	statsGRPCHandler *handlers.StatsGRPCHandler,
+	metricsHandler *handlers.MetricsHandler,
+	healthHandler *handlers.HealthHandler,
+	healthGRPCHandler *handlers.HealthGRPCHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	tenantHandler *handlers.TenantHandler,
+	usageHandler *handlers.UsageHandler,
+	authenticator *handlers.Authenticator,
+	certificates *certs.Reloader,
+	escalationDispatcher *services.EscalationDispatcher,
+	bounceProcessor *services.BounceProcessor,
+	webhookDispatcher *services.WebhookDispatcher,
+	statsRollup *services.StatsRollup,
+	healthService types.HealthService,
 	// Add all handlers as parameters
 ) *App {
 	// Setup HTTP router
 	router := gin.Default()
+	router.Use(metrics.GinMiddleware(), tracing.GinMiddleware(), authenticator.GinMiddleware())
 	emailHandler.RegisterRouter(router)
+	telegramHandler.RegisterRouter(router)
+	inboxHandler.RegisterRouter(router)
+	recipientHandler.RegisterRouter(router)
+	preferenceHandler.RegisterRouter(router)
+	suppressionHandler.RegisterRouter(router)
+	unsubscribeHandler.RegisterRouter(router)
+	webhookHandler.RegisterRouter(router)
+	trackingHandler.RegisterRouter(router)
+	statsHandler.RegisterRouter(router)
+	metricsHandler.RegisterRouter(router)
+	healthHandler.RegisterRouter(router)
+	apiKeyHandler.RegisterRouter(router)
+	tenantHandler.RegisterRouter(router)
+	usageHandler.RegisterRouter(router)
 
 	// Setup gRPC server
-	grpcSrv := grpc.NewServer()
+	grpcOptions := []grpc.ServerOption{
+		grpc.StatsHandler(tracing.GRPCServerHandler()),
+		grpc.MaxRecvMsgSize(cfg.Server.GRPCMaxMessageSize),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor, authenticator.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor, authenticator.StreamServerInterceptor),
+	}
+	var tlsConfig *tls.Config
+	if certificates.Enabled() {
+		tlsConfig = certificates.ServerConfig()
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(certificates.ServerConfig())))
+	}
+	grpcSrv := grpc.NewServer(grpcOptions...)
 	email.RegisterEmailServiceServer(grpcSrv, emailGRPCHandler)
+	telegram.RegisterTelegramServiceServer(grpcSrv, telegramGRPCHandler)
+	inbox.RegisterInboxServiceServer(grpcSrv, inboxGRPCHandler)
+	stats.RegisterStatsServiceServer(grpcSrv, statsGRPCHandler)
+	healthpb.RegisterHealthServer(grpcSrv, healthGRPCHandler)
 
 	return &App{
 		DB:         db,
 		Router:     router,
 		GRPCServer: grpcSrv,
+		SMTPServer: inboundSMTPHandler.NewServer(),
+		TLSConfig:  tlsConfig,
+		Workers: []types.Worker{
+			escalationDispatcher,
+			bounceProcessor,
+			webhookDispatcher,
+			statsRollup,
+			healthService,
+			healthGRPCHandler,
+			certificates,
+		},
 	}
 }
 
@@ -45,6 +128,7 @@ func NewApp(
 func InitializeApp(cfg *config.Config) (*App, error) {
 	wire.Build(
 		database.NewDatabase,
+		certs.NewReloader,
 		services.ProviderSet,
 		handlers.ProviderSet,
 		NewApp,
@@ -7,13 +7,25 @@
 package internal
 
 import (
+	"crypto/tls"
+
+	"github.com/aarondever/notiflow/internal/certs"
 	"github.com/aarondever/notiflow/internal/config"
 	"github.com/aarondever/notiflow/internal/database"
 	"github.com/aarondever/notiflow/internal/handlers"
+	"github.com/aarondever/notiflow/internal/metrics"
 	"github.com/aarondever/notiflow/internal/services"
+	"github.com/aarondever/notiflow/internal/tracing"
+	"github.com/aarondever/notiflow/internal/types"
 	"github.com/aarondever/notiflow/proto/email"
+	"github.com/aarondever/notiflow/proto/inbox"
+	"github.com/aarondever/notiflow/proto/stats"
+	"github.com/aarondever/notiflow/proto/telegram"
+	"github.com/emersion/go-smtp"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // Injectors from wire.go:
@@ -24,10 +36,61 @@ func InitializeApp(cfg *config.Config) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	emailService := services.NewEmailService(databaseDatabase, cfg)
+	tenantDirectory := services.NewTenantDirectory(databaseDatabase, cfg)
+	smtpSender := services.NewSMTPSender(cfg, tenantDirectory)
+	sendLimiter := services.NewSendLimiter(databaseDatabase, cfg)
+	emailService := services.NewEmailService(databaseDatabase, cfg, smtpSender, tenantDirectory, sendLimiter)
 	emailHandler := handlers.NewEmailHandler(emailService)
 	emailGRPCHandler := handlers.NewEmailGRPCHandler(emailService)
-	app := NewApp(databaseDatabase, emailHandler, emailGRPCHandler)
+	telegramService := services.NewTelegramService(databaseDatabase, cfg)
+	telegramHandler := handlers.NewTelegramHandler(telegramService)
+	telegramGRPCHandler := handlers.NewTelegramGRPCHandler(telegramService)
+	inboxService := services.NewInboxService(databaseDatabase)
+	inboxHandler := handlers.NewInboxHandler(inboxService)
+	inboxGRPCHandler := handlers.NewInboxGRPCHandler(inboxService)
+	recipientService := services.NewRecipientService(databaseDatabase)
+	recipientHandler := handlers.NewRecipientHandler(recipientService)
+	preferenceService := services.NewPreferenceService(databaseDatabase, cfg)
+	preferenceHandler := handlers.NewPreferenceHandler(preferenceService)
+	suppressionService := services.NewSuppressionService(databaseDatabase)
+	suppressionHandler := handlers.NewSuppressionHandler(suppressionService)
+	unsubscribeService := services.NewUnsubscribeService(databaseDatabase, cfg)
+	unsubscribeHandler := handlers.NewUnsubscribeHandler(unsubscribeService)
+	escalationDispatcher := services.NewEscalationDispatcher(databaseDatabase, cfg, emailService)
+	inboundService := services.NewInboundService(databaseDatabase, cfg)
+	inboundSMTPHandler := handlers.NewInboundSMTPHandler(inboundService, cfg)
+	bounceProcessor, err := services.NewBounceProcessor(cfg, inboundService)
+	if err != nil {
+		return nil, err
+	}
+	webhookService := services.NewWebhookService(databaseDatabase)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	trackingService := services.NewTrackingService(databaseDatabase, cfg)
+	trackingHandler := handlers.NewTrackingHandler(trackingService)
+	statsService := services.NewStatsService(databaseDatabase, cfg)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	statsGRPCHandler := handlers.NewStatsGRPCHandler(statsService)
+	metricsHandler := handlers.NewMetricsHandler(statsService)
+	healthService := services.NewHealthService(databaseDatabase, cfg, smtpSender)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	healthGRPCHandler := handlers.NewHealthGRPCHandler(healthService, cfg)
+	apiKeyService := services.NewAPIKeyService(databaseDatabase, cfg, tenantDirectory)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	tenantService := services.NewTenantService(databaseDatabase, tenantDirectory)
+	tenantHandler := handlers.NewTenantHandler(tenantService)
+	usageService := services.NewUsageService(databaseDatabase, tenantDirectory, sendLimiter)
+	usageHandler := handlers.NewUsageHandler(usageService)
+	authenticator, err := handlers.NewAuthenticator(apiKeyService, tenantService, cfg)
+	if err != nil {
+		return nil, err
+	}
+	webhookDispatcher := services.NewWebhookDispatcher(databaseDatabase, cfg)
+	statsRollup := services.NewStatsRollup(databaseDatabase, cfg)
+	reloader, err := certs.NewReloader(cfg)
+	if err != nil {
+		return nil, err
+	}
+	app := NewApp(cfg, databaseDatabase, emailHandler, emailGRPCHandler, telegramHandler, telegramGRPCHandler, inboxHandler, inboxGRPCHandler, recipientHandler, preferenceHandler, suppressionHandler, unsubscribeHandler, inboundSMTPHandler, webhookHandler, trackingHandler, statsHandler, statsGRPCHandler, metricsHandler, healthHandler, healthGRPCHandler, apiKeyHandler, tenantHandler, usageHandler, authenticator, reloader, escalationDispatcher, bounceProcessor, webhookDispatcher, statsRollup, healthService)
 	return app, nil
 }
 
@@ -37,24 +100,95 @@ type App struct {
 	DB         *database.Database
 	Router     *gin.Engine
 	GRPCServer *grpc.Server
+	SMTPServer *smtp.Server // Inbound listener, nil when disabled
+	TLSConfig  *tls.Config  // TLS of the HTTP server, nil when it serves plaintext
+	Workers    []types.Worker
 }
 
 func NewApp(
+	cfg *config.Config,
 	db *database.Database,
 	emailHandler *handlers.EmailHandler,
 	emailGRPCHandler *handlers.EmailGRPCHandler,
+	telegramHandler *handlers.TelegramHandler,
+	telegramGRPCHandler *handlers.TelegramGRPCHandler,
+	inboxHandler *handlers.InboxHandler,
+	inboxGRPCHandler *handlers.InboxGRPCHandler,
+	recipientHandler *handlers.RecipientHandler,
+	preferenceHandler *handlers.PreferenceHandler,
+	suppressionHandler *handlers.SuppressionHandler,
+	unsubscribeHandler *handlers.UnsubscribeHandler,
+	inboundSMTPHandler *handlers.InboundSMTPHandler,
+	webhookHandler *handlers.WebhookHandler,
+	trackingHandler *handlers.TrackingHandler,
+	statsHandler *handlers.StatsHandler,
+	statsGRPCHandler *handlers.StatsGRPCHandler,
+	metricsHandler *handlers.MetricsHandler,
+	healthHandler *handlers.HealthHandler,
+	healthGRPCHandler *handlers.HealthGRPCHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	tenantHandler *handlers.TenantHandler,
+	usageHandler *handlers.UsageHandler,
+	authenticator *handlers.Authenticator,
+	certificates *certs.Reloader,
+	escalationDispatcher *services.EscalationDispatcher,
+	bounceProcessor *services.BounceProcessor,
+	webhookDispatcher *services.WebhookDispatcher,
+	statsRollup *services.StatsRollup,
+	healthService types.HealthService,
 
 ) *App {
 
 	router := gin.Default()
+	router.Use(metrics.GinMiddleware(), tracing.GinMiddleware(), authenticator.GinMiddleware())
 	emailHandler.RegisterRouter(router)
+	telegramHandler.RegisterRouter(router)
+	inboxHandler.RegisterRouter(router)
+	recipientHandler.RegisterRouter(router)
+	preferenceHandler.RegisterRouter(router)
+	suppressionHandler.RegisterRouter(router)
+	unsubscribeHandler.RegisterRouter(router)
+	webhookHandler.RegisterRouter(router)
+	trackingHandler.RegisterRouter(router)
+	statsHandler.RegisterRouter(router)
+	metricsHandler.RegisterRouter(router)
+	healthHandler.RegisterRouter(router)
+	apiKeyHandler.RegisterRouter(router)
+	tenantHandler.RegisterRouter(router)
+	usageHandler.RegisterRouter(router)
 
-	grpcSrv := grpc.NewServer()
+	grpcOptions := []grpc.ServerOption{
+		grpc.StatsHandler(tracing.GRPCServerHandler()),
+		grpc.MaxRecvMsgSize(cfg.Server.GRPCMaxMessageSize),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor, authenticator.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor, authenticator.StreamServerInterceptor),
+	}
+	var tlsConfig *tls.Config
+	if certificates.Enabled() {
+		tlsConfig = certificates.ServerConfig()
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(certificates.ServerConfig())))
+	}
+	grpcSrv := grpc.NewServer(grpcOptions...)
 	email.RegisterEmailServiceServer(grpcSrv, emailGRPCHandler)
+	telegram.RegisterTelegramServiceServer(grpcSrv, telegramGRPCHandler)
+	inbox.RegisterInboxServiceServer(grpcSrv, inboxGRPCHandler)
+	stats.RegisterStatsServiceServer(grpcSrv, statsGRPCHandler)
+	healthpb.RegisterHealthServer(grpcSrv, healthGRPCHandler)
 
 	return &App{
 		DB:         db,
 		Router:     router,
 		GRPCServer: grpcSrv,
+		SMTPServer: inboundSMTPHandler.NewServer(),
+		TLSConfig:  tlsConfig,
+		Workers: []types.Worker{
+			escalationDispatcher,
+			bounceProcessor,
+			webhookDispatcher,
+			statsRollup,
+			healthService,
+			healthGRPCHandler,
+			certificates,
+		},
 	}
 }
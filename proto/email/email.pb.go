@@ -31,6 +31,12 @@ type SendEmailRequest struct {
 	Body          string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
 	IsHtml        bool                   `protobuf:"varint,6,opt,name=is_html,json=isHtml,proto3" json:"is_html,omitempty"`
 	Attachments   []*Attachment          `protobuf:"bytes,7,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	Fallback      *FallbackPlan          `protobuf:"bytes,8,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	UserId        string                 `protobuf:"bytes,9,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Category      string                 `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
+	TrackOpens    bool                   `protobuf:"varint,11,opt,name=track_opens,json=trackOpens,proto3" json:"track_opens,omitempty"`
+	TrackClicks   bool                   `protobuf:"varint,12,opt,name=track_clicks,json=trackClicks,proto3" json:"track_clicks,omitempty"`
+	From          string                 `protobuf:"bytes,13,opt,name=from,proto3" json:"from,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -114,6 +120,48 @@ func (x *SendEmailRequest) GetAttachments() []*Attachment {
 	return nil
 }
 
+func (x *SendEmailRequest) GetFallback() *FallbackPlan {
+	if x != nil {
+		return x.Fallback
+	}
+	return nil
+}
+
+func (x *SendEmailRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SendEmailRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *SendEmailRequest) GetTrackOpens() bool {
+	if x != nil {
+		return x.TrackOpens
+	}
+	return false
+}
+
+func (x *SendEmailRequest) GetTrackClicks() bool {
+	if x != nil {
+		return x.TrackClicks
+	}
+	return false
+}
+
+func (x *SendEmailRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
 type Attachment struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
@@ -242,32 +290,1581 @@ func (x *SendEmailResponse) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-var File_proto_email_email_proto protoreflect.FileDescriptor
+type SendEmailBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emails        []*SendEmailRequest    `protobuf:"bytes,1,rep,name=emails,proto3" json:"emails,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_email_email_proto_rawDesc = "" +
-	"\n" +
-	"\x17proto/email/email.proto\x12\x05email\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc0\x01\n" +
-	"\x10SendEmailRequest\x12\x0e\n" +
-	"\x02to\x18\x01 \x03(\tR\x02to\x12\x0e\n" +
-	"\x02cc\x18\x02 \x03(\tR\x02cc\x12\x10\n" +
-	"\x03bcc\x18\x03 \x03(\tR\x03bcc\x12\x18\n" +
-	"\asubject\x18\x04 \x01(\tR\asubject\x12\x12\n" +
-	"\x04body\x18\x05 \x01(\tR\x04body\x12\x17\n" +
-	"\ais_html\x18\x06 \x01(\bR\x06isHtml\x123\n" +
-	"\vattachments\x18\a \x03(\v2\x11.email.AttachmentR\vattachments\"e\n" +
-	"\n" +
-	"Attachment\x12\x1a\n" +
-	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\fR\acontent\x12!\n" +
-	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"\x90\x01\n" +
-	"\x11SendEmailResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\x129\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2N\n" +
-	"\fEmailService\x12>\n" +
-	"\tSendEmail\x12\x17.email.SendEmailRequest\x1a\x18.email.SendEmailResponseB,Z*github.com/aarondever/notiflow/proto/emailb\x06proto3"
+func (x *SendEmailBatchRequest) Reset() {
+	*x = SendEmailBatchRequest{}
+	mi := &file_proto_email_email_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendEmailBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendEmailBatchRequest) ProtoMessage() {}
+
+func (x *SendEmailBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendEmailBatchRequest.ProtoReflect.Descriptor instead.
+func (*SendEmailBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SendEmailBatchRequest) GetEmails() []*SendEmailRequest {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+type SendEmailBatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendEmailBatchResult) Reset() {
+	*x = SendEmailBatchResult{}
+	mi := &file_proto_email_email_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendEmailBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendEmailBatchResult) ProtoMessage() {}
+
+func (x *SendEmailBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendEmailBatchResult.ProtoReflect.Descriptor instead.
+func (*SendEmailBatchResult) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SendEmailBatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SendEmailBatchResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SendEmailBatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SendEmailBatchResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Queued        int32                   `protobuf:"varint,1,opt,name=queued,proto3" json:"queued,omitempty"`
+	Failed        int32                   `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+	Results       []*SendEmailBatchResult `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	CreatedAt     *timestamppb.Timestamp  `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendEmailBatchResponse) Reset() {
+	*x = SendEmailBatchResponse{}
+	mi := &file_proto_email_email_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendEmailBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendEmailBatchResponse) ProtoMessage() {}
+
+func (x *SendEmailBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendEmailBatchResponse.ProtoReflect.Descriptor instead.
+func (*SendEmailBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendEmailBatchResponse) GetQueued() int32 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *SendEmailBatchResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *SendEmailBatchResponse) GetResults() []*SendEmailBatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SendEmailBatchResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEmailRequest) Reset() {
+	*x = GetEmailRequest{}
+	mi := &file_proto_email_email_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEmailRequest) ProtoMessage() {}
+
+func (x *GetEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEmailRequest.ProtoReflect.Descriptor instead.
+func (*GetEmailRequest) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetEmailRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Email struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	To            []string               `protobuf:"bytes,2,rep,name=to,proto3" json:"to,omitempty"`
+	Cc            []string               `protobuf:"bytes,3,rep,name=cc,proto3" json:"cc,omitempty"`
+	Bcc           []string               `protobuf:"bytes,4,rep,name=bcc,proto3" json:"bcc,omitempty"`
+	Subject       string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	IsHtml        bool                   `protobuf:"varint,6,opt,name=is_html,json=isHtml,proto3" json:"is_html,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	Fallback      *FallbackPlan          `protobuf:"bytes,11,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	UserId        string                 `protobuf:"bytes,12,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Category      string                 `protobuf:"bytes,13,opt,name=category,proto3" json:"category,omitempty"`
+	Suppressed    []*SuppressedRecipient `protobuf:"bytes,14,rep,name=suppressed,proto3" json:"suppressed,omitempty"`
+	Bounces       []*Bounce              `protobuf:"bytes,15,rep,name=bounces,proto3" json:"bounces,omitempty"`
+	Complaints    []*Complaint           `protobuf:"bytes,16,rep,name=complaints,proto3" json:"complaints,omitempty"`
+	Recipients    []*EmailRecipient      `protobuf:"bytes,17,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	TrackOpens    bool                   `protobuf:"varint,19,opt,name=track_opens,json=trackOpens,proto3" json:"track_opens,omitempty"`
+	TrackClicks   bool                   `protobuf:"varint,20,opt,name=track_clicks,json=trackClicks,proto3" json:"track_clicks,omitempty"`
+	CreatedBy     *Principal             `protobuf:"bytes,21,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Tenant        string                 `protobuf:"bytes,22,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	From          string                 `protobuf:"bytes,23,opt,name=from,proto3" json:"from,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Email) Reset() {
+	*x = Email{}
+	mi := &file_proto_email_email_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Email) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Email) ProtoMessage() {}
+
+func (x *Email) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Email.ProtoReflect.Descriptor instead.
+func (*Email) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Email) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Email) GetTo() []string {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Email) GetCc() []string {
+	if x != nil {
+		return x.Cc
+	}
+	return nil
+}
+
+func (x *Email) GetBcc() []string {
+	if x != nil {
+		return x.Bcc
+	}
+	return nil
+}
+
+func (x *Email) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Email) GetIsHtml() bool {
+	if x != nil {
+		return x.IsHtml
+	}
+	return false
+}
+
+func (x *Email) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Email) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *Email) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Email) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *Email) GetFallback() *FallbackPlan {
+	if x != nil {
+		return x.Fallback
+	}
+	return nil
+}
+
+func (x *Email) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Email) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Email) GetSuppressed() []*SuppressedRecipient {
+	if x != nil {
+		return x.Suppressed
+	}
+	return nil
+}
+
+func (x *Email) GetBounces() []*Bounce {
+	if x != nil {
+		return x.Bounces
+	}
+	return nil
+}
+
+func (x *Email) GetComplaints() []*Complaint {
+	if x != nil {
+		return x.Complaints
+	}
+	return nil
+}
+
+func (x *Email) GetRecipients() []*EmailRecipient {
+	if x != nil {
+		return x.Recipients
+	}
+	return nil
+}
+
+func (x *Email) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Email) GetTrackOpens() bool {
+	if x != nil {
+		return x.TrackOpens
+	}
+	return false
+}
+
+func (x *Email) GetTrackClicks() bool {
+	if x != nil {
+		return x.TrackClicks
+	}
+	return false
+}
+
+func (x *Email) GetCreatedBy() *Principal {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return nil
+}
+
+func (x *Email) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Email) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+type Principal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Tenant        string                 `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Principal) Reset() {
+	*x = Principal{}
+	mi := &file_proto_email_email_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Principal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Principal) ProtoMessage() {}
+
+func (x *Principal) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Principal.ProtoReflect.Descriptor instead.
+func (*Principal) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Principal) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Principal) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Principal) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Principal) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type EmailRecipient struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Address        string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Kind           string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	SmtpCode       int32                  `protobuf:"varint,4,opt,name=smtp_code,json=smtpCode,proto3" json:"smtp_code,omitempty"`
+	SmtpResponse   string                 `protobuf:"bytes,5,opt,name=smtp_response,json=smtpResponse,proto3" json:"smtp_response,omitempty"`
+	Reason         string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	SentAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Bounce         *Bounce                `protobuf:"bytes,9,opt,name=bounce,proto3" json:"bounce,omitempty"`
+	OpenCount      int32                  `protobuf:"varint,10,opt,name=open_count,json=openCount,proto3" json:"open_count,omitempty"`
+	FirstOpenedAt  *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=first_opened_at,json=firstOpenedAt,proto3" json:"first_opened_at,omitempty"`
+	LastOpenedAt   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_opened_at,json=lastOpenedAt,proto3" json:"last_opened_at,omitempty"`
+	ClickCount     int32                  `protobuf:"varint,13,opt,name=click_count,json=clickCount,proto3" json:"click_count,omitempty"`
+	FirstClickedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=first_clicked_at,json=firstClickedAt,proto3" json:"first_clicked_at,omitempty"`
+	LastClickedAt  *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=last_clicked_at,json=lastClickedAt,proto3" json:"last_clicked_at,omitempty"`
+	Server         string                 `protobuf:"bytes,16,opt,name=server,proto3" json:"server,omitempty"`
+	Sender         string                 `protobuf:"bytes,17,opt,name=sender,proto3" json:"sender,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EmailRecipient) Reset() {
+	*x = EmailRecipient{}
+	mi := &file_proto_email_email_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailRecipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailRecipient) ProtoMessage() {}
+
+func (x *EmailRecipient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailRecipient.ProtoReflect.Descriptor instead.
+func (*EmailRecipient) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EmailRecipient) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *EmailRecipient) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *EmailRecipient) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *EmailRecipient) GetSmtpCode() int32 {
+	if x != nil {
+		return x.SmtpCode
+	}
+	return 0
+}
+
+func (x *EmailRecipient) GetSmtpResponse() string {
+	if x != nil {
+		return x.SmtpResponse
+	}
+	return ""
+}
+
+func (x *EmailRecipient) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *EmailRecipient) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *EmailRecipient) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *EmailRecipient) GetBounce() *Bounce {
+	if x != nil {
+		return x.Bounce
+	}
+	return nil
+}
+
+func (x *EmailRecipient) GetOpenCount() int32 {
+	if x != nil {
+		return x.OpenCount
+	}
+	return 0
+}
+
+func (x *EmailRecipient) GetFirstOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstOpenedAt
+	}
+	return nil
+}
+
+func (x *EmailRecipient) GetLastOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastOpenedAt
+	}
+	return nil
+}
+
+func (x *EmailRecipient) GetClickCount() int32 {
+	if x != nil {
+		return x.ClickCount
+	}
+	return 0
+}
+
+func (x *EmailRecipient) GetFirstClickedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstClickedAt
+	}
+	return nil
+}
+
+func (x *EmailRecipient) GetLastClickedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastClickedAt
+	}
+	return nil
+}
+
+func (x *EmailRecipient) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *EmailRecipient) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+type SuppressedRecipient struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuppressedRecipient) Reset() {
+	*x = SuppressedRecipient{}
+	mi := &file_proto_email_email_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuppressedRecipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuppressedRecipient) ProtoMessage() {}
+
+func (x *SuppressedRecipient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuppressedRecipient.ProtoReflect.Descriptor instead.
+func (*SuppressedRecipient) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SuppressedRecipient) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SuppressedRecipient) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Bounce struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Address        string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Action         string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	DiagnosticCode string                 `protobuf:"bytes,4,opt,name=diagnostic_code,json=diagnosticCode,proto3" json:"diagnostic_code,omitempty"`
+	Permanent      bool                   `protobuf:"varint,5,opt,name=permanent,proto3" json:"permanent,omitempty"`
+	ReportedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=reported_at,json=reportedAt,proto3" json:"reported_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Bounce) Reset() {
+	*x = Bounce{}
+	mi := &file_proto_email_email_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bounce) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bounce) ProtoMessage() {}
+
+func (x *Bounce) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bounce.ProtoReflect.Descriptor instead.
+func (*Bounce) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Bounce) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Bounce) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Bounce) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Bounce) GetDiagnosticCode() string {
+	if x != nil {
+		return x.DiagnosticCode
+	}
+	return ""
+}
+
+func (x *Bounce) GetPermanent() bool {
+	if x != nil {
+		return x.Permanent
+	}
+	return false
+}
+
+func (x *Bounce) GetReportedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReportedAt
+	}
+	return nil
+}
+
+type Complaint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	FeedbackType  string                 `protobuf:"bytes,2,opt,name=feedback_type,json=feedbackType,proto3" json:"feedback_type,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,3,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	ReportedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=reported_at,json=reportedAt,proto3" json:"reported_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Complaint) Reset() {
+	*x = Complaint{}
+	mi := &file_proto_email_email_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Complaint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Complaint) ProtoMessage() {}
+
+func (x *Complaint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Complaint.ProtoReflect.Descriptor instead.
+func (*Complaint) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Complaint) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Complaint) GetFeedbackType() string {
+	if x != nil {
+		return x.FeedbackType
+	}
+	return ""
+}
+
+func (x *Complaint) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Complaint) GetReportedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReportedAt
+	}
+	return nil
+}
+
+type WatchEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEmailRequest) Reset() {
+	*x = WatchEmailRequest{}
+	mi := &file_proto_email_email_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEmailRequest) ProtoMessage() {}
+
+func (x *WatchEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEmailRequest.ProtoReflect.Descriptor instead.
+func (*WatchEmailRequest) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchEmailRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchEmailsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEmailsRequest) Reset() {
+	*x = WatchEmailsRequest{}
+	mi := &file_proto_email_email_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEmailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEmailsRequest) ProtoMessage() {}
+
+func (x *WatchEmailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEmailsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEmailsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchEmailsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *WatchEmailsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *WatchEmailsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListEmailEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmailEventsRequest) Reset() {
+	*x = ListEmailEventsRequest{}
+	mi := &file_proto_email_email_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmailEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmailEventsRequest) ProtoMessage() {}
+
+func (x *ListEmailEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmailEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEmailEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListEmailEventsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListEmailEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*EmailEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmailEventsResponse) Reset() {
+	*x = ListEmailEventsResponse{}
+	mi := &file_proto_email_email_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmailEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmailEventsResponse) ProtoMessage() {}
+
+func (x *ListEmailEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmailEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListEmailEventsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListEmailEventsResponse) GetEvents() []*EmailEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type EmailEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EmailId       string                 `protobuf:"bytes,2,opt,name=email_id,json=emailId,proto3" json:"email_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Recipient     string                 `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Server        string                 `protobuf:"bytes,5,opt,name=server,proto3" json:"server,omitempty"`
+	SmtpCode      int32                  `protobuf:"varint,6,opt,name=smtp_code,json=smtpCode,proto3" json:"smtp_code,omitempty"`
+	QueueId       string                 `protobuf:"bytes,7,opt,name=queue_id,json=queueId,proto3" json:"queue_id,omitempty"`
+	Detail        string                 `protobuf:"bytes,8,opt,name=detail,proto3" json:"detail,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,10,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	IpHash        string                 `protobuf:"bytes,11,opt,name=ip_hash,json=ipHash,proto3" json:"ip_hash,omitempty"`
+	Prefetch      bool                   `protobuf:"varint,12,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+	Url           string                 `protobuf:"bytes,13,opt,name=url,proto3" json:"url,omitempty"`
+	Category      string                 `protobuf:"bytes,14,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailEvent) Reset() {
+	*x = EmailEvent{}
+	mi := &file_proto_email_email_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailEvent) ProtoMessage() {}
+
+func (x *EmailEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailEvent.ProtoReflect.Descriptor instead.
+func (*EmailEvent) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EmailEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetEmailId() string {
+	if x != nil {
+		return x.EmailId
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetSmtpCode() int32 {
+	if x != nil {
+		return x.SmtpCode
+	}
+	return 0
+}
+
+func (x *EmailEvent) GetQueueId() string {
+	if x != nil {
+		return x.QueueId
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *EmailEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetIpHash() string {
+	if x != nil {
+		return x.IpHash
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetPrefetch() bool {
+	if x != nil {
+		return x.Prefetch
+	}
+	return false
+}
+
+func (x *EmailEvent) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *EmailEvent) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+type FallbackPlan struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	EscalateAfterMinutes int32                  `protobuf:"varint,1,opt,name=escalate_after_minutes,json=escalateAfterMinutes,proto3" json:"escalate_after_minutes,omitempty"`
+	Steps                []*FallbackStep        `protobuf:"bytes,2,rep,name=steps,proto3" json:"steps,omitempty"`
+	State                string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	CurrentStep          int32                  `protobuf:"varint,4,opt,name=current_step,json=currentStep,proto3" json:"current_step,omitempty"`
+	Reason               string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	EscalateAt           *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=escalate_at,json=escalateAt,proto3" json:"escalate_at,omitempty"`
+	EscalatedAt          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=escalated_at,json=escalatedAt,proto3" json:"escalated_at,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *FallbackPlan) Reset() {
+	*x = FallbackPlan{}
+	mi := &file_proto_email_email_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FallbackPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FallbackPlan) ProtoMessage() {}
+
+func (x *FallbackPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FallbackPlan.ProtoReflect.Descriptor instead.
+func (*FallbackPlan) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FallbackPlan) GetEscalateAfterMinutes() int32 {
+	if x != nil {
+		return x.EscalateAfterMinutes
+	}
+	return 0
+}
+
+func (x *FallbackPlan) GetSteps() []*FallbackStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *FallbackPlan) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *FallbackPlan) GetCurrentStep() int32 {
+	if x != nil {
+		return x.CurrentStep
+	}
+	return 0
+}
+
+func (x *FallbackPlan) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FallbackPlan) GetEscalateAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EscalateAt
+	}
+	return nil
+}
+
+func (x *FallbackPlan) GetEscalatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EscalatedAt
+	}
+	return nil
+}
+
+type FallbackStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	To            []string               `protobuf:"bytes,2,rep,name=to,proto3" json:"to,omitempty"`
+	Webhook       string                 `protobuf:"bytes,3,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FallbackStep) Reset() {
+	*x = FallbackStep{}
+	mi := &file_proto_email_email_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FallbackStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FallbackStep) ProtoMessage() {}
+
+func (x *FallbackStep) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_email_email_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FallbackStep.ProtoReflect.Descriptor instead.
+func (*FallbackStep) Descriptor() ([]byte, []int) {
+	return file_proto_email_email_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FallbackStep) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *FallbackStep) GetTo() []string {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *FallbackStep) GetWebhook() string {
+	if x != nil {
+		return x.Webhook
+	}
+	return ""
+}
+
+func (x *FallbackStep) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FallbackStep) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FallbackStep) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+var File_proto_email_email_proto protoreflect.FileDescriptor
+
+const file_proto_email_email_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/email/email.proto\x12\x05email\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfe\x02\n" +
+	"\x10SendEmailRequest\x12\x0e\n" +
+	"\x02to\x18\x01 \x03(\tR\x02to\x12\x0e\n" +
+	"\x02cc\x18\x02 \x03(\tR\x02cc\x12\x10\n" +
+	"\x03bcc\x18\x03 \x03(\tR\x03bcc\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\x12\x17\n" +
+	"\ais_html\x18\x06 \x01(\bR\x06isHtml\x123\n" +
+	"\vattachments\x18\a \x03(\v2\x11.email.AttachmentR\vattachments\x12/\n" +
+	"\bfallback\x18\b \x01(\v2\x13.email.FallbackPlanR\bfallback\x12\x17\n" +
+	"\auser_id\x18\t \x01(\tR\x06userId\x12\x1a\n" +
+	"\bcategory\x18\n" +
+	" \x01(\tR\bcategory\x12\x1f\n" +
+	"\vtrack_opens\x18\v \x01(\bR\n" +
+	"trackOpens\x12!\n" +
+	"\ftrack_clicks\x18\f \x01(\bR\vtrackClicks\x12\x12\n" +
+	"\x04from\x18\r \x01(\tR\x04from\"e\n" +
+	"\n" +
+	"Attachment\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"\x90\x01\n" +
+	"\x11SendEmailResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"H\n" +
+	"\x15SendEmailBatchRequest\x12/\n" +
+	"\x06emails\x18\x01 \x03(\v2\x17.email.SendEmailRequestR\x06emails\"R\n" +
+	"\x14SendEmailBatchResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xba\x01\n" +
+	"\x16SendEmailBatchResponse\x12\x16\n" +
+	"\x06queued\x18\x01 \x01(\x05R\x06queued\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\x05R\x06failed\x125\n" +
+	"\aresults\x18\x03 \x03(\v2\x1b.email.SendEmailBatchResultR\aresults\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"!\n" +
+	"\x0fGetEmailRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xb9\x06\n" +
+	"\x05Email\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x0e\n" +
+	"\x02to\x18\x02 \x03(\tR\x02to\x12\x0e\n" +
+	"\x02cc\x18\x03 \x03(\tR\x02cc\x12\x10\n" +
+	"\x03bcc\x18\x04 \x03(\tR\x03bcc\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\x12\x17\n" +
+	"\ais_html\x18\x06 \x01(\bR\x06isHtml\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12#\n" +
+	"\rerror_message\x18\b \x01(\tR\ferrorMessage\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x123\n" +
+	"\asent_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12/\n" +
+	"\bfallback\x18\v \x01(\v2\x13.email.FallbackPlanR\bfallback\x12\x17\n" +
+	"\auser_id\x18\f \x01(\tR\x06userId\x12\x1a\n" +
+	"\bcategory\x18\r \x01(\tR\bcategory\x12:\n" +
+	"\n" +
+	"suppressed\x18\x0e \x03(\v2\x1a.email.SuppressedRecipientR\n" +
+	"suppressed\x12'\n" +
+	"\abounces\x18\x0f \x03(\v2\r.email.BounceR\abounces\x120\n" +
+	"\n" +
+	"complaints\x18\x10 \x03(\v2\x10.email.ComplaintR\n" +
+	"complaints\x125\n" +
+	"\n" +
+	"recipients\x18\x11 \x03(\v2\x15.email.EmailRecipientR\n" +
+	"recipients\x129\n" +
+	"\n" +
+	"updated_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\vtrack_opens\x18\x13 \x01(\bR\n" +
+	"trackOpens\x12!\n" +
+	"\ftrack_clicks\x18\x14 \x01(\bR\vtrackClicks\x12/\n" +
+	"\n" +
+	"created_by\x18\x15 \x01(\v2\x10.email.PrincipalR\tcreatedBy\x12\x16\n" +
+	"\x06tenant\x18\x16 \x01(\tR\x06tenant\x12\x12\n" +
+	"\x04from\x18\x17 \x01(\tR\x04from\"[\n" +
+	"\tPrincipal\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06tenant\x18\x04 \x01(\tR\x06tenant\"\xc7\x05\n" +
+	"\x0eEmailRecipient\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1b\n" +
+	"\tsmtp_code\x18\x04 \x01(\x05R\bsmtpCode\x12#\n" +
+	"\rsmtp_response\x18\x05 \x01(\tR\fsmtpResponse\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x123\n" +
+	"\asent_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12%\n" +
+	"\x06bounce\x18\t \x01(\v2\r.email.BounceR\x06bounce\x12\x1d\n" +
+	"\n" +
+	"open_count\x18\n" +
+	" \x01(\x05R\topenCount\x12B\n" +
+	"\x0ffirst_opened_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rfirstOpenedAt\x12@\n" +
+	"\x0elast_opened_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\flastOpenedAt\x12\x1f\n" +
+	"\vclick_count\x18\r \x01(\x05R\n" +
+	"clickCount\x12D\n" +
+	"\x10first_clicked_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x0efirstClickedAt\x12B\n" +
+	"\x0flast_clicked_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\rlastClickedAt\x12\x16\n" +
+	"\x06server\x18\x10 \x01(\tR\x06server\x12\x16\n" +
+	"\x06sender\x18\x11 \x01(\tR\x06sender\"G\n" +
+	"\x13SuppressedRecipient\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xd6\x01\n" +
+	"\x06Bounce\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12'\n" +
+	"\x0fdiagnostic_code\x18\x04 \x01(\tR\x0ediagnosticCode\x12\x1c\n" +
+	"\tpermanent\x18\x05 \x01(\bR\tpermanent\x12;\n" +
+	"\vreported_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"reportedAt\"\xa6\x01\n" +
+	"\tComplaint\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12#\n" +
+	"\rfeedback_type\x18\x02 \x01(\tR\ffeedbackType\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x03 \x01(\tR\tuserAgent\x12;\n" +
+	"\vreported_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"reportedAt\"#\n" +
+	"\x11WatchEmailRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"e\n" +
+	"\x12WatchEmailsRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"(\n" +
+	"\x16ListEmailEventsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
+	"\x17ListEmailEventsResponse\x12)\n" +
+	"\x06events\x18\x01 \x03(\v2\x11.email.EmailEventR\x06events\"\x8e\x03\n" +
+	"\n" +
+	"EmailEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bemail_id\x18\x02 \x01(\tR\aemailId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1c\n" +
+	"\trecipient\x18\x04 \x01(\tR\trecipient\x12\x16\n" +
+	"\x06server\x18\x05 \x01(\tR\x06server\x12\x1b\n" +
+	"\tsmtp_code\x18\x06 \x01(\x05R\bsmtpCode\x12\x19\n" +
+	"\bqueue_id\x18\a \x01(\tR\aqueueId\x12\x16\n" +
+	"\x06detail\x18\b \x01(\tR\x06detail\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\n" +
+	" \x01(\tR\tuserAgent\x12\x17\n" +
+	"\aip_hash\x18\v \x01(\tR\x06ipHash\x12\x1a\n" +
+	"\bprefetch\x18\f \x01(\bR\bprefetch\x12\x10\n" +
+	"\x03url\x18\r \x01(\tR\x03url\x12\x1a\n" +
+	"\bcategory\x18\x0e \x01(\tR\bcategory\"\xbc\x02\n" +
+	"\fFallbackPlan\x124\n" +
+	"\x16escalate_after_minutes\x18\x01 \x01(\x05R\x14escalateAfterMinutes\x12)\n" +
+	"\x05steps\x18\x02 \x03(\v2\x13.email.FallbackStepR\x05steps\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12!\n" +
+	"\fcurrent_step\x18\x04 \x01(\x05R\vcurrentStep\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12;\n" +
+	"\vescalate_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"escalateAt\x12=\n" +
+	"\fescalated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vescalatedAt\"\xbd\x01\n" +
+	"\fFallbackStep\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x0e\n" +
+	"\x02to\x18\x02 \x03(\tR\x02to\x12\x18\n" +
+	"\awebhook\x18\x03 \x01(\tR\awebhook\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12=\n" +
+	"\fcompleted_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt2\x93\x03\n" +
+	"\fEmailService\x12>\n" +
+	"\tSendEmail\x12\x17.email.SendEmailRequest\x1a\x18.email.SendEmailResponse\x12M\n" +
+	"\x0eSendEmailBatch\x12\x1c.email.SendEmailBatchRequest\x1a\x1d.email.SendEmailBatchResponse\x120\n" +
+	"\bGetEmail\x12\x16.email.GetEmailRequest\x1a\f.email.Email\x12P\n" +
+	"\x0fListEmailEvents\x12\x1d.email.ListEmailEventsRequest\x1a\x1e.email.ListEmailEventsResponse\x126\n" +
+	"\n" +
+	"WatchEmail\x12\x18.email.WatchEmailRequest\x1a\f.email.Email0\x01\x128\n" +
+	"\vWatchEmails\x12\x19.email.WatchEmailsRequest\x1a\f.email.Email0\x01B,Z*github.com/aarondever/notiflow/proto/emailb\x06proto3"
 
 var (
 	file_proto_email_email_proto_rawDescOnce sync.Once
@@ -281,23 +1878,78 @@ func file_proto_email_email_proto_rawDescGZIP() []byte {
 	return file_proto_email_email_proto_rawDescData
 }
 
-var file_proto_email_email_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_email_email_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_email_email_proto_goTypes = []any{
-	(*SendEmailRequest)(nil),      // 0: email.SendEmailRequest
-	(*Attachment)(nil),            // 1: email.Attachment
-	(*SendEmailResponse)(nil),     // 2: email.SendEmailResponse
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*SendEmailRequest)(nil),        // 0: email.SendEmailRequest
+	(*Attachment)(nil),              // 1: email.Attachment
+	(*SendEmailResponse)(nil),       // 2: email.SendEmailResponse
+	(*SendEmailBatchRequest)(nil),   // 3: email.SendEmailBatchRequest
+	(*SendEmailBatchResult)(nil),    // 4: email.SendEmailBatchResult
+	(*SendEmailBatchResponse)(nil),  // 5: email.SendEmailBatchResponse
+	(*GetEmailRequest)(nil),         // 6: email.GetEmailRequest
+	(*Email)(nil),                   // 7: email.Email
+	(*Principal)(nil),               // 8: email.Principal
+	(*EmailRecipient)(nil),          // 9: email.EmailRecipient
+	(*SuppressedRecipient)(nil),     // 10: email.SuppressedRecipient
+	(*Bounce)(nil),                  // 11: email.Bounce
+	(*Complaint)(nil),               // 12: email.Complaint
+	(*WatchEmailRequest)(nil),       // 13: email.WatchEmailRequest
+	(*WatchEmailsRequest)(nil),      // 14: email.WatchEmailsRequest
+	(*ListEmailEventsRequest)(nil),  // 15: email.ListEmailEventsRequest
+	(*ListEmailEventsResponse)(nil), // 16: email.ListEmailEventsResponse
+	(*EmailEvent)(nil),              // 17: email.EmailEvent
+	(*FallbackPlan)(nil),            // 18: email.FallbackPlan
+	(*FallbackStep)(nil),            // 19: email.FallbackStep
+	(*timestamppb.Timestamp)(nil),   // 20: google.protobuf.Timestamp
 }
 var file_proto_email_email_proto_depIdxs = []int32{
-	1, // 0: email.SendEmailRequest.attachments:type_name -> email.Attachment
-	3, // 1: email.SendEmailResponse.created_at:type_name -> google.protobuf.Timestamp
-	0, // 2: email.EmailService.SendEmail:input_type -> email.SendEmailRequest
-	2, // 3: email.EmailService.SendEmail:output_type -> email.SendEmailResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	1,  // 0: email.SendEmailRequest.attachments:type_name -> email.Attachment
+	18, // 1: email.SendEmailRequest.fallback:type_name -> email.FallbackPlan
+	20, // 2: email.SendEmailResponse.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: email.SendEmailBatchRequest.emails:type_name -> email.SendEmailRequest
+	4,  // 4: email.SendEmailBatchResponse.results:type_name -> email.SendEmailBatchResult
+	20, // 5: email.SendEmailBatchResponse.created_at:type_name -> google.protobuf.Timestamp
+	20, // 6: email.Email.created_at:type_name -> google.protobuf.Timestamp
+	20, // 7: email.Email.sent_at:type_name -> google.protobuf.Timestamp
+	18, // 8: email.Email.fallback:type_name -> email.FallbackPlan
+	10, // 9: email.Email.suppressed:type_name -> email.SuppressedRecipient
+	11, // 10: email.Email.bounces:type_name -> email.Bounce
+	12, // 11: email.Email.complaints:type_name -> email.Complaint
+	9,  // 12: email.Email.recipients:type_name -> email.EmailRecipient
+	20, // 13: email.Email.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 14: email.Email.created_by:type_name -> email.Principal
+	20, // 15: email.EmailRecipient.sent_at:type_name -> google.protobuf.Timestamp
+	20, // 16: email.EmailRecipient.updated_at:type_name -> google.protobuf.Timestamp
+	11, // 17: email.EmailRecipient.bounce:type_name -> email.Bounce
+	20, // 18: email.EmailRecipient.first_opened_at:type_name -> google.protobuf.Timestamp
+	20, // 19: email.EmailRecipient.last_opened_at:type_name -> google.protobuf.Timestamp
+	20, // 20: email.EmailRecipient.first_clicked_at:type_name -> google.protobuf.Timestamp
+	20, // 21: email.EmailRecipient.last_clicked_at:type_name -> google.protobuf.Timestamp
+	20, // 22: email.Bounce.reported_at:type_name -> google.protobuf.Timestamp
+	20, // 23: email.Complaint.reported_at:type_name -> google.protobuf.Timestamp
+	17, // 24: email.ListEmailEventsResponse.events:type_name -> email.EmailEvent
+	20, // 25: email.EmailEvent.created_at:type_name -> google.protobuf.Timestamp
+	19, // 26: email.FallbackPlan.steps:type_name -> email.FallbackStep
+	20, // 27: email.FallbackPlan.escalate_at:type_name -> google.protobuf.Timestamp
+	20, // 28: email.FallbackPlan.escalated_at:type_name -> google.protobuf.Timestamp
+	20, // 29: email.FallbackStep.completed_at:type_name -> google.protobuf.Timestamp
+	0,  // 30: email.EmailService.SendEmail:input_type -> email.SendEmailRequest
+	3,  // 31: email.EmailService.SendEmailBatch:input_type -> email.SendEmailBatchRequest
+	6,  // 32: email.EmailService.GetEmail:input_type -> email.GetEmailRequest
+	15, // 33: email.EmailService.ListEmailEvents:input_type -> email.ListEmailEventsRequest
+	13, // 34: email.EmailService.WatchEmail:input_type -> email.WatchEmailRequest
+	14, // 35: email.EmailService.WatchEmails:input_type -> email.WatchEmailsRequest
+	2,  // 36: email.EmailService.SendEmail:output_type -> email.SendEmailResponse
+	5,  // 37: email.EmailService.SendEmailBatch:output_type -> email.SendEmailBatchResponse
+	7,  // 38: email.EmailService.GetEmail:output_type -> email.Email
+	16, // 39: email.EmailService.ListEmailEvents:output_type -> email.ListEmailEventsResponse
+	7,  // 40: email.EmailService.WatchEmail:output_type -> email.Email
+	7,  // 41: email.EmailService.WatchEmails:output_type -> email.Email
+	36, // [36:42] is the sub-list for method output_type
+	30, // [30:36] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_proto_email_email_proto_init() }
@@ -311,7 +1963,7 @@ func file_proto_email_email_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_email_email_proto_rawDesc), len(file_proto_email_email_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
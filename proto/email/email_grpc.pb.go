@@ -19,7 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	EmailService_SendEmail_FullMethodName = "/email.EmailService/SendEmail"
+	EmailService_SendEmail_FullMethodName       = "/email.EmailService/SendEmail"
+	EmailService_SendEmailBatch_FullMethodName  = "/email.EmailService/SendEmailBatch"
+	EmailService_GetEmail_FullMethodName        = "/email.EmailService/GetEmail"
+	EmailService_ListEmailEvents_FullMethodName = "/email.EmailService/ListEmailEvents"
+	EmailService_WatchEmail_FullMethodName      = "/email.EmailService/WatchEmail"
+	EmailService_WatchEmails_FullMethodName     = "/email.EmailService/WatchEmails"
 )
 
 // EmailServiceClient is the client API for EmailService service.
@@ -27,6 +32,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EmailServiceClient interface {
 	SendEmail(ctx context.Context, in *SendEmailRequest, opts ...grpc.CallOption) (*SendEmailResponse, error)
+	SendEmailBatch(ctx context.Context, in *SendEmailBatchRequest, opts ...grpc.CallOption) (*SendEmailBatchResponse, error)
+	GetEmail(ctx context.Context, in *GetEmailRequest, opts ...grpc.CallOption) (*Email, error)
+	ListEmailEvents(ctx context.Context, in *ListEmailEventsRequest, opts ...grpc.CallOption) (*ListEmailEventsResponse, error)
+	WatchEmail(ctx context.Context, in *WatchEmailRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Email], error)
+	WatchEmails(ctx context.Context, in *WatchEmailsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Email], error)
 }
 
 type emailServiceClient struct {
@@ -47,11 +57,84 @@ func (c *emailServiceClient) SendEmail(ctx context.Context, in *SendEmailRequest
 	return out, nil
 }
 
+func (c *emailServiceClient) SendEmailBatch(ctx context.Context, in *SendEmailBatchRequest, opts ...grpc.CallOption) (*SendEmailBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendEmailBatchResponse)
+	err := c.cc.Invoke(ctx, EmailService_SendEmailBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) GetEmail(ctx context.Context, in *GetEmailRequest, opts ...grpc.CallOption) (*Email, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Email)
+	err := c.cc.Invoke(ctx, EmailService_GetEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) ListEmailEvents(ctx context.Context, in *ListEmailEventsRequest, opts ...grpc.CallOption) (*ListEmailEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmailEventsResponse)
+	err := c.cc.Invoke(ctx, EmailService_ListEmailEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailServiceClient) WatchEmail(ctx context.Context, in *WatchEmailRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Email], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EmailService_ServiceDesc.Streams[0], EmailService_WatchEmail_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEmailRequest, Email]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EmailService_WatchEmailClient = grpc.ServerStreamingClient[Email]
+
+func (c *emailServiceClient) WatchEmails(ctx context.Context, in *WatchEmailsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Email], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EmailService_ServiceDesc.Streams[1], EmailService_WatchEmails_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEmailsRequest, Email]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EmailService_WatchEmailsClient = grpc.ServerStreamingClient[Email]
+
 // EmailServiceServer is the server API for EmailService service.
 // All implementations must embed UnimplementedEmailServiceServer
 // for forward compatibility.
 type EmailServiceServer interface {
 	SendEmail(context.Context, *SendEmailRequest) (*SendEmailResponse, error)
+	SendEmailBatch(context.Context, *SendEmailBatchRequest) (*SendEmailBatchResponse, error)
+	GetEmail(context.Context, *GetEmailRequest) (*Email, error)
+	ListEmailEvents(context.Context, *ListEmailEventsRequest) (*ListEmailEventsResponse, error)
+	WatchEmail(*WatchEmailRequest, grpc.ServerStreamingServer[Email]) error
+	WatchEmails(*WatchEmailsRequest, grpc.ServerStreamingServer[Email]) error
 	mustEmbedUnimplementedEmailServiceServer()
 }
 
@@ -65,6 +148,21 @@ type UnimplementedEmailServiceServer struct{}
 func (UnimplementedEmailServiceServer) SendEmail(context.Context, *SendEmailRequest) (*SendEmailResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendEmail not implemented")
 }
+func (UnimplementedEmailServiceServer) SendEmailBatch(context.Context, *SendEmailBatchRequest) (*SendEmailBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendEmailBatch not implemented")
+}
+func (UnimplementedEmailServiceServer) GetEmail(context.Context, *GetEmailRequest) (*Email, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmail not implemented")
+}
+func (UnimplementedEmailServiceServer) ListEmailEvents(context.Context, *ListEmailEventsRequest) (*ListEmailEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmailEvents not implemented")
+}
+func (UnimplementedEmailServiceServer) WatchEmail(*WatchEmailRequest, grpc.ServerStreamingServer[Email]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEmail not implemented")
+}
+func (UnimplementedEmailServiceServer) WatchEmails(*WatchEmailsRequest, grpc.ServerStreamingServer[Email]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEmails not implemented")
+}
 func (UnimplementedEmailServiceServer) mustEmbedUnimplementedEmailServiceServer() {}
 func (UnimplementedEmailServiceServer) testEmbeddedByValue()                      {}
 
@@ -104,6 +202,82 @@ func _EmailService_SendEmail_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EmailService_SendEmailBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendEmailBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).SendEmailBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailService_SendEmailBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).SendEmailBatch(ctx, req.(*SendEmailBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_GetEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).GetEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailService_GetEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).GetEmail(ctx, req.(*GetEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_ListEmailEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmailEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailServiceServer).ListEmailEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailService_ListEmailEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailServiceServer).ListEmailEvents(ctx, req.(*ListEmailEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailService_WatchEmail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEmailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EmailServiceServer).WatchEmail(m, &grpc.GenericServerStream[WatchEmailRequest, Email]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EmailService_WatchEmailServer = grpc.ServerStreamingServer[Email]
+
+func _EmailService_WatchEmails_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEmailsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EmailServiceServer).WatchEmails(m, &grpc.GenericServerStream[WatchEmailsRequest, Email]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EmailService_WatchEmailsServer = grpc.ServerStreamingServer[Email]
+
 // EmailService_ServiceDesc is the grpc.ServiceDesc for EmailService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +289,30 @@ var EmailService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendEmail",
 			Handler:    _EmailService_SendEmail_Handler,
 		},
+		{
+			MethodName: "SendEmailBatch",
+			Handler:    _EmailService_SendEmailBatch_Handler,
+		},
+		{
+			MethodName: "GetEmail",
+			Handler:    _EmailService_GetEmail_Handler,
+		},
+		{
+			MethodName: "ListEmailEvents",
+			Handler:    _EmailService_ListEmailEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEmail",
+			Handler:       _EmailService_WatchEmail_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEmails",
+			Handler:       _EmailService_WatchEmails_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/email/email.proto",
 }
@@ -0,0 +1,315 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: proto/inbox/inbox.proto
+
+package inbox
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	InboxService_CreateItem_FullMethodName  = "/inbox.InboxService/CreateItem"
+	InboxService_ListItems_FullMethodName   = "/inbox.InboxService/ListItems"
+	InboxService_MarkRead_FullMethodName    = "/inbox.InboxService/MarkRead"
+	InboxService_Archive_FullMethodName     = "/inbox.InboxService/Archive"
+	InboxService_CountUnread_FullMethodName = "/inbox.InboxService/CountUnread"
+	InboxService_StreamItems_FullMethodName = "/inbox.InboxService/StreamItems"
+)
+
+// InboxServiceClient is the client API for InboxService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InboxServiceClient interface {
+	CreateItem(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*InboxItem, error)
+	ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
+	MarkRead(ctx context.Context, in *MarkReadRequest, opts ...grpc.CallOption) (*InboxItem, error)
+	Archive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (*InboxItem, error)
+	CountUnread(ctx context.Context, in *CountUnreadRequest, opts ...grpc.CallOption) (*CountUnreadResponse, error)
+	StreamItems(ctx context.Context, in *StreamItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InboxItem], error)
+}
+
+type inboxServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInboxServiceClient(cc grpc.ClientConnInterface) InboxServiceClient {
+	return &inboxServiceClient{cc}
+}
+
+func (c *inboxServiceClient) CreateItem(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*InboxItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboxItem)
+	err := c.cc.Invoke(ctx, InboxService_CreateItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxServiceClient) ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListItemsResponse)
+	err := c.cc.Invoke(ctx, InboxService_ListItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxServiceClient) MarkRead(ctx context.Context, in *MarkReadRequest, opts ...grpc.CallOption) (*InboxItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboxItem)
+	err := c.cc.Invoke(ctx, InboxService_MarkRead_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxServiceClient) Archive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (*InboxItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboxItem)
+	err := c.cc.Invoke(ctx, InboxService_Archive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxServiceClient) CountUnread(ctx context.Context, in *CountUnreadRequest, opts ...grpc.CallOption) (*CountUnreadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountUnreadResponse)
+	err := c.cc.Invoke(ctx, InboxService_CountUnread_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxServiceClient) StreamItems(ctx context.Context, in *StreamItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InboxItem], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InboxService_ServiceDesc.Streams[0], InboxService_StreamItems_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamItemsRequest, InboxItem]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InboxService_StreamItemsClient = grpc.ServerStreamingClient[InboxItem]
+
+// InboxServiceServer is the server API for InboxService service.
+// All implementations must embed UnimplementedInboxServiceServer
+// for forward compatibility.
+type InboxServiceServer interface {
+	CreateItem(context.Context, *CreateItemRequest) (*InboxItem, error)
+	ListItems(context.Context, *ListItemsRequest) (*ListItemsResponse, error)
+	MarkRead(context.Context, *MarkReadRequest) (*InboxItem, error)
+	Archive(context.Context, *ArchiveRequest) (*InboxItem, error)
+	CountUnread(context.Context, *CountUnreadRequest) (*CountUnreadResponse, error)
+	StreamItems(*StreamItemsRequest, grpc.ServerStreamingServer[InboxItem]) error
+	mustEmbedUnimplementedInboxServiceServer()
+}
+
+// UnimplementedInboxServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInboxServiceServer struct{}
+
+func (UnimplementedInboxServiceServer) CreateItem(context.Context, *CreateItemRequest) (*InboxItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateItem not implemented")
+}
+func (UnimplementedInboxServiceServer) ListItems(context.Context, *ListItemsRequest) (*ListItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListItems not implemented")
+}
+func (UnimplementedInboxServiceServer) MarkRead(context.Context, *MarkReadRequest) (*InboxItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkRead not implemented")
+}
+func (UnimplementedInboxServiceServer) Archive(context.Context, *ArchiveRequest) (*InboxItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Archive not implemented")
+}
+func (UnimplementedInboxServiceServer) CountUnread(context.Context, *CountUnreadRequest) (*CountUnreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountUnread not implemented")
+}
+func (UnimplementedInboxServiceServer) StreamItems(*StreamItemsRequest, grpc.ServerStreamingServer[InboxItem]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamItems not implemented")
+}
+func (UnimplementedInboxServiceServer) mustEmbedUnimplementedInboxServiceServer() {}
+func (UnimplementedInboxServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeInboxServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InboxServiceServer will
+// result in compilation errors.
+type UnsafeInboxServiceServer interface {
+	mustEmbedUnimplementedInboxServiceServer()
+}
+
+func RegisterInboxServiceServer(s grpc.ServiceRegistrar, srv InboxServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInboxServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InboxService_ServiceDesc, srv)
+}
+
+func _InboxService_CreateItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxServiceServer).CreateItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxService_CreateItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxServiceServer).CreateItem(ctx, req.(*CreateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxService_ListItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxServiceServer).ListItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxService_ListItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxServiceServer).ListItems(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxService_MarkRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxServiceServer).MarkRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxService_MarkRead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxServiceServer).MarkRead(ctx, req.(*MarkReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxService_Archive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxServiceServer).Archive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxService_Archive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxServiceServer).Archive(ctx, req.(*ArchiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxService_CountUnread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountUnreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxServiceServer).CountUnread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxService_CountUnread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxServiceServer).CountUnread(ctx, req.(*CountUnreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxService_StreamItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InboxServiceServer).StreamItems(m, &grpc.GenericServerStream[StreamItemsRequest, InboxItem]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InboxService_StreamItemsServer = grpc.ServerStreamingServer[InboxItem]
+
+// InboxService_ServiceDesc is the grpc.ServiceDesc for InboxService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InboxService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "inbox.InboxService",
+	HandlerType: (*InboxServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateItem",
+			Handler:    _InboxService_CreateItem_Handler,
+		},
+		{
+			MethodName: "ListItems",
+			Handler:    _InboxService_ListItems_Handler,
+		},
+		{
+			MethodName: "MarkRead",
+			Handler:    _InboxService_MarkRead_Handler,
+		},
+		{
+			MethodName: "Archive",
+			Handler:    _InboxService_Archive_Handler,
+		},
+		{
+			MethodName: "CountUnread",
+			Handler:    _InboxService_CountUnread_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamItems",
+			Handler:       _InboxService_StreamItems_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/inbox/inbox.proto",
+}
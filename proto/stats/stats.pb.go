@@ -0,0 +1,441 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v3.21.12
+// source: proto/stats/stats.proto
+
+package stats
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EmailStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupBy       string                 `protobuf:"bytes,1,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Server        string                 `protobuf:"bytes,5,opt,name=server,proto3" json:"server,omitempty"`
+	Sender        string                 `protobuf:"bytes,6,opt,name=sender,proto3" json:"sender,omitempty"`
+	Domain        string                 `protobuf:"bytes,7,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailStatsRequest) Reset() {
+	*x = EmailStatsRequest{}
+	mi := &file_proto_stats_stats_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailStatsRequest) ProtoMessage() {}
+
+func (x *EmailStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_stats_stats_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailStatsRequest.ProtoReflect.Descriptor instead.
+func (*EmailStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_stats_stats_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EmailStatsRequest) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *EmailStatsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *EmailStatsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *EmailStatsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *EmailStatsRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *EmailStatsRequest) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *EmailStatsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type EmailStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupBy       string                 `protobuf:"bytes,1,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	Totals        *StatsGroup            `protobuf:"bytes,4,opt,name=totals,proto3" json:"totals,omitempty"`
+	Groups        []*StatsGroup          `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailStatsResponse) Reset() {
+	*x = EmailStatsResponse{}
+	mi := &file_proto_stats_stats_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailStatsResponse) ProtoMessage() {}
+
+func (x *EmailStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_stats_stats_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailStatsResponse.ProtoReflect.Descriptor instead.
+func (*EmailStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_stats_stats_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EmailStatsResponse) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *EmailStatsResponse) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *EmailStatsResponse) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *EmailStatsResponse) GetTotals() *StatsGroup {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+func (x *EmailStatsResponse) GetGroups() []*StatsGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type StatsGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Pending       int32                  `protobuf:"varint,2,opt,name=pending,proto3" json:"pending,omitempty"`
+	Sent          int32                  `protobuf:"varint,3,opt,name=sent,proto3" json:"sent,omitempty"`
+	Failed        int32                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	Bounced       int32                  `protobuf:"varint,5,opt,name=bounced,proto3" json:"bounced,omitempty"`
+	Suppressed    int32                  `protobuf:"varint,6,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
+	Opened        int32                  `protobuf:"varint,7,opt,name=opened,proto3" json:"opened,omitempty"`
+	Clicked       int32                  `protobuf:"varint,8,opt,name=clicked,proto3" json:"clicked,omitempty"`
+	OpenTracked   int32                  `protobuf:"varint,9,opt,name=open_tracked,json=openTracked,proto3" json:"open_tracked,omitempty"`
+	ClickTracked  int32                  `protobuf:"varint,10,opt,name=click_tracked,json=clickTracked,proto3" json:"click_tracked,omitempty"`
+	DeliveryRate  float64                `protobuf:"fixed64,11,opt,name=delivery_rate,json=deliveryRate,proto3" json:"delivery_rate,omitempty"`
+	BounceRate    float64                `protobuf:"fixed64,12,opt,name=bounce_rate,json=bounceRate,proto3" json:"bounce_rate,omitempty"`
+	OpenRate      float64                `protobuf:"fixed64,13,opt,name=open_rate,json=openRate,proto3" json:"open_rate,omitempty"`
+	ClickRate     float64                `protobuf:"fixed64,14,opt,name=click_rate,json=clickRate,proto3" json:"click_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsGroup) Reset() {
+	*x = StatsGroup{}
+	mi := &file_proto_stats_stats_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsGroup) ProtoMessage() {}
+
+func (x *StatsGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_stats_stats_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsGroup.ProtoReflect.Descriptor instead.
+func (*StatsGroup) Descriptor() ([]byte, []int) {
+	return file_proto_stats_stats_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StatsGroup) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *StatsGroup) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetSent() int32 {
+	if x != nil {
+		return x.Sent
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetBounced() int32 {
+	if x != nil {
+		return x.Bounced
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetSuppressed() int32 {
+	if x != nil {
+		return x.Suppressed
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetOpened() int32 {
+	if x != nil {
+		return x.Opened
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetClicked() int32 {
+	if x != nil {
+		return x.Clicked
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetOpenTracked() int32 {
+	if x != nil {
+		return x.OpenTracked
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetClickTracked() int32 {
+	if x != nil {
+		return x.ClickTracked
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetDeliveryRate() float64 {
+	if x != nil {
+		return x.DeliveryRate
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetBounceRate() float64 {
+	if x != nil {
+		return x.BounceRate
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetOpenRate() float64 {
+	if x != nil {
+		return x.OpenRate
+	}
+	return 0
+}
+
+func (x *StatsGroup) GetClickRate() float64 {
+	if x != nil {
+		return x.ClickRate
+	}
+	return 0
+}
+
+var File_proto_stats_stats_proto protoreflect.FileDescriptor
+
+const file_proto_stats_stats_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/stats/stats.proto\x12\x05stats\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf6\x01\n" +
+	"\x11EmailStatsRequest\x12\x19\n" +
+	"\bgroup_by\x18\x01 \x01(\tR\agroupBy\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x16\n" +
+	"\x06server\x18\x05 \x01(\tR\x06server\x12\x16\n" +
+	"\x06sender\x18\x06 \x01(\tR\x06sender\x12\x16\n" +
+	"\x06domain\x18\a \x01(\tR\x06domain\"\xe9\x01\n" +
+	"\x12EmailStatsResponse\x12\x19\n" +
+	"\bgroup_by\x18\x01 \x01(\tR\agroupBy\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12)\n" +
+	"\x06totals\x18\x04 \x01(\v2\x11.stats.StatsGroupR\x06totals\x12)\n" +
+	"\x06groups\x18\x05 \x03(\v2\x11.stats.StatsGroupR\x06groups\"\x9a\x03\n" +
+	"\n" +
+	"StatsGroup\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\apending\x18\x02 \x01(\x05R\apending\x12\x12\n" +
+	"\x04sent\x18\x03 \x01(\x05R\x04sent\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\x12\x18\n" +
+	"\abounced\x18\x05 \x01(\x05R\abounced\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\x06 \x01(\x05R\n" +
+	"suppressed\x12\x16\n" +
+	"\x06opened\x18\a \x01(\x05R\x06opened\x12\x18\n" +
+	"\aclicked\x18\b \x01(\x05R\aclicked\x12!\n" +
+	"\fopen_tracked\x18\t \x01(\x05R\vopenTracked\x12#\n" +
+	"\rclick_tracked\x18\n" +
+	" \x01(\x05R\fclickTracked\x12#\n" +
+	"\rdelivery_rate\x18\v \x01(\x01R\fdeliveryRate\x12\x1f\n" +
+	"\vbounce_rate\x18\f \x01(\x01R\n" +
+	"bounceRate\x12\x1b\n" +
+	"\topen_rate\x18\r \x01(\x01R\bopenRate\x12\x1d\n" +
+	"\n" +
+	"click_rate\x18\x0e \x01(\x01R\tclickRate2T\n" +
+	"\fStatsService\x12D\n" +
+	"\rGetEmailStats\x12\x18.stats.EmailStatsRequest\x1a\x19.stats.EmailStatsResponseB,Z*github.com/aarondever/notiflow/proto/statsb\x06proto3"
+
+var (
+	file_proto_stats_stats_proto_rawDescOnce sync.Once
+	file_proto_stats_stats_proto_rawDescData []byte
+)
+
+func file_proto_stats_stats_proto_rawDescGZIP() []byte {
+	file_proto_stats_stats_proto_rawDescOnce.Do(func() {
+		file_proto_stats_stats_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_stats_stats_proto_rawDesc), len(file_proto_stats_stats_proto_rawDesc)))
+	})
+	return file_proto_stats_stats_proto_rawDescData
+}
+
+var file_proto_stats_stats_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_stats_stats_proto_goTypes = []any{
+	(*EmailStatsRequest)(nil),     // 0: stats.EmailStatsRequest
+	(*EmailStatsResponse)(nil),    // 1: stats.EmailStatsResponse
+	(*StatsGroup)(nil),            // 2: stats.StatsGroup
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_proto_stats_stats_proto_depIdxs = []int32{
+	3, // 0: stats.EmailStatsRequest.since:type_name -> google.protobuf.Timestamp
+	3, // 1: stats.EmailStatsRequest.until:type_name -> google.protobuf.Timestamp
+	3, // 2: stats.EmailStatsResponse.since:type_name -> google.protobuf.Timestamp
+	3, // 3: stats.EmailStatsResponse.until:type_name -> google.protobuf.Timestamp
+	2, // 4: stats.EmailStatsResponse.totals:type_name -> stats.StatsGroup
+	2, // 5: stats.EmailStatsResponse.groups:type_name -> stats.StatsGroup
+	0, // 6: stats.StatsService.GetEmailStats:input_type -> stats.EmailStatsRequest
+	1, // 7: stats.StatsService.GetEmailStats:output_type -> stats.EmailStatsResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_proto_stats_stats_proto_init() }
+func file_proto_stats_stats_proto_init() {
+	if File_proto_stats_stats_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_stats_stats_proto_rawDesc), len(file_proto_stats_stats_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_stats_stats_proto_goTypes,
+		DependencyIndexes: file_proto_stats_stats_proto_depIdxs,
+		MessageInfos:      file_proto_stats_stats_proto_msgTypes,
+	}.Build()
+	File_proto_stats_stats_proto = out.File
+	file_proto_stats_stats_proto_goTypes = nil
+	file_proto_stats_stats_proto_depIdxs = nil
+}